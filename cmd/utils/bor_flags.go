@@ -18,7 +18,7 @@ var (
 	// HeimdallURLFlag flag for heimdall url
 	HeimdallURLFlag = &cli.StringFlag{
 		Name:  "bor.heimdall",
-		Usage: "URL of Heimdall service",
+		Usage: "Comma separated URLs of Heimdall services, used as fallback when gRPC is configured if set explicitly",
 		Value: "http://localhost:1317",
 	}
 
@@ -31,10 +31,35 @@ var (
 	// HeimdallgRPCAddressFlag flag for heimdall gRPC address
 	HeimdallgRPCAddressFlag = &cli.StringFlag{
 		Name:  "bor.heimdallgRPC",
-		Usage: "Address of Heimdall gRPC service",
+		Usage: "Comma separated addresses of Heimdall gRPC services",
 		Value: "",
 	}
 
+	// HeimdallQuorumFlag flag for the number of heimdall services that must agree
+	HeimdallQuorumFlag = &cli.IntFlag{
+		Name:  "bor.heimdallquorum",
+		Usage: "Number of Heimdall services that must agree on spans and milestones (0 or 1 to disable)",
+	}
+
+	// HeimdallArchiveFlag flag for the heimdall archive to serve heimdall data from
+	HeimdallArchiveFlag = &cli.StringFlag{
+		Name:  "bor.heimdallarchive",
+		Usage: "Path of a Heimdall archive (exported with bor heimdall export) to serve Heimdall data from, without connecting to Heimdall",
+		Value: "",
+	}
+
+	// HeimdallNoCacheFlag flag for not caching the heimdall responses
+	HeimdallNoCacheFlag = &cli.BoolFlag{
+		Name:  "bor.heimdallnocache",
+		Usage: "Don't cache the spans, checkpoints and state-sync events fetched from Heimdall in the chain database",
+	}
+
+	// HeimdallCacheSpansFlag flag for the number of spans kept in the heimdall cache
+	HeimdallCacheSpansFlag = &cli.Uint64Flag{
+		Name:  "bor.heimdallcachespans",
+		Usage: "Number of the latest spans kept in the Heimdall cache (0 = all)",
+	}
+
 	// RunHeimdallFlag flag for running heimdall internally from bor
 	RunHeimdallFlag = &cli.BoolFlag{
 		Name:  "bor.runheimdall",
@@ -58,6 +83,10 @@ var (
 		HeimdallURLFlag,
 		WithoutHeimdallFlag,
 		HeimdallgRPCAddressFlag,
+		HeimdallQuorumFlag,
+		HeimdallArchiveFlag,
+		HeimdallNoCacheFlag,
+		HeimdallCacheSpansFlag,
 		RunHeimdallFlag,
 		RunHeimdallArgsFlag,
 		UseHeimdallAppFlag,
@@ -66,9 +95,13 @@ var (
 
 // SetBorConfig sets bor config
 func SetBorConfig(ctx *cli.Context, cfg *eth.Config) {
-	cfg.HeimdallURL = ctx.String(HeimdallURLFlag.Name)
+	cfg.HeimdallURL = heimdallURL(ctx)
 	cfg.WithoutHeimdall = ctx.Bool(WithoutHeimdallFlag.Name)
 	cfg.HeimdallgRPCAddress = ctx.String(HeimdallgRPCAddressFlag.Name)
+	cfg.HeimdallQuorum = ctx.Int(HeimdallQuorumFlag.Name)
+	cfg.HeimdallArchive = ctx.String(HeimdallArchiveFlag.Name)
+	cfg.HeimdallNoCache = ctx.Bool(HeimdallNoCacheFlag.Name)
+	cfg.HeimdallCacheSpans = ctx.Uint64(HeimdallCacheSpansFlag.Name)
 	cfg.RunHeimdall = ctx.Bool(RunHeimdallFlag.Name)
 	cfg.RunHeimdallArgs = ctx.String(RunHeimdallArgsFlag.Name)
 	cfg.UseHeimdallApp = ctx.Bool(UseHeimdallAppFlag.Name)
@@ -101,3 +134,14 @@ func CreateBorEthereum(cfg *ethconfig.Config) *eth.Ethereum {
 
 	return ethereum
 }
+
+// heimdallURL returns the Heimdall HTTP URL. The default one is replaced by the
+// gRPC address if set, the HTTP URL only being kept alongside it when set
+// explicitly.
+func heimdallURL(ctx *cli.Context) string {
+	if ctx.String(HeimdallgRPCAddressFlag.Name) != "" && !ctx.IsSet(HeimdallURLFlag.Name) {
+		return ""
+	}
+
+	return ctx.String(HeimdallURLFlag.Name)
+}
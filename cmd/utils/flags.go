@@ -2307,9 +2307,13 @@ func MakeChain(ctx *cli.Context, stack *node.Node, readonly bool) (*core.BlockCh
 
 	configs := &ethconfig.Config{
 		Genesis:             gspec,
-		HeimdallURL:         ctx.String(HeimdallURLFlag.Name),
+		HeimdallURL:         heimdallURL(ctx),
 		WithoutHeimdall:     ctx.Bool(WithoutHeimdallFlag.Name),
 		HeimdallgRPCAddress: ctx.String(HeimdallgRPCAddressFlag.Name),
+		HeimdallQuorum:      ctx.Int(HeimdallQuorumFlag.Name),
+		HeimdallArchive:     ctx.String(HeimdallArchiveFlag.Name),
+		HeimdallNoCache:     ctx.Bool(HeimdallNoCacheFlag.Name),
+		HeimdallCacheSpans:  ctx.Uint64(HeimdallCacheSpansFlag.Name),
 		RunHeimdall:         ctx.Bool(RunHeimdallArgsFlag.Name),
 		RunHeimdallArgs:     ctx.String(RunHeimdallArgsFlag.Name),
 		UseHeimdallApp:      ctx.Bool(UseHeimdallAppFlag.Name),
@@ -31,6 +31,9 @@ type API struct {
 	chain         consensus.ChainHeaderReader
 	bor           *Bor
 	rootHashCache *lru.ARCCache
+
+	producerCache     *lru.ARCCache
+	producerCacheOnce sync.Once
 }
 
 // GetSnapshot retrieves the state snapshot at a given block.
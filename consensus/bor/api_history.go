@@ -0,0 +1,336 @@
+package bor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus"
+	"github.com/ethereum/go-ethereum/consensus/bor/heimdall/span"
+	"github.com/ethereum/go-ethereum/consensus/bor/valset"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+
+	lru "github.com/hashicorp/golang-lru"
+)
+
+const (
+	// maxValidatorStatsRange is the maximum number of blocks the validator
+	// stats can be computed over in a single request
+	maxValidatorStatsRange = 10000
+
+	// producerCacheSize is the number of blocks whose producer is cached
+	producerCacheSize = 4 * maxValidatorStatsRange
+
+	// maxSpanLookups is the maximum number of spans fetched to find the span
+	// of a block, covering span length changes
+	maxSpanLookups = 64
+)
+
+var (
+	errNoHeimdall      = errors.New("heimdall is not in use")
+	errFutureBlock     = errors.New("block in the future")
+	errInvalidRange    = errors.New("invalid block range")
+	errSpanNotLocated  = errors.New("span of block not located")
+	errRangeTooLarge   = fmt.Errorf("block range larger than %d blocks", maxValidatorStatsRange)
+	errNoValidatorDiff = errors.New("no validator set at block")
+)
+
+// ValidatorPowerChange is a change of voting power of a validator.
+type ValidatorPowerChange struct {
+	Address  common.Address `json:"address"`
+	OldPower int64          `json:"oldPower"`
+	NewPower int64          `json:"newPower"`
+}
+
+// ValidatorSetDiff is the change of the validator set between two blocks.
+type ValidatorSetDiff struct {
+	FromBlock uint64                  `json:"fromBlock"`
+	ToBlock   uint64                  `json:"toBlock"`
+	Added     []*valset.Validator     `json:"added"`
+	Removed   []*valset.Validator     `json:"removed"`
+	Changed   []*ValidatorPowerChange `json:"changed"`
+}
+
+// ValidatorStats are the blocks produced by a validator over a range of blocks.
+type ValidatorStats struct {
+	Address   common.Address `json:"address"`
+	InTurn    uint64         `json:"inTurn"`
+	OutOfTurn uint64         `json:"outOfTurn"`
+
+	// Successions is the number of blocks produced per backup succession
+	// number, 1 being the first backup of the in-turn producer.
+	Successions map[int]uint64 `json:"successions"`
+}
+
+// producer is the producer of a block and its succession number, 0 when in-turn,
+// along with the primary producer expected for the block.
+type producer struct {
+	address    common.Address
+	succession int
+	primary    common.Address
+}
+
+// GetSpan returns the span with the given id.
+func (api *API) GetSpan(ctx context.Context, id uint64) (*span.HeimdallSpan, error) {
+	if api.bor.HeimdallClient == nil {
+		return nil, errNoHeimdall
+	}
+
+	return api.bor.HeimdallClient.Span(ctx, id)
+}
+
+// GetSpanByBlock returns the span including the given block. Spans after the
+// first one sharing the same length, the span is located without walking all
+// the previous ones.
+func (api *API) GetSpanByBlock(ctx context.Context, number rpc.BlockNumber) (*span.HeimdallSpan, error) {
+	if api.bor.HeimdallClient == nil {
+		return nil, errNoHeimdall
+	}
+
+	header, err := api.headerByNumber(number)
+	if err != nil {
+		return nil, err
+	}
+
+	n := header.Number.Uint64()
+
+	first, err := api.bor.HeimdallClient.Span(ctx, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	if n <= first.EndBlock {
+		return first, nil
+	}
+
+	second, err := api.bor.HeimdallClient.Span(ctx, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	id := 1 + (n-second.StartBlock)/(second.EndBlock-second.StartBlock+1)
+
+	for i := 0; i < maxSpanLookups; i++ {
+		s, err := api.bor.HeimdallClient.Span(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+
+		switch {
+		case n < s.StartBlock:
+			id--
+		case n > s.EndBlock:
+			id++
+		default:
+			return s, nil
+		}
+	}
+
+	return nil, errSpanNotLocated
+}
+
+// GetValidatorSetDiff returns the validators added, removed and whose power
+// changed between the validator sets of the given blocks.
+func (api *API) GetValidatorSetDiff(fromBlock rpc.BlockNumber, toBlock rpc.BlockNumber) (*ValidatorSetDiff, error) {
+	from, err := api.headerByNumber(fromBlock)
+	if err != nil {
+		return nil, err
+	}
+
+	to, err := api.headerByNumber(toBlock)
+	if err != nil {
+		return nil, err
+	}
+
+	fromSnap, err := api.bor.snapshot(api.chain, from.Number.Uint64(), from.Hash(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	toSnap, err := api.bor.snapshot(api.chain, to.Number.Uint64(), to.Hash(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if fromSnap.ValidatorSet == nil || toSnap.ValidatorSet == nil {
+		return nil, errNoValidatorDiff
+	}
+
+	return diffValidatorSets(from.Number.Uint64(), fromSnap.ValidatorSet, to.Number.Uint64(), toSnap.ValidatorSet), nil
+}
+
+// diffValidatorSets compares two validator sets, sorting the changes by address.
+func diffValidatorSets(fromBlock uint64, from *valset.ValidatorSet, toBlock uint64, to *valset.ValidatorSet) *ValidatorSetDiff {
+	diff := &ValidatorSetDiff{
+		FromBlock: fromBlock,
+		ToBlock:   toBlock,
+		Added:     make([]*valset.Validator, 0),
+		Removed:   make([]*valset.Validator, 0),
+		Changed:   make([]*ValidatorPowerChange, 0),
+	}
+
+	previous := make(map[common.Address]*valset.Validator, len(from.Validators))
+	for _, v := range from.Validators {
+		previous[v.Address] = v
+	}
+
+	for _, v := range to.Validators {
+		old, ok := previous[v.Address]
+		if !ok {
+			diff.Added = append(diff.Added, v)
+			continue
+		}
+
+		if old.VotingPower != v.VotingPower {
+			diff.Changed = append(diff.Changed, &ValidatorPowerChange{Address: v.Address, OldPower: old.VotingPower, NewPower: v.VotingPower})
+		}
+
+		delete(previous, v.Address)
+	}
+
+	for _, v := range previous {
+		diff.Removed = append(diff.Removed, v)
+	}
+
+	sort.Slice(diff.Added, func(i, j int) bool {
+		return diff.Added[i].Address.Cmp(diff.Added[j].Address) < 0
+	})
+	sort.Slice(diff.Removed, func(i, j int) bool {
+		return diff.Removed[i].Address.Cmp(diff.Removed[j].Address) < 0
+	})
+	sort.Slice(diff.Changed, func(i, j int) bool {
+		return diff.Changed[i].Address.Cmp(diff.Changed[j].Address) < 0
+	})
+
+	return diff
+}
+
+// GetValidatorStats returns the number of blocks produced by every validator
+// in-turn and out-of-turn over the given range of blocks, along with their
+// backup successions.
+func (api *API) GetValidatorStats(fromBlock rpc.BlockNumber, toBlock rpc.BlockNumber) ([]*ValidatorStats, error) {
+	from, err := api.headerByNumber(fromBlock)
+	if err != nil {
+		return nil, err
+	}
+
+	to, err := api.headerByNumber(toBlock)
+	if err != nil {
+		return nil, err
+	}
+
+	start, end := from.Number.Uint64(), to.Number.Uint64()
+
+	switch {
+	case start == 0 || start > end:
+		return nil, errInvalidRange
+	case end-start+1 > maxValidatorStatsRange:
+		return nil, errRangeTooLarge
+	}
+
+	stats := make(map[common.Address]*ValidatorStats)
+
+	for number := start; number <= end; number++ {
+		header := api.chain.GetHeaderByNumber(number)
+		if header == nil {
+			return nil, errUnknownBlock
+		}
+
+		p, err := api.blockProducer(header)
+		if err != nil {
+			return nil, err
+		}
+
+		s, ok := stats[p.address]
+		if !ok {
+			s = &ValidatorStats{Address: p.address, Successions: make(map[int]uint64)}
+			stats[p.address] = s
+		}
+
+		if p.succession == 0 {
+			s.InTurn++
+		} else {
+			s.OutOfTurn++
+			s.Successions[p.succession]++
+		}
+	}
+
+	result := make([]*ValidatorStats, 0, len(stats))
+	for _, s := range stats {
+		result = append(result, s)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Address.Cmp(result[j].Address) < 0
+	})
+
+	return result, nil
+}
+
+// blockProducer returns the producer of the given block with its succession
+// number in the snapshot of the parent, caching them by block hash.
+func (api *API) blockProducer(header *types.Header) (*producer, error) {
+	api.producerCacheOnce.Do(func() {
+		api.producerCache, _ = lru.NewARC(producerCacheSize)
+	})
+
+	hash := header.Hash()
+
+	if p, ok := api.producerCache.Get(hash); ok {
+		return p.(*producer), nil
+	}
+
+	p, err := api.bor.blockProducer(api.chain, header)
+	if err != nil {
+		return nil, err
+	}
+
+	api.producerCache.Add(hash, p)
+
+	return p, nil
+}
+
+// blockProducer returns the producer of the given block with its succession
+// number in the snapshot of the parent, and the primary producer of the block.
+func (c *Bor) blockProducer(chain consensus.ChainHeaderReader, header *types.Header) (*producer, error) {
+	author, err := c.Author(header)
+	if err != nil {
+		return nil, err
+	}
+
+	snap, err := c.snapshot(chain, header.Number.Uint64()-1, header.ParentHash, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	succession, err := snap.GetSignerSuccessionNumber(author)
+	if err != nil {
+		return nil, err
+	}
+
+	return &producer{address: author, succession: succession, primary: snap.ValidatorSet.GetProposer().Address}, nil
+}
+
+// headerByNumber returns the header of the given block, the current one for
+// the latest block.
+func (api *API) headerByNumber(number rpc.BlockNumber) (*types.Header, error) {
+	current := api.chain.CurrentHeader()
+
+	if number == rpc.LatestBlockNumber || number == rpc.PendingBlockNumber {
+		return current, nil
+	}
+
+	if number < 0 || uint64(number) > current.Number.Uint64() {
+		return nil, errFutureBlock
+	}
+
+	header := api.chain.GetHeaderByNumber(uint64(number))
+	if header == nil {
+		return nil, errUnknownBlock
+	}
+
+	return header, nil
+}
@@ -0,0 +1,51 @@
+package bor
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/bor/valset"
+)
+
+func TestDiffValidatorSets(t *testing.T) {
+	t.Parallel()
+
+	var (
+		a = common.HexToAddress("0x1")
+		b = common.HexToAddress("0x2")
+		c = common.HexToAddress("0x3")
+		d = common.HexToAddress("0x4")
+	)
+
+	from := valset.NewValidatorSet([]*valset.Validator{valset.NewValidator(a, 10), valset.NewValidator(b, 10), valset.NewValidator(c, 10)})
+	to := valset.NewValidatorSet([]*valset.Validator{valset.NewValidator(a, 10), valset.NewValidator(c, 20), valset.NewValidator(d, 5)})
+
+	diff := diffValidatorSets(16, from, 6656, to)
+	require.Equal(t, uint64(16), diff.FromBlock)
+	require.Equal(t, uint64(6656), diff.ToBlock)
+
+	require.Len(t, diff.Added, 1)
+	require.Equal(t, d, diff.Added[0].Address)
+
+	require.Len(t, diff.Removed, 1)
+	require.Equal(t, b, diff.Removed[0].Address)
+
+	require.Equal(t, []*ValidatorPowerChange{{Address: c, OldPower: 10, NewPower: 20}}, diff.Changed)
+
+	diff = diffValidatorSets(16, from, 16, from)
+	require.Empty(t, diff.Added)
+	require.Empty(t, diff.Removed)
+	require.Empty(t, diff.Changed)
+
+	// The changes are sorted by address whatever the order of the sets
+	from = &valset.ValidatorSet{Validators: []*valset.Validator{valset.NewValidator(c, 10), valset.NewValidator(a, 10)}}
+	to = &valset.ValidatorSet{Validators: []*valset.Validator{valset.NewValidator(d, 5), valset.NewValidator(c, 20), valset.NewValidator(b, 5), valset.NewValidator(a, 20)}}
+
+	diff = diffValidatorSets(16, from, 6656, to)
+	require.Equal(t, b, diff.Added[0].Address)
+	require.Equal(t, d, diff.Added[1].Address)
+	require.Equal(t, a, diff.Changed[0].Address)
+	require.Equal(t, c, diff.Changed[1].Address)
+}
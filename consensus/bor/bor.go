@@ -217,6 +217,8 @@ type Bor struct {
 	recents    *lru.ARCCache // Snapshots for recent block to speed up reorgs
 	signatures *lru.ARCCache // Signatures of recent blocks to speed up mining
 
+	pruningSnapshots atomic.Bool // Whether the stored snapshots are being pruned
+
 	authorizedSigner atomic.Pointer[signer] // Ethereum address and sign function of the signing key
 
 	ethAPI                 api.Caller
@@ -627,6 +629,8 @@ func (c *Bor) snapshot(chain consensus.ChainHeaderReader, number uint64, hash co
 		}
 
 		log.Trace("Stored snapshot to disk", "number", snap.Number, "hash", snap.Hash)
+
+		c.pruneSnapshots()
 	}
 
 	return snap, err
@@ -1228,6 +1232,18 @@ func (c *Bor) CommitStates(
 	header *types.Header,
 	chain statefull.ChainContext,
 ) ([]*types.StateSyncData, error) {
+	stateSyncs, _, err := c.commitStates(ctx, state, header, chain)
+	return stateSyncs, err
+}
+
+// commitStates commits the state-sync events of the block, returning them with
+// the gas used by each of them.
+func (c *Bor) commitStates(
+	ctx context.Context,
+	state *state.StateDB,
+	header *types.Header,
+	chain statefull.ChainContext,
+) ([]*types.StateSyncData, []uint64, error) {
 	fetchStart := time.Now()
 	number := header.Number.Uint64()
 
@@ -1242,7 +1258,7 @@ func (c *Bor) CommitStates(
 		// Fetch the LastStateId from contract via current state instance
 		lastStateIDBig, err = c.GenesisContractsClient.LastStateId(state.Copy(), number-1, header.ParentHash)
 		if err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 
 		stateSyncDelay := c.config.CalculateStateSyncDelay(number)
@@ -1250,7 +1266,7 @@ func (c *Bor) CommitStates(
 	} else {
 		lastStateIDBig, err = c.GenesisContractsClient.LastStateId(nil, number-1, header.ParentHash)
 		if err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 
 		to = time.Unix(int64(chain.Chain.GetHeaderByNumber(number-c.config.CalculateSprint(number)).Time), 0)
@@ -1280,6 +1296,7 @@ func (c *Bor) CommitStates(
 	totalGas := 0 /// limit on gas for state sync per block
 	chainID := c.chainConfig.ChainID.String()
 	stateSyncs := make([]*types.StateSyncData, 0, len(eventRecords))
+	stateSyncGas := make([]uint64, 0, len(eventRecords))
 
 	var gasUsed uint64
 
@@ -1307,10 +1324,11 @@ func (c *Bor) CommitStates(
 		// https://github.com/maticnetwork/genesis-contracts/blob/master/contracts/StateReceiver.sol#L27
 		gasUsed, err = c.GenesisContractsClient.CommitState(eventRecord, state, header, chain)
 		if err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 
 		totalGas += int(gasUsed)
+		stateSyncGas = append(stateSyncGas, gasUsed)
 
 		lastStateID++
 	}
@@ -1319,7 +1337,7 @@ func (c *Bor) CommitStates(
 
 	log.Info("StateSyncData", "gas", totalGas, "number", number, "lastStateID", lastStateID, "total records", len(eventRecords), "fetch time", int(fetchTime.Milliseconds()), "process time", int(processTime.Milliseconds()))
 
-	return stateSyncs, nil
+	return stateSyncs, stateSyncGas, nil
 }
 
 func validateEventRecord(eventRecord *clerk.EventRecordWithTime, number uint64, to time.Time, lastStateID uint64, chainID string) error {
@@ -347,6 +347,8 @@ func Fetch[T any](ctx context.Context, request *Request) (*T, error) {
 
 	result := new(T)
 
+	CountAttempt(ctx)
+
 	body, err := internalFetchWithTimeout(ctx, request.client, request.url)
 	if err != nil {
 		return nil, err
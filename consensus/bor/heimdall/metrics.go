@@ -2,6 +2,7 @@ package heimdall
 
 import (
 	"context"
+	"sync/atomic"
 	"time"
 
 	"github.com/ethereum/go-ethereum/metrics"
@@ -10,6 +11,7 @@ import (
 type (
 	requestTypeKey struct{}
 	requestType    string
+	attemptsKey    struct{}
 
 	meter struct {
 		request map[bool]metrics.Meter // map[isSuccessful]metrics.Meter
@@ -38,6 +40,21 @@ func getRequestType(ctx context.Context) (requestType, bool) {
 	return reqType, ok
 }
 
+// WithAttempts returns a context counting the requests sent to Heimdall with it,
+// the retries included.
+func WithAttempts(ctx context.Context) (context.Context, *atomic.Int64) {
+	attempts := new(atomic.Int64)
+
+	return context.WithValue(ctx, attemptsKey{}, attempts), attempts
+}
+
+// CountAttempt counts a request sent to Heimdall with the given context.
+func CountAttempt(ctx context.Context) {
+	if attempts, ok := ctx.Value(attemptsKey{}).(*atomic.Int64); ok {
+		attempts.Add(1)
+	}
+}
+
 var (
 	requestMeters = map[requestType]meter{
 		stateSyncRequest: {
@@ -0,0 +1,248 @@
+// Package heimdallarchive implements the export of Heimdall data for a range of
+// blocks to a file, and a Heimdall client serving it without a live Heimdall.
+package heimdallarchive
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/ethereum/go-ethereum/consensus/bor"
+	"github.com/ethereum/go-ethereum/consensus/bor/clerk"
+	"github.com/ethereum/go-ethereum/consensus/bor/heimdall/checkpoint"
+	"github.com/ethereum/go-ethereum/consensus/bor/heimdall/milestone"
+	"github.com/ethereum/go-ethereum/consensus/bor/heimdall/span"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// Checkpoint is a checkpoint along with its number.
+type Checkpoint struct {
+	Number int64 `json:"number"`
+	checkpoint.Checkpoint
+}
+
+// Archive holds the Heimdall data needed to process a range of blocks.
+type Archive struct {
+	FromBlock uint64 `json:"from_block"`
+	ToBlock   uint64 `json:"to_block"`
+
+	Spans []*span.HeimdallSpan `json:"spans"`
+
+	// Events holds all the state-sync events with an id of at least
+	// EventsFromID recorded before EventsToTime.
+	EventsFromID uint64                       `json:"events_from_id"`
+	EventsToTime int64                        `json:"events_to_time"`
+	Events       []*clerk.EventRecordWithTime `json:"events"`
+
+	Checkpoints     []*Checkpoint `json:"checkpoints"`
+	CheckpointCount int64         `json:"checkpoint_count"`
+
+	// Milestones are only exposed by Heimdall as the latest one, which is
+	// the one at the time of the export.
+	Milestone          *milestone.Milestone `json:"milestone,omitempty"`
+	MilestoneCount     int64                `json:"milestone_count"`
+	LastNoAckMilestone string               `json:"last_no_ack_milestone"`
+}
+
+// ExportConfig is the range of data to export.
+type ExportConfig struct {
+	FromBlock uint64
+	ToBlock   uint64
+
+	// FromEventID is the id of the first state-sync event to export, the last
+	// state id committed before FromBlock plus one. It is required, as starting
+	// from the first event would export the whole state-sync history.
+	FromEventID uint64
+
+	// ToTime is the record time up to which state-sync events are exported. When
+	// zero, the timestamp of the checkpoint including ToBlock is used, or the
+	// current time when ToBlock is not checkpointed yet.
+	ToTime int64
+}
+
+// Export fetches from Heimdall the spans, checkpoints, state-sync events and
+// milestones needed to process the given range of blocks.
+func Export(ctx context.Context, client bor.IHeimdallClient, config ExportConfig) (*Archive, error) {
+	if config.FromBlock > config.ToBlock {
+		return nil, fmt.Errorf("invalid block range %d-%d", config.FromBlock, config.ToBlock)
+	}
+
+	if config.FromEventID == 0 {
+		return nil, errors.New("missing first state-sync event id")
+	}
+
+	archive := &Archive{
+		FromBlock:    config.FromBlock,
+		ToBlock:      config.ToBlock,
+		EventsFromID: config.FromEventID,
+	}
+
+	var err error
+
+	if archive.Spans, err = exportSpans(ctx, client, config.FromBlock, config.ToBlock); err != nil {
+		return nil, err
+	}
+
+	if archive.CheckpointCount, err = client.FetchCheckpointCount(ctx); err != nil {
+		return nil, err
+	}
+
+	if archive.Checkpoints, err = exportCheckpoints(ctx, client, archive.CheckpointCount, config.FromBlock, config.ToBlock); err != nil {
+		return nil, err
+	}
+
+	archive.EventsToTime = config.ToTime
+	if archive.EventsToTime == 0 {
+		archive.EventsToTime = time.Now().Unix()
+
+		if n := len(archive.Checkpoints); n > 0 && archive.Checkpoints[n-1].EndBlock.Uint64() >= config.ToBlock {
+			archive.EventsToTime = int64(archive.Checkpoints[n-1].Timestamp)
+		}
+	}
+
+	log.Info("Exporting state-sync events", "from", archive.EventsFromID, "to", time.Unix(archive.EventsToTime, 0))
+
+	if archive.Events, err = client.StateSyncEvents(ctx, archive.EventsFromID, archive.EventsToTime); err != nil {
+		return nil, err
+	}
+
+	if archive.MilestoneCount, err = client.FetchMilestoneCount(ctx); err != nil {
+		return nil, err
+	}
+
+	if archive.MilestoneCount > 0 {
+		if archive.Milestone, err = client.FetchMilestone(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	if archive.LastNoAckMilestone, err = client.FetchLastNoAckMilestone(ctx); err != nil {
+		return nil, err
+	}
+
+	return archive, nil
+}
+
+// exportSpans fetches the spans including the given range of blocks. Spans
+// other than the first one have the same length, which is used to locate the
+// span including the first block without walking all the previous ones.
+func exportSpans(ctx context.Context, client bor.IHeimdallClient, from uint64, to uint64) ([]*span.HeimdallSpan, error) {
+	first, err := client.Span(ctx, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	id := uint64(0)
+
+	if from > first.EndBlock {
+		second, err := client.Span(ctx, 1)
+		if err != nil {
+			return nil, err
+		}
+
+		length := second.EndBlock - second.StartBlock + 1
+		id = 1 + (from-second.StartBlock)/length
+	}
+
+	var spans []*span.HeimdallSpan
+
+	for {
+		log.Info("Exporting span", "id", id)
+
+		s, err := client.Span(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+
+		switch {
+		case s.StartBlock > from && id > 0 && len(spans) == 0:
+			// The span length changed before the first block, look back
+			id--
+			continue
+		case s.EndBlock < from:
+			id++
+			continue
+		}
+
+		spans = append(spans, s)
+
+		if s.EndBlock >= to {
+			return spans, nil
+		}
+
+		id++
+	}
+}
+
+// exportCheckpoints fetches the checkpoints including the given range of
+// blocks, searching for the first one by block number.
+func exportCheckpoints(ctx context.Context, client bor.IHeimdallClient, count int64, from uint64, to uint64) ([]*Checkpoint, error) {
+	// Find the first checkpoint ending at or after the first block
+	low, high := int64(1), count+1
+
+	for low < high {
+		mid := low + (high-low)/2
+
+		c, err := client.FetchCheckpoint(ctx, mid)
+		if err != nil {
+			return nil, err
+		}
+
+		if c.EndBlock.Uint64() < from {
+			low = mid + 1
+		} else {
+			high = mid
+		}
+	}
+
+	var checkpoints []*Checkpoint
+
+	for number := low; number <= count; number++ {
+		log.Info("Exporting checkpoint", "number", number)
+
+		c, err := client.FetchCheckpoint(ctx, number)
+		if err != nil {
+			return nil, err
+		}
+
+		if c.StartBlock.Uint64() > to {
+			break
+		}
+
+		checkpoints = append(checkpoints, &Checkpoint{Number: number, Checkpoint: *c})
+	}
+
+	return checkpoints, nil
+}
+
+// Write stores the archive in the file at the given path.
+func (a *Archive) Write(path string) error {
+	data, err := json.Marshal(a)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, data, 0600)
+}
+
+// Load reads the archive stored in the file at the given path.
+func Load(path string) (*Archive, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var archive Archive
+	if err := json.Unmarshal(data, &archive); err != nil {
+		return nil, fmt.Errorf("invalid heimdall archive %s: %w", path, err)
+	}
+
+	if len(archive.Spans) == 0 {
+		return nil, errors.New("heimdall archive without spans")
+	}
+
+	return &archive, nil
+}
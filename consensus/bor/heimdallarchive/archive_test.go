@@ -0,0 +1,153 @@
+package heimdallarchive
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/consensus/bor/clerk"
+	"github.com/ethereum/go-ethereum/consensus/bor/heimdall"
+	"github.com/ethereum/go-ethereum/consensus/bor/heimdall/checkpoint"
+	"github.com/ethereum/go-ethereum/consensus/bor/heimdall/milestone"
+	"github.com/ethereum/go-ethereum/consensus/bor/heimdall/span"
+
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	fakeSpanLength       = 100
+	fakeCheckpointLength = 50
+	fakeCheckpointCount  = 40
+)
+
+// fakeHeimdall serves spans of 256 blocks then fakeSpanLength blocks,
+// checkpoints of fakeCheckpointLength blocks stamped with their end block, and
+// one state-sync event per second.
+type fakeHeimdall struct {
+	spanCalls int
+}
+
+func (f *fakeHeimdall) StateSyncEvents(_ context.Context, fromID uint64, to int64) ([]*clerk.EventRecordWithTime, error) {
+	var records []*clerk.EventRecordWithTime
+
+	for id := fromID; int64(id) < to; id++ {
+		records = append(records, &clerk.EventRecordWithTime{EventRecord: clerk.EventRecord{ID: id}, Time: time.Unix(int64(id), 0)})
+	}
+
+	return records, nil
+}
+
+func (f *fakeHeimdall) Span(_ context.Context, spanID uint64) (*span.HeimdallSpan, error) {
+	f.spanCalls++
+
+	if spanID == 0 {
+		return &span.HeimdallSpan{Span: span.Span{ID: 0, StartBlock: 0, EndBlock: 255}}, nil
+	}
+
+	start := 256 + (spanID-1)*fakeSpanLength
+
+	return &span.HeimdallSpan{Span: span.Span{ID: spanID, StartBlock: start, EndBlock: start + fakeSpanLength - 1}}, nil
+}
+
+func (f *fakeHeimdall) FetchCheckpoint(_ context.Context, number int64) (*checkpoint.Checkpoint, error) {
+	if number < 1 || number > fakeCheckpointCount {
+		return nil, errors.New("unknown checkpoint")
+	}
+
+	start := (number - 1) * fakeCheckpointLength
+
+	return &checkpoint.Checkpoint{
+		StartBlock: big.NewInt(start),
+		EndBlock:   big.NewInt(start + fakeCheckpointLength - 1),
+		Timestamp:  uint64(start + fakeCheckpointLength - 1),
+	}, nil
+}
+
+func (f *fakeHeimdall) FetchCheckpointCount(context.Context) (int64, error) {
+	return fakeCheckpointCount, nil
+}
+
+func (f *fakeHeimdall) FetchMilestone(context.Context) (*milestone.Milestone, error) {
+	return &milestone.Milestone{StartBlock: big.NewInt(2000), EndBlock: big.NewInt(2010)}, nil
+}
+
+func (f *fakeHeimdall) FetchMilestoneCount(context.Context) (int64, error) {
+	return 7, nil
+}
+
+func (f *fakeHeimdall) FetchNoAckMilestone(context.Context, string) error {
+	return nil
+}
+
+func (f *fakeHeimdall) FetchLastNoAckMilestone(context.Context) (string, error) {
+	return "rejected", nil
+}
+
+func (f *fakeHeimdall) FetchMilestoneID(context.Context, string) error {
+	return nil
+}
+
+func (f *fakeHeimdall) Close() {}
+
+func TestExport(t *testing.T) {
+	t.Parallel()
+
+	heimdallClient := &fakeHeimdall{}
+
+	// The first state-sync event must be given
+	_, err := Export(context.Background(), heimdallClient, ExportConfig{FromBlock: 1000, ToBlock: 1210})
+	require.Error(t, err)
+
+	archive, err := Export(context.Background(), heimdallClient, ExportConfig{FromBlock: 1000, ToBlock: 1210, FromEventID: 900})
+	require.NoError(t, err)
+
+	// Spans 8 (956-1055) to 10 (1156-1255) are located without walking the previous ones
+	require.Len(t, archive.Spans, 3)
+	require.Equal(t, uint64(8), archive.Spans[0].ID)
+	require.Equal(t, uint64(10), archive.Spans[2].ID)
+	require.Equal(t, 5, heimdallClient.spanCalls)
+
+	// Checkpoints 21 (1000-1049) to 25 (1200-1249)
+	require.Len(t, archive.Checkpoints, 5)
+	require.Equal(t, int64(21), archive.Checkpoints[0].Number)
+	require.Equal(t, int64(25), archive.Checkpoints[4].Number)
+
+	// Events are exported until the timestamp of the last checkpoint
+	require.Equal(t, int64(1249), archive.EventsToTime)
+	require.Len(t, archive.Events, 1249-900)
+
+	path := filepath.Join(t.TempDir(), "heimdall.json")
+	require.NoError(t, archive.Write(path))
+
+	client, err := NewHeimdallArchiveClient(path)
+	require.NoError(t, err)
+
+	s, err := client.Span(context.Background(), 9)
+	require.NoError(t, err)
+	require.Equal(t, uint64(1056), s.StartBlock)
+
+	_, err = client.Span(context.Background(), 11)
+	require.ErrorIs(t, err, ErrNotInArchive)
+
+	events, err := client.StateSyncEvents(context.Background(), 1000, 1100)
+	require.NoError(t, err)
+	require.Len(t, events, 100)
+	require.Equal(t, uint64(1000), events[0].ID)
+
+	_, err = client.StateSyncEvents(context.Background(), 800, 1100)
+	require.ErrorIs(t, err, ErrNotInArchive)
+
+	cp, err := client.FetchCheckpoint(context.Background(), -1)
+	require.NoError(t, err)
+	require.Equal(t, int64(1249), cp.EndBlock.Int64())
+
+	m, err := client.FetchMilestone(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, int64(2010), m.EndBlock.Int64())
+
+	require.NoError(t, client.FetchNoAckMilestone(context.Background(), "rejected"))
+	require.ErrorIs(t, client.FetchNoAckMilestone(context.Background(), "other"), heimdall.ErrNotInRejectedList)
+}
@@ -0,0 +1,139 @@
+package heimdallarchive
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/ethereum/go-ethereum/consensus/bor"
+	"github.com/ethereum/go-ethereum/consensus/bor/clerk"
+	"github.com/ethereum/go-ethereum/consensus/bor/heimdall"
+	"github.com/ethereum/go-ethereum/consensus/bor/heimdall/checkpoint"
+	"github.com/ethereum/go-ethereum/consensus/bor/heimdall/milestone"
+	"github.com/ethereum/go-ethereum/consensus/bor/heimdall/span"
+)
+
+// ErrNotInArchive is returned for the data outside of the exported range.
+var ErrNotInArchive = errors.New("not in heimdall archive")
+
+var _ bor.IHeimdallClient = (*HeimdallArchiveClient)(nil)
+
+// HeimdallArchiveClient is a Heimdall client serving the data of an archive,
+// allowing to process the archived range of blocks offline.
+type HeimdallArchiveClient struct {
+	archive *Archive
+
+	spans       map[uint64]*span.HeimdallSpan
+	checkpoints map[int64]*checkpoint.Checkpoint
+}
+
+// NewHeimdallArchiveClient loads the archive stored at the given path.
+func NewHeimdallArchiveClient(path string) (*HeimdallArchiveClient, error) {
+	archive, err := Load(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return newHeimdallArchiveClient(archive), nil
+}
+
+func newHeimdallArchiveClient(archive *Archive) *HeimdallArchiveClient {
+	c := &HeimdallArchiveClient{
+		archive:     archive,
+		spans:       make(map[uint64]*span.HeimdallSpan, len(archive.Spans)),
+		checkpoints: make(map[int64]*checkpoint.Checkpoint, len(archive.Checkpoints)),
+	}
+
+	for _, s := range archive.Spans {
+		c.spans[s.ID] = s
+	}
+
+	for _, cp := range archive.Checkpoints {
+		c.checkpoints[cp.Number] = &cp.Checkpoint
+	}
+
+	sort.SliceStable(archive.Events, func(i, j int) bool {
+		return archive.Events[i].ID < archive.Events[j].ID
+	})
+
+	return c
+}
+
+func (c *HeimdallArchiveClient) StateSyncEvents(_ context.Context, fromID uint64, to int64) ([]*clerk.EventRecordWithTime, error) {
+	if fromID < c.archive.EventsFromID || to > c.archive.EventsToTime {
+		return nil, fmt.Errorf("state-sync events from %d until %d: %w", fromID, to, ErrNotInArchive)
+	}
+
+	records := make([]*clerk.EventRecordWithTime, 0)
+
+	for _, record := range c.archive.Events {
+		if record.ID >= fromID && record.Time.Unix() < to {
+			records = append(records, record)
+		}
+	}
+
+	return records, nil
+}
+
+func (c *HeimdallArchiveClient) Span(_ context.Context, spanID uint64) (*span.HeimdallSpan, error) {
+	s, ok := c.spans[spanID]
+	if !ok {
+		return nil, fmt.Errorf("span %d: %w", spanID, ErrNotInArchive)
+	}
+
+	return s, nil
+}
+
+// FetchCheckpoint returns the archived checkpoint with the given number, the
+// latest archived one being returned for -1.
+func (c *HeimdallArchiveClient) FetchCheckpoint(_ context.Context, number int64) (*checkpoint.Checkpoint, error) {
+	if number == -1 && len(c.archive.Checkpoints) > 0 {
+		return &c.archive.Checkpoints[len(c.archive.Checkpoints)-1].Checkpoint, nil
+	}
+
+	cp, ok := c.checkpoints[number]
+	if !ok {
+		return nil, fmt.Errorf("checkpoint %d: %w", number, ErrNotInArchive)
+	}
+
+	return cp, nil
+}
+
+func (c *HeimdallArchiveClient) FetchCheckpointCount(context.Context) (int64, error) {
+	return c.archive.CheckpointCount, nil
+}
+
+func (c *HeimdallArchiveClient) FetchMilestone(context.Context) (*milestone.Milestone, error) {
+	if c.archive.Milestone == nil {
+		return nil, fmt.Errorf("milestone: %w", ErrNotInArchive)
+	}
+
+	return c.archive.Milestone, nil
+}
+
+func (c *HeimdallArchiveClient) FetchMilestoneCount(context.Context) (int64, error) {
+	return c.archive.MilestoneCount, nil
+}
+
+// FetchNoAckMilestone only knows about the last rejected milestone at the time
+// of the export.
+func (c *HeimdallArchiveClient) FetchNoAckMilestone(_ context.Context, milestoneID string) error {
+	if milestoneID == "" || milestoneID != c.archive.LastNoAckMilestone {
+		return fmt.Errorf("%w: milestoneID %q", heimdall.ErrNotInRejectedList, milestoneID)
+	}
+
+	return nil
+}
+
+func (c *HeimdallArchiveClient) FetchLastNoAckMilestone(context.Context) (string, error) {
+	return c.archive.LastNoAckMilestone, nil
+}
+
+// FetchMilestoneID reports all the milestones as unknown, as no milestone is in
+// process in an archive.
+func (c *HeimdallArchiveClient) FetchMilestoneID(_ context.Context, milestoneID string) error {
+	return fmt.Errorf("%w: milestoneID %q", heimdall.ErrNotInMilestoneList, milestoneID)
+}
+
+func (c *HeimdallArchiveClient) Close() {}
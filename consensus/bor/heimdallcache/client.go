@@ -0,0 +1,201 @@
+// Package heimdallcache implements a Heimdall client caching the immutable
+// Heimdall responses in the chain database.
+package heimdallcache
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/ethereum/go-ethereum/consensus/bor"
+	"github.com/ethereum/go-ethereum/consensus/bor/clerk"
+	"github.com/ethereum/go-ethereum/consensus/bor/heimdall/checkpoint"
+	"github.com/ethereum/go-ethereum/consensus/bor/heimdall/span"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
+)
+
+// stateSyncFinality is the age after which no state-sync event can be recorded
+// in Heimdall anymore, making the event list up to that time immutable.
+const stateSyncFinality = 10 * time.Minute
+
+var (
+	spanHitCounter        = metrics.NewRegisteredCounter("client/cache/span/hit", nil)
+	spanMissCounter       = metrics.NewRegisteredCounter("client/cache/span/miss", nil)
+	checkpointHitCounter  = metrics.NewRegisteredCounter("client/cache/checkpoint/hit", nil)
+	checkpointMissCounter = metrics.NewRegisteredCounter("client/cache/checkpoint/miss", nil)
+	stateSyncHitCounter   = metrics.NewRegisteredCounter("client/cache/statesync/hit", nil)
+	stateSyncMissCounter  = metrics.NewRegisteredCounter("client/cache/statesync/miss", nil)
+)
+
+var _ bor.IHeimdallClient = (*HeimdallCacheClient)(nil)
+
+// HeimdallCacheClient decorates a Heimdall client, storing spans, checkpoints
+// and finalized state-sync events in the database so that they are only
+// fetched once from Heimdall.
+type HeimdallCacheClient struct {
+	bor.IHeimdallClient
+
+	db    ethdb.KeyValueStore
+	spans uint64 // Number of the latest spans kept in the cache, all if 0
+
+	now func() time.Time // Clock deciding the finality of state-sync events, replaced in tests
+}
+
+// NewHeimdallCacheClient wraps the given client with a cache stored in db,
+// keeping the given number of the latest spans, or all of them if 0.
+func NewHeimdallCacheClient(client bor.IHeimdallClient, db ethdb.KeyValueStore, spans uint64) *HeimdallCacheClient {
+	return &HeimdallCacheClient{
+		IHeimdallClient: client,
+		db:              db,
+		spans:           spans,
+		now:             time.Now,
+	}
+}
+
+// Span returns the span with the given id from the cache, fetching it from
+// Heimdall on a miss. Only the latest spans fetched are kept if the retention
+// is bounded.
+func (c *HeimdallCacheClient) Span(ctx context.Context, spanID uint64) (*span.HeimdallSpan, error) {
+	if data := rawdb.ReadHeimdallSpan(c.db, spanID); len(data) > 0 {
+		var cached span.HeimdallSpan
+		if err := json.Unmarshal(data, &cached); err == nil {
+			spanHitCounter.Inc(1)
+			return &cached, nil
+		}
+
+		log.Warn("Invalid cached heimdall span", "id", spanID)
+	}
+
+	spanMissCounter.Inc(1)
+
+	response, err := c.IHeimdallClient.Span(ctx, spanID)
+	if err != nil {
+		return nil, err
+	}
+
+	if data, err := json.Marshal(response); err == nil {
+		rawdb.WriteHeimdallSpan(c.db, spanID, data)
+
+		// Drop the span falling out of the retention window
+		if c.spans > 0 && spanID >= c.spans {
+			rawdb.DeleteHeimdallSpan(c.db, spanID-c.spans)
+		}
+	}
+
+	return response, nil
+}
+
+// FetchCheckpoint returns the checkpoint with the given number from the cache,
+// fetching it from Heimdall on a miss. The latest checkpoint is never cached.
+func (c *HeimdallCacheClient) FetchCheckpoint(ctx context.Context, number int64) (*checkpoint.Checkpoint, error) {
+	if number < 0 {
+		return c.IHeimdallClient.FetchCheckpoint(ctx, number)
+	}
+
+	if data := rawdb.ReadHeimdallCheckpoint(c.db, uint64(number)); len(data) > 0 {
+		var cached checkpoint.Checkpoint
+		if err := json.Unmarshal(data, &cached); err == nil {
+			checkpointHitCounter.Inc(1)
+			return &cached, nil
+		}
+
+		log.Warn("Invalid cached heimdall checkpoint", "number", number)
+	}
+
+	checkpointMissCounter.Inc(1)
+
+	response, err := c.IHeimdallClient.FetchCheckpoint(ctx, number)
+	if err != nil {
+		return nil, err
+	}
+
+	if data, err := json.Marshal(response); err == nil {
+		rawdb.WriteHeimdallCheckpoint(c.db, uint64(number), data)
+	}
+
+	return response, nil
+}
+
+// StateSyncEvents returns the state-sync events with an id of at least fromID
+// recorded before the given time. Ranges of events older than the finality
+// delay are served from the cache once fetched.
+func (c *HeimdallCacheClient) StateSyncEvents(ctx context.Context, fromID uint64, to int64) ([]*clerk.EventRecordWithTime, error) {
+	if records, ok := c.cachedEvents(fromID, to); ok {
+		stateSyncHitCounter.Inc(1)
+		return records, nil
+	}
+
+	stateSyncMissCounter.Inc(1)
+
+	records, err := c.IHeimdallClient.StateSyncEvents(ctx, fromID, to)
+	if err != nil {
+		return nil, err
+	}
+
+	c.storeEvents(fromID, to, records)
+
+	return records, nil
+}
+
+// cachedEvents returns the events of a complete cached range, if any.
+func (c *HeimdallCacheClient) cachedEvents(fromID uint64, to int64) ([]*clerk.EventRecordWithTime, bool) {
+	lastID, toTime, ok := rawdb.ReadHeimdallEventRange(c.db, fromID)
+	if !ok || to < 0 || uint64(to) > toTime {
+		return nil, false
+	}
+
+	records := make([]*clerk.EventRecordWithTime, 0)
+
+	for id := fromID; id <= lastID; id++ {
+		data := rawdb.ReadHeimdallEventRecord(c.db, id)
+		if len(data) == 0 {
+			return nil, false
+		}
+
+		var record clerk.EventRecordWithTime
+		if err := json.Unmarshal(data, &record); err != nil {
+			log.Warn("Invalid cached heimdall event record", "id", id)
+			return nil, false
+		}
+
+		if record.Time.Unix() >= to {
+			break
+		}
+
+		records = append(records, &record)
+	}
+
+	return records, true
+}
+
+// storeEvents caches the fetched events, and the range they cover if no
+// event can be added to it anymore.
+func (c *HeimdallCacheClient) storeEvents(fromID uint64, to int64, records []*clerk.EventRecordWithTime) {
+	batch := c.db.NewBatch()
+
+	contiguous := true
+
+	for i, record := range records {
+		if record.ID != fromID+uint64(i) {
+			contiguous = false
+		}
+
+		data, err := json.Marshal(record)
+		if err != nil {
+			return
+		}
+
+		rawdb.WriteHeimdallEventRecord(batch, record.ID, data)
+	}
+
+	if contiguous && fromID > 0 && to >= 0 && time.Unix(to, 0).Add(stateSyncFinality).Before(c.now()) {
+		rawdb.WriteHeimdallEventRange(batch, fromID, fromID+uint64(len(records))-1, uint64(to))
+	}
+
+	if err := batch.Write(); err != nil {
+		log.Error("Failed to cache heimdall event records", "from", fromID, "err", err)
+	}
+}
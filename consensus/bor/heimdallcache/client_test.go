@@ -0,0 +1,122 @@
+package heimdallcache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/consensus/bor/clerk"
+	"github.com/ethereum/go-ethereum/consensus/bor/heimdall/span"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/tests/bor/mocks"
+	"github.com/golang/mock/gomock"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSpanCache(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	inner := mocks.NewMockIHeimdallClient(ctrl)
+	inner.EXPECT().Span(gomock.Any(), uint64(7)).Return(&span.HeimdallSpan{Span: span.Span{ID: 7, StartBlock: 100, EndBlock: 200}, ChainID: "137"}, nil).Times(1)
+
+	db := rawdb.NewMemoryDatabase()
+
+	for i := 0; i < 2; i++ {
+		// A new client over the same database, as after a restart
+		client := NewHeimdallCacheClient(inner, db, 0)
+
+		s, err := client.Span(context.Background(), 7)
+		require.NoError(t, err)
+		require.Equal(t, uint64(200), s.EndBlock)
+		require.Equal(t, "137", s.ChainID)
+	}
+}
+
+func TestSpanCacheRetention(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	inner := mocks.NewMockIHeimdallClient(ctrl)
+	inner.EXPECT().Span(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, id uint64) (*span.HeimdallSpan, error) {
+		return &span.HeimdallSpan{Span: span.Span{ID: id}}, nil
+	}).Times(7)
+
+	db := rawdb.NewMemoryDatabase()
+	client := NewHeimdallCacheClient(inner, db, 2)
+
+	for id := uint64(0); id < 3; id++ {
+		_, err := client.Span(context.Background(), id)
+		require.NoError(t, err)
+	}
+
+	// Only the latest two spans are kept
+	require.Empty(t, rawdb.ReadHeimdallSpan(db, 0))
+	require.NotEmpty(t, rawdb.ReadHeimdallSpan(db, 1))
+	require.NotEmpty(t, rawdb.ReadHeimdallSpan(db, 2))
+
+	_, err := client.Span(context.Background(), 0)
+	require.NoError(t, err)
+
+	// All the spans are kept without a retention
+	db = rawdb.NewMemoryDatabase()
+	client = NewHeimdallCacheClient(inner, db, 0)
+
+	for id := uint64(0); id < 3; id++ {
+		_, err := client.Span(context.Background(), id+100)
+		require.NoError(t, err)
+	}
+
+	for id := uint64(0); id < 3; id++ {
+		require.NotEmpty(t, rawdb.ReadHeimdallSpan(db, id+100))
+	}
+}
+
+func TestStateSyncEventsCache(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	var (
+		base    = time.Unix(1_000_000, 0)
+		records = []*clerk.EventRecordWithTime{
+			{EventRecord: clerk.EventRecord{ID: 5}, Time: base},
+			{EventRecord: clerk.EventRecord{ID: 6}, Time: base.Add(time.Second)},
+		}
+		final = base.Add(2 * time.Second).Unix()
+		fresh = base.Add(time.Hour).Unix()
+	)
+
+	inner := mocks.NewMockIHeimdallClient(ctrl)
+	inner.EXPECT().StateSyncEvents(gomock.Any(), uint64(5), final).Return(records, nil).Times(1)
+	inner.EXPECT().StateSyncEvents(gomock.Any(), uint64(5), fresh).Return(records, nil).Times(2)
+
+	client := NewHeimdallCacheClient(inner, rawdb.NewMemoryDatabase(), 0)
+	client.now = func() time.Time { return base.Add(time.Hour + time.Minute) }
+
+	// Finalized ranges are only fetched once
+	for i := 0; i < 2; i++ {
+		events, err := client.StateSyncEvents(context.Background(), 5, final)
+		require.NoError(t, err)
+		require.Len(t, events, 2)
+		require.Equal(t, uint64(6), events[1].ID)
+	}
+
+	// Earlier times are served from the finalized range
+	events, err := client.StateSyncEvents(context.Background(), 5, base.Add(time.Second).Unix())
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+
+	// Ranges which may still receive events are always fetched
+	for i := 0; i < 2; i++ {
+		events, err := client.StateSyncEvents(context.Background(), 5, fresh)
+		require.NoError(t, err)
+		require.Len(t, events, 2)
+	}
+}
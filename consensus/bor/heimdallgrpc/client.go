@@ -1,8 +1,10 @@
 package heimdallgrpc
 
 import (
+	"context"
 	"time"
 
+	"github.com/ethereum/go-ethereum/consensus/bor/heimdall"
 	"github.com/ethereum/go-ethereum/log"
 
 	grpc_retry "github.com/grpc-ecosystem/go-grpc-middleware/retry"
@@ -32,6 +34,8 @@ func NewHeimdallGRPCClient(address string) *HeimdallGRPCClient {
 	conn, err := grpc.Dial(address,
 		grpc.WithStreamInterceptor(grpc_retry.StreamClientInterceptor(opts...)),
 		grpc.WithUnaryInterceptor(grpc_retry.UnaryClientInterceptor(opts...)),
+		grpc.WithChainStreamInterceptor(countStreamAttempts),
+		grpc.WithChainUnaryInterceptor(countUnaryAttempts),
 		grpc.WithTransportCredentials(insecure.NewCredentials()),
 	)
 	if err != nil {
@@ -46,6 +50,20 @@ func NewHeimdallGRPCClient(address string) *HeimdallGRPCClient {
 	}
 }
 
+// countUnaryAttempts counts every attempt of a call, running within the retries.
+func countUnaryAttempts(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	heimdall.CountAttempt(ctx)
+
+	return invoker(ctx, method, req, reply, cc, opts...)
+}
+
+// countStreamAttempts counts every attempt of a stream, running within the retries.
+func countStreamAttempts(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+	heimdall.CountAttempt(ctx)
+
+	return streamer(ctx, desc, cc, method, opts...)
+}
+
 func (h *HeimdallGRPCClient) Close() {
 	log.Debug("Shutdown detected, Closing Heimdall gRPC client")
 	h.conn.Close()
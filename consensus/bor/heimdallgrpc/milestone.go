@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"math/big"
 
+	"github.com/ethereum/go-ethereum/consensus/bor/heimdall"
 	"github.com/ethereum/go-ethereum/consensus/bor/heimdall/milestone"
 	"github.com/ethereum/go-ethereum/log"
 
@@ -73,7 +74,7 @@ func (h *HeimdallGRPCClient) FetchNoAckMilestone(ctx context.Context, milestoneI
 	}
 
 	if !res.Result.Result {
-		return fmt.Errorf("Not in rejected list: milestoneID %q", milestoneID)
+		return fmt.Errorf("%w: milestoneID %q", heimdall.ErrNotInRejectedList, milestoneID)
 	}
 
 	log.Info("Fetched no ack milestone", "milestoneaID", milestoneID)
@@ -94,7 +95,7 @@ func (h *HeimdallGRPCClient) FetchMilestoneID(ctx context.Context, milestoneID s
 	}
 
 	if !res.Result.Result {
-		return fmt.Errorf("This milestoneID %q does not exist", milestoneID)
+		return fmt.Errorf("%w: milestoneID %q", heimdall.ErrNotInMilestoneList, milestoneID)
 	}
 
 	log.Info("Fetched milestone id", "milestoneID", milestoneID)
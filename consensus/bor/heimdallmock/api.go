@@ -0,0 +1,18 @@
+package heimdallmock
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// AdminAPI is the admin RPC API of the mock Heimdall.
+type AdminAPI struct {
+	server *Server
+}
+
+// InjectStateSync records a state-sync event with the given data for the given
+// receiver contract, to be committed by the next sprints. It returns the id of
+// the event.
+func (api *AdminAPI) InjectStateSync(contract common.Address, data hexutil.Bytes) hexutil.Uint64 {
+	return hexutil.Uint64(api.server.AddStateSyncEvent(contract, data).ID)
+}
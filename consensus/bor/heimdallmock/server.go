@@ -0,0 +1,458 @@
+// Package heimdallmock implements an in-process fake Heimdall serving the REST
+// endpoints used by the Heimdall client, for devnets without a Heimdall.
+package heimdallmock
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/bor/clerk"
+	"github.com/ethereum/go-ethereum/consensus/bor/heimdall/checkpoint"
+	"github.com/ethereum/go-ethereum/consensus/bor/heimdall/milestone"
+	"github.com/ethereum/go-ethereum/consensus/bor/heimdall/span"
+	"github.com/ethereum/go-ethereum/consensus/bor/valset"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+const (
+	// firstSpanLength is the minimum number of blocks of the first span, as
+	// set by Heimdall in its genesis.
+	firstSpanLength = 256
+
+	// validatorPower is the voting power given to every validator.
+	validatorPower = 10
+
+	defaultSprint           = 16
+	defaultSpanLength       = 6400
+	defaultCheckpointLength = 256
+	defaultMilestoneLength  = 16
+)
+
+// errNoChain is returned when the data needs a chain which is not set yet.
+var errNoChain = errors.New("chain not available")
+
+// Config is the mock Heimdall settings.
+type Config struct {
+	// Address is the address the REST server listens on
+	Address string
+
+	// ChainID is the Bor chain id set in the responses
+	ChainID string
+
+	// Validators is the validator set producing every span
+	Validators []common.Address
+
+	// Sprint is the number of blocks of the sprints of the chain, the spans
+	// ending on a sprint boundary
+	Sprint uint64
+
+	// SpanLength is the number of blocks of the spans following the first one,
+	// rounded up to a multiple of the sprint
+	SpanLength uint64
+
+	// CheckpointLength is the number of blocks of the checkpoints
+	CheckpointLength uint64
+
+	// MilestoneLength is the number of blocks of the milestones
+	MilestoneLength uint64
+}
+
+// Backend is the local chain the checkpoints and milestones are produced from.
+type Backend interface {
+	HeaderByNumber(ctx context.Context, number rpc.BlockNumber) (*types.Header, error)
+	GetRootHash(ctx context.Context, starBlockNr uint64, endBlockNr uint64) (string, error)
+}
+
+// Server is a fake Heimdall generating spans from a fixed validator set,
+// checkpoints and milestones from the local chain, and serving the state-sync
+// events injected through its API.
+type Server struct {
+	config       Config
+	firstSpanEnd uint64
+
+	mu      sync.RWMutex
+	backend Backend
+	events  []*clerk.EventRecordWithTime
+
+	server *http.Server
+}
+
+// NewServer creates a mock Heimdall with the given settings, the lengths left
+// to zero taking the default ones.
+func NewServer(config Config) *Server {
+	if config.Sprint == 0 {
+		config.Sprint = defaultSprint
+	}
+
+	if config.SpanLength == 0 {
+		config.SpanLength = defaultSpanLength
+	}
+
+	config.SpanLength = roundToSprint(config.SpanLength, config.Sprint)
+
+	if config.CheckpointLength == 0 {
+		config.CheckpointLength = defaultCheckpointLength
+	}
+
+	if config.MilestoneLength == 0 {
+		config.MilestoneLength = defaultMilestoneLength
+	}
+
+	s := &Server{
+		config:       config,
+		firstSpanEnd: roundToSprint(firstSpanLength, config.Sprint) - 1,
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/bor/span/", s.handleSpan)
+	mux.HandleFunc("/clerk/event-record/list", s.handleStateSyncEvents)
+	mux.HandleFunc("/checkpoints/", s.handleCheckpoint)
+	mux.HandleFunc("/milestone/", s.handleMilestone)
+
+	s.server = &http.Server{
+		Handler:           mux,
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+
+	return s
+}
+
+// SetBackend sets the local chain the checkpoints and milestones are
+// produced from.
+func (s *Server) SetBackend(backend Backend) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.backend = backend
+}
+
+// Handler returns the handler serving the Heimdall REST endpoints.
+func (s *Server) Handler() http.Handler {
+	return s.server.Handler
+}
+
+// Start implements node.Lifecycle, starting the REST server.
+func (s *Server) Start() error {
+	listener, err := net.Listen("tcp", s.config.Address)
+	if err != nil {
+		return fmt.Errorf("failed to start mock heimdall: %w", err)
+	}
+
+	go func() {
+		if err := s.server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Error("Mock heimdall failed", "err", err)
+		}
+	}()
+
+	log.Info("Mock heimdall started", "url", "http://"+listener.Addr().String(), "validators", len(s.config.Validators))
+
+	return nil
+}
+
+// Stop implements node.Lifecycle, stopping the REST server.
+func (s *Server) Stop() error {
+	return s.server.Close()
+}
+
+// APIs returns the RPC APIs of the mock Heimdall.
+func (s *Server) APIs() []rpc.API {
+	return []rpc.API{
+		{
+			Namespace: "admin",
+			Service:   &AdminAPI{server: s},
+		},
+	}
+}
+
+// AddStateSyncEvent records a new state-sync event for the given receiver.
+func (s *Server) AddStateSyncEvent(contract common.Address, data []byte) *clerk.EventRecordWithTime {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id := uint64(len(s.events)) + 1
+
+	record := &clerk.EventRecordWithTime{
+		EventRecord: clerk.EventRecord{
+			ID:       id,
+			Contract: contract,
+			Data:     common.CopyBytes(data),
+			TxHash:   crypto.Keccak256Hash(new(big.Int).SetUint64(id).Bytes(), data),
+			ChainID:  s.config.ChainID,
+		},
+		Time: time.Now().UTC(),
+	}
+
+	s.events = append(s.events, record)
+
+	return record
+}
+
+// roundToSprint rounds the given number of blocks up to a multiple of the sprint.
+func roundToSprint(blocks uint64, sprint uint64) uint64 {
+	return (blocks + sprint - 1) / sprint * sprint
+}
+
+// span returns the span with the given id, produced by all the validators.
+func (s *Server) span(id uint64) *span.HeimdallSpan {
+	start, end := uint64(0), s.firstSpanEnd
+	if id > 0 {
+		start = s.firstSpanEnd + 1 + (id-1)*s.config.SpanLength
+		end = start + s.config.SpanLength - 1
+	}
+
+	validators := make([]*valset.Validator, len(s.config.Validators))
+	producers := make([]valset.Validator, len(s.config.Validators))
+
+	for i, address := range s.config.Validators {
+		validators[i] = &valset.Validator{ID: uint64(i + 1), Address: address, VotingPower: validatorPower}
+		producers[i] = *validators[i]
+	}
+
+	return &span.HeimdallSpan{
+		Span:              span.Span{ID: id, StartBlock: start, EndBlock: end},
+		ValidatorSet:      *valset.NewValidatorSet(validators),
+		SelectedProducers: producers,
+		ChainID:           s.config.ChainID,
+	}
+}
+
+// stateSyncEvents returns up to limit events with an id of at least fromID
+// recorded before the given time.
+func (s *Server) stateSyncEvents(fromID uint64, to int64, limit int) []*clerk.EventRecordWithTime {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	records := make([]*clerk.EventRecordWithTime, 0)
+
+	for _, record := range s.events {
+		if len(records) >= limit {
+			break
+		}
+
+		if record.ID >= fromID && record.Time.Unix() < to {
+			records = append(records, record)
+		}
+	}
+
+	return records
+}
+
+// head returns the current head of the local chain.
+func (s *Server) head(ctx context.Context) (Backend, *types.Header, error) {
+	s.mu.RLock()
+	backend := s.backend
+	s.mu.RUnlock()
+
+	if backend == nil {
+		return nil, nil, errNoChain
+	}
+
+	header, err := backend.HeaderByNumber(ctx, rpc.LatestBlockNumber)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return backend, header, nil
+}
+
+// checkpointCount returns the number of complete checkpoints in the local chain.
+func (s *Server) checkpointCount(ctx context.Context) (int64, error) {
+	_, head, err := s.head(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	return int64((head.Number.Uint64() + 1) / s.config.CheckpointLength), nil
+}
+
+// checkpoint returns the checkpoint with the given number, the latest one for -1.
+func (s *Server) checkpoint(ctx context.Context, number int64) (*checkpoint.Checkpoint, error) {
+	backend, head, err := s.head(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	count := int64((head.Number.Uint64() + 1) / s.config.CheckpointLength)
+	if number == -1 {
+		number = count
+	}
+
+	if number < 1 || number > count {
+		return nil, fmt.Errorf("checkpoint %d not found", number)
+	}
+
+	start := uint64(number-1) * s.config.CheckpointLength
+	end := start + s.config.CheckpointLength - 1
+
+	root, err := backend.GetRootHash(ctx, start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	header, err := backend.HeaderByNumber(ctx, rpc.BlockNumber(end))
+	if err != nil {
+		return nil, err
+	}
+
+	return &checkpoint.Checkpoint{
+		Proposer:   header.Coinbase,
+		StartBlock: new(big.Int).SetUint64(start),
+		EndBlock:   new(big.Int).SetUint64(end),
+		RootHash:   common.HexToHash(root),
+		BorChainID: s.config.ChainID,
+		Timestamp:  header.Time,
+	}, nil
+}
+
+// milestone returns the latest milestone of the local chain, and the number of
+// milestones so far.
+func (s *Server) milestone(ctx context.Context) (*milestone.Milestone, int64, error) {
+	backend, head, err := s.head(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	count := int64((head.Number.Uint64() + 1) / s.config.MilestoneLength)
+	if count == 0 {
+		return nil, 0, nil
+	}
+
+	start := uint64(count-1) * s.config.MilestoneLength
+	end := start + s.config.MilestoneLength - 1
+
+	header, err := backend.HeaderByNumber(ctx, rpc.BlockNumber(end))
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return &milestone.Milestone{
+		Proposer:   header.Coinbase,
+		StartBlock: new(big.Int).SetUint64(start),
+		EndBlock:   new(big.Int).SetUint64(end),
+		Hash:       header.Hash(),
+		BorChainID: s.config.ChainID,
+		Timestamp:  header.Time,
+	}, count, nil
+}
+
+func (s *Server) handleSpan(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseUint(strings.TrimPrefix(r.URL.Path, "/bor/span/"), 10, 64)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	respond(w, s.span(id))
+}
+
+func (s *Server) handleStateSyncEvents(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	fromID, err := strconv.ParseUint(query.Get("from-id"), 10, 64)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	to, err := strconv.ParseInt(query.Get("to-time"), 10, 64)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	limit, err := strconv.Atoi(query.Get("limit"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	respond(w, s.stateSyncEvents(fromID, to, limit))
+}
+
+func (s *Server) handleCheckpoint(w http.ResponseWriter, r *http.Request) {
+	switch path := strings.TrimPrefix(r.URL.Path, "/checkpoints/"); path {
+	case "count":
+		count, err := s.checkpointCount(r.Context())
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusServiceUnavailable)
+			return
+		}
+
+		respond(w, checkpoint.CheckpointCount{Result: count})
+	default:
+		number := int64(-1)
+
+		if path != "latest" {
+			var err error
+			if number, err = strconv.ParseInt(path, 10, 64); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+		}
+
+		c, err := s.checkpoint(r.Context(), number)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+
+		respond(w, c)
+	}
+}
+
+func (s *Server) handleMilestone(w http.ResponseWriter, r *http.Request) {
+	path := strings.TrimPrefix(r.URL.Path, "/milestone/")
+
+	switch {
+	case path == "count", path == "latest":
+		m, count, err := s.milestone(r.Context())
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusServiceUnavailable)
+			return
+		}
+
+		if path == "count" {
+			respond(w, milestone.MilestoneCount{Count: count})
+			return
+		}
+
+		if m == nil {
+			http.Error(w, "no milestone yet", http.StatusNotFound)
+			return
+		}
+
+		respond(w, m)
+	case path == "lastNoAck":
+		// Milestones of the local chain are never rejected
+		respond(w, milestone.MilestoneLastNoAck{Result: ""})
+	case strings.HasPrefix(path, "noAck/"):
+		respond(w, milestone.MilestoneNoAck{Result: false})
+	case strings.HasPrefix(path, "ID/"):
+		// No milestone is ever in process, they are produced instantly
+		respond(w, milestone.MilestoneID{Result: false})
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// respond writes the result in the Heimdall response envelope.
+func respond(w http.ResponseWriter, result interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+
+	if err := json.NewEncoder(w).Encode(map[string]interface{}{"height": "0", "result": result}); err != nil {
+		log.Debug("Failed to write mock heimdall response", "err", err)
+	}
+}
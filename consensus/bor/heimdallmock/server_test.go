@@ -0,0 +1,98 @@
+package heimdallmock
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/bor/heimdall"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+
+	"github.com/stretchr/testify/require"
+)
+
+// testBackend is a chain of headers numbered up to head.
+type testBackend struct {
+	head uint64
+}
+
+func (b *testBackend) HeaderByNumber(_ context.Context, number rpc.BlockNumber) (*types.Header, error) {
+	if number == rpc.LatestBlockNumber {
+		number = rpc.BlockNumber(b.head)
+	}
+
+	return &types.Header{Number: big.NewInt(number.Int64()), Time: uint64(number.Int64()) * 2}, nil
+}
+
+func (b *testBackend) GetRootHash(_ context.Context, start uint64, end uint64) (string, error) {
+	return fmt.Sprintf("%064x", start<<32|end), nil
+}
+
+func TestMockHeimdall(t *testing.T) {
+	t.Parallel()
+
+	validators := []common.Address{common.HexToAddress("0x1"), common.HexToAddress("0x2")}
+
+	server := NewServer(Config{ChainID: "15001", Validators: validators, SpanLength: 64, CheckpointLength: 32, MilestoneLength: 8})
+	server.SetBackend(&testBackend{head: 100})
+
+	httpServer := httptest.NewServer(server.Handler())
+	defer httpServer.Close()
+
+	client := heimdall.NewHeimdallClient(httpServer.URL)
+	defer client.Close()
+
+	ctx := context.Background()
+
+	s, err := client.Span(ctx, 2)
+	require.NoError(t, err)
+	require.Equal(t, uint64(320), s.StartBlock)
+	require.Equal(t, uint64(383), s.EndBlock)
+	require.Len(t, s.SelectedProducers, 2)
+	require.Len(t, s.ValidatorSet.Validators, 2)
+	require.Equal(t, "15001", s.ChainID)
+
+	// The spans end on the sprint boundaries of the chain
+	sprinted := NewServer(Config{Validators: validators, Sprint: 100, SpanLength: 64})
+	require.Equal(t, uint64(299), sprinted.span(0).EndBlock)
+	require.Equal(t, uint64(300), sprinted.span(1).StartBlock)
+	require.Equal(t, uint64(399), sprinted.span(1).EndBlock)
+
+	// Blocks 0 to 95 are checkpointed
+	count, err := client.FetchCheckpointCount(ctx)
+	require.NoError(t, err)
+	require.Equal(t, int64(3), count)
+
+	c, err := client.FetchCheckpoint(ctx, -1)
+	require.NoError(t, err)
+	require.Equal(t, uint64(64), c.StartBlock.Uint64())
+	require.Equal(t, uint64(95), c.EndBlock.Uint64())
+	require.Equal(t, common.HexToHash(fmt.Sprintf("%x", uint64(64)<<32|95)), c.RootHash)
+
+	m, err := client.FetchMilestone(ctx)
+	require.NoError(t, err)
+	require.Equal(t, uint64(88), m.StartBlock.Uint64())
+	require.Equal(t, uint64(2*95), m.Timestamp)
+
+	count, err = client.FetchMilestoneCount(ctx)
+	require.NoError(t, err)
+	require.Equal(t, int64(12), count)
+
+	require.ErrorIs(t, client.FetchNoAckMilestone(ctx, "id"), heimdall.ErrNotInRejectedList)
+
+	// Injected events are served once recorded
+	api := server.APIs()[0].Service.(*AdminAPI)
+	require.Equal(t, uint64(1), uint64(api.InjectStateSync(common.HexToAddress("0x1001"), []byte{1})))
+	require.Equal(t, uint64(2), uint64(api.InjectStateSync(common.HexToAddress("0x1001"), []byte{2})))
+
+	events, err := client.StateSyncEvents(ctx, 2, time.Now().Add(time.Minute).Unix())
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	require.Equal(t, uint64(2), events[0].ID)
+	require.Equal(t, []byte{2}, []byte(events[0].Data))
+}
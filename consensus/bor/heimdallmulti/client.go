@@ -0,0 +1,411 @@
+// Package heimdallmulti implements a Heimdall client failing over between
+// several HTTP and gRPC Heimdall endpoints.
+package heimdallmulti
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/consensus/bor"
+	"github.com/ethereum/go-ethereum/consensus/bor/clerk"
+	"github.com/ethereum/go-ethereum/consensus/bor/heimdall"
+	"github.com/ethereum/go-ethereum/consensus/bor/heimdall/checkpoint"
+	"github.com/ethereum/go-ethereum/consensus/bor/heimdall/milestone"
+	"github.com/ethereum/go-ethereum/consensus/bor/heimdall/span"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
+)
+
+var (
+	errNoEndpoints      = errors.New("no heimdall endpoints")
+	errQuorumNotReached = errors.New("heimdall endpoints did not reach quorum")
+
+	failoverMeter       = metrics.NewRegisteredMeter("client/multi/failover", nil)
+	quorumMismatchMeter = metrics.NewRegisteredMeter("client/multi/quorum/mismatch", nil)
+)
+
+const (
+	// attemptTimeout is the time given to an endpoint before failing over to
+	// the next one. The wrapped clients retry on their own until it expires.
+	attemptTimeout = 10 * time.Second
+
+	// retryCall is the delay between two rounds over all endpoints
+	retryCall = 5 * time.Second
+
+	// maxBackoffShift caps the cool down of a failing endpoint to
+	// retryCall << maxBackoffShift
+	maxBackoffShift = 5
+
+	// latencyWeight is the weight of the last request in the latency average
+	latencyWeight = 0.2
+)
+
+// Endpoint is a named Heimdall client wrapped by the multi client.
+type Endpoint struct {
+	Name   string
+	Client bor.IHeimdallClient
+}
+
+// endpoint tracks the health of a single Heimdall endpoint.
+type endpoint struct {
+	Endpoint
+
+	mu       sync.Mutex
+	latency  float64   // Moving average of the request latency in milliseconds
+	failures int       // Number of consecutive failed requests
+	retryAt  time.Time // Time before which a failing endpoint is only used as last resort
+}
+
+// record updates the health of the endpoint after a request.
+func (e *endpoint) record(latency time.Duration, err error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	ms := float64(latency) / float64(time.Millisecond)
+	if e.latency == 0 {
+		e.latency = ms
+	} else {
+		e.latency = (1-latencyWeight)*e.latency + latencyWeight*ms
+	}
+
+	if err == nil {
+		e.failures = 0
+		e.retryAt = time.Time{}
+
+		return
+	}
+
+	e.backoff()
+}
+
+// penalize records a failure of the endpoint without a latency sample, for an
+// answer which came in time but is wrong.
+func (e *endpoint) penalize() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.backoff()
+}
+
+// backoff counts a failure of the endpoint and cools it down. The caller must
+// hold the lock.
+func (e *endpoint) backoff() {
+	shift := e.failures
+	if shift > maxBackoffShift {
+		shift = maxBackoffShift
+	}
+
+	e.failures++
+	e.retryAt = time.Now().Add(retryCall << shift)
+}
+
+// score returns whether the endpoint is healthy at the given time along with
+// its score, lower being better.
+func (e *endpoint) score(now time.Time) (bool, float64, time.Time) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	return now.After(e.retryAt), e.latency * float64(1+e.failures), e.retryAt
+}
+
+var _ bor.IHeimdallClient = (*HeimdallMultiClient)(nil)
+
+// HeimdallMultiClient implements bor.IHeimdallClient on top of several
+// endpoints. Requests go to the healthiest endpoint first and fail over to the
+// others on errors or timeouts. Span and milestone responses can optionally be
+// cross-checked against a quorum of endpoints.
+type HeimdallMultiClient struct {
+	endpoints []*endpoint
+	quorum    int
+
+	closeCh   chan struct{}
+	closeOnce sync.Once
+}
+
+// NewHeimdallMultiClient creates a client failing over between the given
+// endpoints. A quorum greater than one requires that many endpoints to return
+// the same span or milestone before it is accepted, it can't exceed the number
+// of endpoints.
+func NewHeimdallMultiClient(endpoints []Endpoint, quorum int) (*HeimdallMultiClient, error) {
+	if len(endpoints) == 0 {
+		return nil, errNoEndpoints
+	}
+
+	if quorum > len(endpoints) {
+		return nil, fmt.Errorf("heimdall quorum of %d larger than the %d endpoints", quorum, len(endpoints))
+	}
+
+	c := &HeimdallMultiClient{
+		endpoints: make([]*endpoint, 0, len(endpoints)),
+		quorum:    quorum,
+		closeCh:   make(chan struct{}),
+	}
+
+	for _, e := range endpoints {
+		c.endpoints = append(c.endpoints, &endpoint{Endpoint: e})
+	}
+
+	return c, nil
+}
+
+// ordered returns the endpoints sorted by preference: healthy endpoints by
+// score, then failing endpoints by the end of their cool down.
+func (c *HeimdallMultiClient) ordered() []*endpoint {
+	type ranked struct {
+		e       *endpoint
+		healthy bool
+		score   float64
+		retryAt time.Time
+	}
+
+	now := time.Now()
+	ranks := make([]ranked, len(c.endpoints))
+
+	for i, e := range c.endpoints {
+		healthy, score, retryAt := e.score(now)
+		ranks[i] = ranked{e, healthy, score, retryAt}
+	}
+
+	sort.SliceStable(ranks, func(i, j int) bool {
+		if ranks[i].healthy != ranks[j].healthy {
+			return ranks[i].healthy
+		}
+
+		if ranks[i].healthy {
+			return ranks[i].score < ranks[j].score
+		}
+
+		return ranks[i].retryAt.Before(ranks[j].retryAt)
+	})
+
+	ordered := make([]*endpoint, len(ranks))
+	for i, r := range ranks {
+		ordered[i] = r.e
+	}
+
+	return ordered
+}
+
+// isAnswer reports whether the error is a valid answer from Heimdall rather
+// than a failure of the endpoint.
+func isAnswer(err error) bool {
+	return errors.Is(err, heimdall.ErrNotInRejectedList) || errors.Is(err, heimdall.ErrNotInMilestoneList)
+}
+
+// wait blocks until the next round of requests, the context is cancelled or
+// the client is closed.
+func (c *HeimdallMultiClient) wait(ctx context.Context) error {
+	timer := time.NewTimer(retryCall)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-c.closeCh:
+		return heimdall.ErrShutdownDetected
+	case <-timer.C:
+		return nil
+	}
+}
+
+// attempt runs a single request against an endpoint and records the outcome.
+func attempt[T any](ctx context.Context, e *endpoint, fn func(context.Context, bor.IHeimdallClient) (T, error)) (T, error) {
+	ctx, cancel := context.WithTimeout(ctx, attemptTimeout)
+	defer cancel()
+
+	start := time.Now()
+	result, err := fn(ctx, e.Client)
+
+	if err == nil || isAnswer(err) {
+		e.record(time.Since(start), nil)
+	} else {
+		e.record(time.Since(start), err)
+	}
+
+	return result, err
+}
+
+// call runs the request on the endpoints by order of preference until one of
+// them answers, retrying all of them until the context is done.
+func call[T any](ctx context.Context, c *HeimdallMultiClient, request string, fn func(context.Context, bor.IHeimdallClient) (T, error)) (T, error) {
+	var zero T
+
+	for {
+		unavailable := 0
+
+		for i, e := range c.ordered() {
+			result, err := attempt(ctx, e, fn)
+			if err == nil || isAnswer(err) {
+				if i > 0 {
+					failoverMeter.Mark(1)
+				}
+
+				return result, err
+			}
+
+			if ctx.Err() != nil {
+				return zero, ctx.Err()
+			}
+
+			// The endpoint may not be activated on this Heimdall node yet
+			if errors.Is(err, heimdall.ErrServiceUnavailable) {
+				unavailable++
+
+				if unavailable == len(c.endpoints) {
+					return zero, err
+				}
+			}
+
+			log.Warn("Heimdall endpoint failed, trying the next one", "endpoint", e.Name, "request", request, "err", err)
+		}
+
+		if err := c.wait(ctx); err != nil {
+			return zero, err
+		}
+	}
+}
+
+// callQuorum runs the request like call, but only accepts a response once the
+// configured quorum of endpoints returned it. Endpoints disagreeing with the
+// quorum are penalized.
+func callQuorum[T any](ctx context.Context, c *HeimdallMultiClient, request string, fn func(context.Context, bor.IHeimdallClient) (T, error)) (T, error) {
+	var zero T
+
+	if c.quorum <= 1 {
+		return call(ctx, c, request, fn)
+	}
+
+	for {
+		var (
+			groups = make(map[string][]*endpoint)
+			values = make(map[string]T)
+		)
+
+		for _, e := range c.ordered() {
+			result, err := attempt(ctx, e, fn)
+			if err != nil {
+				if ctx.Err() != nil {
+					return zero, ctx.Err()
+				}
+
+				log.Warn("Heimdall endpoint failed, trying the next one", "endpoint", e.Name, "request", request, "err", err)
+
+				continue
+			}
+
+			blob, err := json.Marshal(result)
+			if err != nil {
+				return zero, err
+			}
+
+			key := string(blob)
+			groups[key] = append(groups[key], e)
+			values[key] = result
+
+			if len(groups[key]) < c.quorum {
+				continue
+			}
+
+			for other, endpoints := range groups {
+				if other == key {
+					continue
+				}
+
+				for _, e := range endpoints {
+					quorumMismatchMeter.Mark(1)
+					e.penalize()
+					log.Warn("Heimdall endpoint disagrees with quorum", "endpoint", e.Name, "request", request)
+				}
+			}
+
+			return values[key], nil
+		}
+
+		log.Warn("Failed to reach Heimdall quorum", "request", request, "quorum", c.quorum, "responses", len(groups), "err", errQuorumNotReached)
+
+		if err := c.wait(ctx); err != nil {
+			return zero, err
+		}
+	}
+}
+
+func (c *HeimdallMultiClient) StateSyncEvents(ctx context.Context, fromID uint64, to int64) ([]*clerk.EventRecordWithTime, error) {
+	return call(ctx, c, "state-sync", func(ctx context.Context, client bor.IHeimdallClient) ([]*clerk.EventRecordWithTime, error) {
+		return client.StateSyncEvents(ctx, fromID, to)
+	})
+}
+
+func (c *HeimdallMultiClient) Span(ctx context.Context, spanID uint64) (*span.HeimdallSpan, error) {
+	return callQuorum(ctx, c, "span", func(ctx context.Context, client bor.IHeimdallClient) (*span.HeimdallSpan, error) {
+		return client.Span(ctx, spanID)
+	})
+}
+
+// FetchCheckpoint fetches the checkpoint from heimdall
+func (c *HeimdallMultiClient) FetchCheckpoint(ctx context.Context, number int64) (*checkpoint.Checkpoint, error) {
+	return call(ctx, c, "checkpoint", func(ctx context.Context, client bor.IHeimdallClient) (*checkpoint.Checkpoint, error) {
+		return client.FetchCheckpoint(ctx, number)
+	})
+}
+
+// FetchCheckpointCount fetches the checkpoint count from heimdall
+func (c *HeimdallMultiClient) FetchCheckpointCount(ctx context.Context) (int64, error) {
+	return call(ctx, c, "checkpoint-count", func(ctx context.Context, client bor.IHeimdallClient) (int64, error) {
+		return client.FetchCheckpointCount(ctx)
+	})
+}
+
+// FetchMilestone fetches the latest milestone from heimdall
+func (c *HeimdallMultiClient) FetchMilestone(ctx context.Context) (*milestone.Milestone, error) {
+	return callQuorum(ctx, c, "milestone", func(ctx context.Context, client bor.IHeimdallClient) (*milestone.Milestone, error) {
+		return client.FetchMilestone(ctx)
+	})
+}
+
+// FetchMilestoneCount fetches the milestone count from heimdall
+func (c *HeimdallMultiClient) FetchMilestoneCount(ctx context.Context) (int64, error) {
+	return call(ctx, c, "milestone-count", func(ctx context.Context, client bor.IHeimdallClient) (int64, error) {
+		return client.FetchMilestoneCount(ctx)
+	})
+}
+
+// FetchNoAckMilestone fetches whether the given milestone failed in heimdall
+func (c *HeimdallMultiClient) FetchNoAckMilestone(ctx context.Context, milestoneID string) error {
+	_, err := call(ctx, c, "milestone-no-ack", func(ctx context.Context, client bor.IHeimdallClient) (struct{}, error) {
+		return struct{}{}, client.FetchNoAckMilestone(ctx, milestoneID)
+	})
+
+	return err
+}
+
+// FetchLastNoAckMilestone fetches the last no-ack-milestone from heimdall
+func (c *HeimdallMultiClient) FetchLastNoAckMilestone(ctx context.Context) (string, error) {
+	return call(ctx, c, "milestone-last-no-ack", func(ctx context.Context, client bor.IHeimdallClient) (string, error) {
+		return client.FetchLastNoAckMilestone(ctx)
+	})
+}
+
+// FetchMilestoneID fetches whether the given milestone is in process in heimdall
+func (c *HeimdallMultiClient) FetchMilestoneID(ctx context.Context, milestoneID string) error {
+	_, err := call(ctx, c, "milestone-id", func(ctx context.Context, client bor.IHeimdallClient) (struct{}, error) {
+		return struct{}{}, client.FetchMilestoneID(ctx, milestoneID)
+	})
+
+	return err
+}
+
+// Close stops the pending requests and closes all the endpoints
+func (c *HeimdallMultiClient) Close() {
+	c.closeOnce.Do(func() {
+		close(c.closeCh)
+
+		for _, e := range c.endpoints {
+			e.Client.Close()
+		}
+	})
+}
@@ -0,0 +1,190 @@
+package heimdallmulti
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/consensus/bor/clerk"
+	"github.com/ethereum/go-ethereum/consensus/bor/heimdall"
+	"github.com/ethereum/go-ethereum/consensus/bor/heimdall/checkpoint"
+	"github.com/ethereum/go-ethereum/consensus/bor/heimdall/milestone"
+	"github.com/ethereum/go-ethereum/consensus/bor/heimdall/span"
+
+	"github.com/stretchr/testify/require"
+)
+
+var errEndpointDown = errors.New("endpoint down")
+
+// fakeClient is a Heimdall client answering milestones and spans with fixed
+// values, or failing when down.
+type fakeClient struct {
+	down      atomic.Bool
+	endBlock  int64
+	calls     atomic.Int32
+	closed    atomic.Bool
+	noAckList bool
+}
+
+func (f *fakeClient) err() error {
+	f.calls.Add(1)
+
+	if f.down.Load() {
+		return errEndpointDown
+	}
+
+	return nil
+}
+
+func (f *fakeClient) StateSyncEvents(context.Context, uint64, int64) ([]*clerk.EventRecordWithTime, error) {
+	return nil, f.err()
+}
+
+func (f *fakeClient) Span(_ context.Context, spanID uint64) (*span.HeimdallSpan, error) {
+	if err := f.err(); err != nil {
+		return nil, err
+	}
+
+	return &span.HeimdallSpan{Span: span.Span{ID: spanID, EndBlock: uint64(f.endBlock)}}, nil
+}
+
+func (f *fakeClient) FetchCheckpoint(context.Context, int64) (*checkpoint.Checkpoint, error) {
+	return nil, f.err()
+}
+
+func (f *fakeClient) FetchCheckpointCount(context.Context) (int64, error) {
+	return 0, f.err()
+}
+
+func (f *fakeClient) FetchMilestone(context.Context) (*milestone.Milestone, error) {
+	if err := f.err(); err != nil {
+		return nil, err
+	}
+
+	return &milestone.Milestone{StartBlock: big.NewInt(0), EndBlock: big.NewInt(f.endBlock)}, nil
+}
+
+func (f *fakeClient) FetchMilestoneCount(context.Context) (int64, error) {
+	return f.endBlock, f.err()
+}
+
+func (f *fakeClient) FetchNoAckMilestone(context.Context, string) error {
+	if err := f.err(); err != nil {
+		return err
+	}
+
+	if !f.noAckList {
+		return heimdall.ErrNotInRejectedList
+	}
+
+	return nil
+}
+
+func (f *fakeClient) FetchLastNoAckMilestone(context.Context) (string, error) {
+	return "", f.err()
+}
+
+func (f *fakeClient) FetchMilestoneID(context.Context, string) error {
+	return f.err()
+}
+
+func (f *fakeClient) Close() {
+	f.closed.Store(true)
+}
+
+func TestFailover(t *testing.T) {
+	t.Parallel()
+
+	first, second := &fakeClient{endBlock: 1}, &fakeClient{endBlock: 2}
+	first.down.Store(true)
+
+	client, err := NewHeimdallMultiClient([]Endpoint{{"first", first}, {"second", second}}, 0)
+	require.NoError(t, err)
+
+	defer client.Close()
+
+	count, err := client.FetchMilestoneCount(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, int64(2), count)
+
+	// The failing endpoint is cooling down and is not tried anymore
+	count, err = client.FetchMilestoneCount(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, int64(2), count)
+	require.Equal(t, int32(1), first.calls.Load())
+
+	// Answers of Heimdall are returned without failing over
+	err = client.FetchNoAckMilestone(context.Background(), "id")
+	require.ErrorIs(t, err, heimdall.ErrNotInRejectedList)
+	require.Equal(t, int32(3), second.calls.Load())
+}
+
+func TestAllEndpointsDown(t *testing.T) {
+	t.Parallel()
+
+	first := &fakeClient{}
+	first.down.Store(true)
+
+	client, err := NewHeimdallMultiClient([]Endpoint{{"first", first}}, 0)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	_, err = client.FetchMilestoneCount(ctx)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+
+	client.Close()
+	require.True(t, first.closed.Load())
+
+	_, err = client.FetchMilestoneCount(context.Background())
+	require.ErrorIs(t, err, heimdall.ErrShutdownDetected)
+}
+
+func TestQuorum(t *testing.T) {
+	t.Parallel()
+
+	var (
+		honest1 = &fakeClient{endBlock: 10}
+		faulty  = &fakeClient{endBlock: 20}
+		honest2 = &fakeClient{endBlock: 10}
+	)
+
+	client, err := NewHeimdallMultiClient([]Endpoint{{"faulty", faulty}, {"honest1", honest1}, {"honest2", honest2}}, 2)
+	require.NoError(t, err)
+
+	defer client.Close()
+
+	m, err := client.FetchMilestone(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, int64(10), m.EndBlock.Int64())
+
+	s, err := client.Span(context.Background(), 1)
+	require.NoError(t, err)
+	require.Equal(t, uint64(10), s.EndBlock)
+
+	// The endpoint disagreeing with the quorum is penalized
+	ordered := client.ordered()
+	require.Equal(t, "faulty", ordered[len(ordered)-1].Name)
+
+	// A penalty cools the endpoint down without sampling its latency
+	e := &endpoint{latency: 100}
+	e.penalize()
+
+	healthy, score, _ := e.score(time.Now())
+	require.False(t, healthy)
+	require.Equal(t, 200.0, score)
+}
+
+func TestInvalidQuorum(t *testing.T) {
+	t.Parallel()
+
+	_, err := NewHeimdallMultiClient(nil, 0)
+	require.ErrorIs(t, err, errNoEndpoints)
+
+	_, err = NewHeimdallMultiClient([]Endpoint{{"first", &fakeClient{}}, {"second", &fakeClient{}}}, 3)
+	require.Error(t, err)
+}
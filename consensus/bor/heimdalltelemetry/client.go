@@ -0,0 +1,232 @@
+// Package heimdalltelemetry implements a Heimdall client recording the metrics
+// and traces of the requests of any other Heimdall client, so that the HTTP,
+// gRPC, in-process and archive backends can be monitored alike.
+package heimdalltelemetry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/tracing"
+	"github.com/ethereum/go-ethereum/consensus/bor"
+	"github.com/ethereum/go-ethereum/consensus/bor/clerk"
+	"github.com/ethereum/go-ethereum/consensus/bor/heimdall"
+	"github.com/ethereum/go-ethereum/consensus/bor/heimdall/checkpoint"
+	"github.com/ethereum/go-ethereum/consensus/bor/heimdall/milestone"
+	"github.com/ethereum/go-ethereum/consensus/bor/heimdall/span"
+	"github.com/ethereum/go-ethereum/metrics"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	grpccodes "google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// instrumentationName is the name of the tracer used when none is set in the
+// context of a request.
+const instrumentationName = "heimdall"
+
+// Names of the requests, as used in the metrics and spans
+const (
+	stateSyncRequest          = "statesync"
+	spanRequest               = "span"
+	checkpointRequest         = "checkpoint"
+	checkpointCountRequest    = "checkpointcount"
+	milestoneRequest          = "milestone"
+	milestoneCountRequest     = "milestonecount"
+	milestoneNoAckRequest     = "milestonenoack"
+	milestoneLastNoAckRequest = "milestonelastnoack"
+	milestoneIDRequest        = "milestoneid"
+)
+
+// Classes of the request errors, as used in the metrics
+const (
+	errorCanceled    = "canceled"
+	errorTimeout     = "timeout"
+	errorShutdown    = "shutdown"
+	errorUnavailable = "unavailable"
+	errorNotFound    = "notfound"
+	errorOther       = "other"
+)
+
+var _ bor.IHeimdallClient = (*HeimdallTelemetryClient)(nil)
+
+// HeimdallTelemetryClient decorates a Heimdall client, recording for every
+// request its latency, the class of its error and its number of retries, and
+// tracing it with OpenTelemetry.
+type HeimdallTelemetryClient struct {
+	bor.IHeimdallClient
+
+	backend  string // Kind of the wrapped client, labelling the metrics
+	endpoint string // Address of the wrapped client, labelling the spans
+
+	registry metrics.Registry
+}
+
+// NewHeimdallTelemetryClient wraps the given client of a backend kind (http,
+// grpc, app or archive) connected to the given endpoint.
+func NewHeimdallTelemetryClient(client bor.IHeimdallClient, backend string, endpoint string) *HeimdallTelemetryClient {
+	return &HeimdallTelemetryClient{
+		IHeimdallClient: client,
+		backend:         backend,
+		endpoint:        endpoint,
+		registry:        metrics.DefaultRegistry,
+	}
+}
+
+// instrument runs a request, recording its metrics and span.
+func (c *HeimdallTelemetryClient) instrument(ctx context.Context, request string, fn func(context.Context) error, attrs ...attribute.KeyValue) error {
+	if tracing.FromContext(ctx) == nil {
+		ctx = tracing.WithTracer(ctx, otel.GetTracerProvider().Tracer(instrumentationName))
+	}
+
+	ctx, traceSpan := tracing.StartSpan(ctx, "heimdall."+request)
+	defer tracing.EndSpan(traceSpan)
+
+	ctx, attempts := heimdall.WithAttempts(ctx)
+
+	start := time.Now()
+	err := fn(ctx)
+	elapsed := time.Since(start)
+
+	// Clients not counting their requests made a single attempt
+	retries := attempts.Load() - 1
+	if retries < 0 {
+		retries = 0
+	}
+
+	prefix := fmt.Sprintf("client/%s/requests/%s/", c.backend, request)
+
+	metrics.GetOrRegisterTimer(prefix+"duration", c.registry).Update(elapsed)
+	metrics.GetOrRegisterCounter(prefix+"retries", c.registry).Inc(retries)
+
+	tracing.SetAttributes(traceSpan, append(attrs,
+		attribute.String("backend", c.backend),
+		attribute.String("endpoint", c.endpoint),
+		attribute.Int64("retries", retries),
+	)...)
+
+	if err != nil {
+		class := errorClass(err)
+
+		metrics.GetOrRegisterMeter(prefix+"errors/"+class, c.registry).Mark(1)
+
+		if traceSpan != nil {
+			traceSpan.RecordError(err)
+			traceSpan.SetStatus(codes.Error, class)
+		}
+	} else {
+		metrics.GetOrRegisterMeter(prefix+"success", c.registry).Mark(1)
+	}
+
+	return err
+}
+
+// errorClass classifies the error of a request.
+func errorClass(err error) string {
+	switch {
+	case errors.Is(err, context.Canceled):
+		return errorCanceled
+	case errors.Is(err, context.DeadlineExceeded):
+		return errorTimeout
+	case errors.Is(err, heimdall.ErrShutdownDetected):
+		return errorShutdown
+	case errors.Is(err, heimdall.ErrServiceUnavailable):
+		return errorUnavailable
+	case errors.Is(err, heimdall.ErrNotInRejectedList), errors.Is(err, heimdall.ErrNotInMilestoneList):
+		return errorNotFound
+	}
+
+	if s, ok := status.FromError(err); ok {
+		switch s.Code() {
+		case grpccodes.Canceled:
+			return errorCanceled
+		case grpccodes.DeadlineExceeded:
+			return errorTimeout
+		case grpccodes.Unavailable:
+			return errorUnavailable
+		case grpccodes.NotFound:
+			return errorNotFound
+		}
+	}
+
+	return errorOther
+}
+
+func (c *HeimdallTelemetryClient) StateSyncEvents(ctx context.Context, fromID uint64, to int64) (events []*clerk.EventRecordWithTime, err error) {
+	err = c.instrument(ctx, stateSyncRequest, func(ctx context.Context) (err error) {
+		events, err = c.IHeimdallClient.StateSyncEvents(ctx, fromID, to)
+		return err
+	}, attribute.Int64("fromID", int64(fromID)), attribute.Int64("to", to))
+
+	return events, err
+}
+
+func (c *HeimdallTelemetryClient) Span(ctx context.Context, spanID uint64) (response *span.HeimdallSpan, err error) {
+	err = c.instrument(ctx, spanRequest, func(ctx context.Context) (err error) {
+		response, err = c.IHeimdallClient.Span(ctx, spanID)
+		return err
+	}, attribute.Int64("id", int64(spanID)))
+
+	return response, err
+}
+
+func (c *HeimdallTelemetryClient) FetchCheckpoint(ctx context.Context, number int64) (response *checkpoint.Checkpoint, err error) {
+	err = c.instrument(ctx, checkpointRequest, func(ctx context.Context) (err error) {
+		response, err = c.IHeimdallClient.FetchCheckpoint(ctx, number)
+		return err
+	}, attribute.Int64("number", number))
+
+	return response, err
+}
+
+func (c *HeimdallTelemetryClient) FetchCheckpointCount(ctx context.Context) (count int64, err error) {
+	err = c.instrument(ctx, checkpointCountRequest, func(ctx context.Context) (err error) {
+		count, err = c.IHeimdallClient.FetchCheckpointCount(ctx)
+		return err
+	})
+
+	return count, err
+}
+
+func (c *HeimdallTelemetryClient) FetchMilestone(ctx context.Context) (response *milestone.Milestone, err error) {
+	err = c.instrument(ctx, milestoneRequest, func(ctx context.Context) (err error) {
+		response, err = c.IHeimdallClient.FetchMilestone(ctx)
+		return err
+	})
+
+	return response, err
+}
+
+func (c *HeimdallTelemetryClient) FetchMilestoneCount(ctx context.Context) (count int64, err error) {
+	err = c.instrument(ctx, milestoneCountRequest, func(ctx context.Context) (err error) {
+		count, err = c.IHeimdallClient.FetchMilestoneCount(ctx)
+		return err
+	})
+
+	return count, err
+}
+
+func (c *HeimdallTelemetryClient) FetchNoAckMilestone(ctx context.Context, milestoneID string) error {
+	return c.instrument(ctx, milestoneNoAckRequest, func(ctx context.Context) error {
+		return c.IHeimdallClient.FetchNoAckMilestone(ctx, milestoneID)
+	}, attribute.String("milestoneID", milestoneID))
+}
+
+func (c *HeimdallTelemetryClient) FetchLastNoAckMilestone(ctx context.Context) (milestoneID string, err error) {
+	err = c.instrument(ctx, milestoneLastNoAckRequest, func(ctx context.Context) (err error) {
+		milestoneID, err = c.IHeimdallClient.FetchLastNoAckMilestone(ctx)
+		return err
+	})
+
+	return milestoneID, err
+}
+
+func (c *HeimdallTelemetryClient) FetchMilestoneID(ctx context.Context, milestoneID string) error {
+	return c.instrument(ctx, milestoneIDRequest, func(ctx context.Context) error {
+		return c.IHeimdallClient.FetchMilestoneID(ctx, milestoneID)
+	}, attribute.String("milestoneID", milestoneID))
+}
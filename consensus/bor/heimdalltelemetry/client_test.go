@@ -0,0 +1,93 @@
+package heimdalltelemetry
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common/tracing"
+	"github.com/ethereum/go-ethereum/consensus/bor"
+	"github.com/ethereum/go-ethereum/consensus/bor/heimdall"
+	"github.com/ethereum/go-ethereum/consensus/bor/heimdall/span"
+	"github.com/ethereum/go-ethereum/metrics"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	grpccodes "google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// retryingHeimdall succeeds fetching spans after a few attempts, and fails
+// fetching the checkpoint count.
+type retryingHeimdall struct {
+	bor.IHeimdallClient
+
+	attempts int
+}
+
+func (h *retryingHeimdall) Span(ctx context.Context, spanID uint64) (*span.HeimdallSpan, error) {
+	for i := 0; i < h.attempts; i++ {
+		heimdall.CountAttempt(ctx)
+	}
+
+	return &span.HeimdallSpan{Span: span.Span{ID: spanID}}, nil
+}
+
+func (h *retryingHeimdall) FetchCheckpointCount(context.Context) (int64, error) {
+	return 0, fmt.Errorf("fetching: %w", context.DeadlineExceeded)
+}
+
+func TestTelemetryClient(t *testing.T) {
+	metrics.Enabled = true
+
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	ctx := tracing.WithTracer(context.Background(), provider.Tracer("test"))
+
+	client := NewHeimdallTelemetryClient(&retryingHeimdall{attempts: 3}, "http", "http://heimdall:1317")
+	client.registry = metrics.NewRegistry()
+
+	s, err := client.Span(ctx, 7)
+	require.NoError(t, err)
+	require.Equal(t, uint64(7), s.ID)
+
+	_, err = client.FetchCheckpointCount(ctx)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+
+	require.Equal(t, int64(2), client.registry.Get("client/http/requests/span/retries").(metrics.Counter).Snapshot().Count())
+	require.Equal(t, int64(1), client.registry.Get("client/http/requests/span/success").(metrics.Meter).Snapshot().Count())
+	require.Equal(t, int64(1), client.registry.Get("client/http/requests/span/duration").(metrics.Timer).Snapshot().Count())
+	require.Equal(t, int64(1), client.registry.Get("client/http/requests/checkpointcount/errors/timeout").(metrics.Meter).Snapshot().Count())
+	require.Nil(t, client.registry.Get("client/http/requests/checkpointcount/success"))
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 2)
+	require.Equal(t, "heimdall.span", spans[0].Name())
+	require.Contains(t, spans[0].Attributes(), attribute.Int64("retries", 2))
+	require.Contains(t, spans[0].Attributes(), attribute.String("backend", "http"))
+	require.Equal(t, "heimdall.checkpointcount", spans[1].Name())
+	require.Len(t, spans[1].Events(), 1)
+}
+
+func TestErrorClass(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		err   error
+		class string
+	}{
+		{context.Canceled, errorCanceled},
+		{heimdall.ErrShutdownDetected, errorShutdown},
+		{fmt.Errorf("%w: milestoneID %q", heimdall.ErrNotInRejectedList, "id"), errorNotFound},
+		{heimdall.ErrServiceUnavailable, errorUnavailable},
+		{status.Error(grpccodes.Unavailable, "connection refused"), errorUnavailable},
+		{status.Error(grpccodes.DeadlineExceeded, "deadline"), errorTimeout},
+		{heimdall.ErrNoResponse, errorOther},
+	}
+
+	for _, c := range cases {
+		require.Equal(t, c.class, errorClass(c.err), c.err.Error())
+	}
+}
@@ -0,0 +1,56 @@
+package bor
+
+import (
+	"context"
+	"errors"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus"
+	"github.com/ethereum/go-ethereum/consensus/bor/valset"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// ErrNoTurnInSpan is returned when the signer is not the primary producer of
+// any sprint left in the current span.
+var ErrNoTurnInSpan = errors.New("no producer turn in the current span")
+
+// NextProducerTurn predicts the first block of the next sprint the given signer
+// is the primary producer of, within the current span. The sprint following the
+// given header is included, so its first block is returned when the signer is
+// producing it already.
+func (c *Bor) NextProducerTurn(ctx context.Context, chain consensus.ChainHeaderReader, header *types.Header, signer common.Address) (uint64, error) {
+	span, err := c.spanner.GetCurrentSpan(ctx, header.Hash())
+	if err != nil {
+		return 0, err
+	}
+
+	number := header.Number.Uint64()
+
+	snap, err := c.snapshot(chain, number, header.Hash(), nil)
+	if err != nil {
+		return 0, err
+	}
+
+	next := number + 1
+	start := next - next%c.config.CalculateSprint(next)
+
+	return nextProducerTurn(snap.ValidatorSet.Copy(), signer, start, span.EndBlock, c.config)
+}
+
+// nextProducerTurn returns the first block of the next sprint produced by the
+// signer, starting from the sprint whose proposer is the one of the given
+// validator set. The proposer is rotated at the end of every sprint, the same
+// way the snapshot does, until the end of the span.
+func nextProducerTurn(validators *valset.ValidatorSet, signer common.Address, start uint64, spanEnd uint64, config *params.BorConfig) (uint64, error) {
+	for start <= spanEnd {
+		if validators.GetProposer().Address == signer {
+			return start, nil
+		}
+
+		validators.IncrementProposerPriority(1)
+		start += config.CalculateSprint(start)
+	}
+
+	return 0, ErrNoTurnInSpan
+}
@@ -0,0 +1,46 @@
+package bor
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/bor/valset"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+func TestNextProducerTurn(t *testing.T) {
+	t.Parallel()
+
+	config := &params.BorConfig{Sprint: map[string]uint64{"0": 16}}
+
+	validators := valset.NewValidatorSet([]*valset.Validator{
+		valset.NewValidator(common.HexToAddress("0x1"), 10),
+		valset.NewValidator(common.HexToAddress("0x2"), 10),
+		valset.NewValidator(common.HexToAddress("0x3"), 10),
+	})
+
+	// The proposers of the following sprints, rotated as by the snapshot
+	proposers := make([]common.Address, 0, 3)
+
+	rotated := validators.Copy()
+	for i := 0; i < 3; i++ {
+		proposers = append(proposers, rotated.GetProposer().Address)
+		rotated.IncrementProposerPriority(1)
+	}
+
+	for i, proposer := range proposers {
+		turn, err := nextProducerTurn(validators.Copy(), proposer, 64, 6655, config)
+		require.NoError(t, err)
+		require.Equal(t, 64+16*uint64(i), turn)
+	}
+
+	// No turn before the end of the span
+	_, err := nextProducerTurn(validators.Copy(), proposers[2], 64, 64+16*2-1, config)
+	require.ErrorIs(t, err, ErrNoTurnInSpan)
+
+	// Not a validator
+	_, err = nextProducerTurn(validators.Copy(), common.HexToAddress("0x4"), 64, 6655, config)
+	require.ErrorIs(t, err, ErrNoTurnInSpan)
+}
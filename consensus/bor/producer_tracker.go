@@ -0,0 +1,332 @@
+package bor
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/event"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
+)
+
+const (
+	// producerTrackerWindow is the number of recent blocks whose producer is
+	// kept to detect the blocks reorged out of the chain
+	producerTrackerWindow = 1024
+
+	// producerTrackerMaxDepth is the maximum number of blocks processed on a
+	// new head, bounding the work when the head jumps during a sync
+	producerTrackerMaxDepth = 256
+)
+
+// TrackerChain is the chain followed by the producer tracker.
+type TrackerChain interface {
+	consensus.ChainHeaderReader
+
+	SubscribeChainHeadEvent(ch chan<- core.ChainHeadEvent) event.Subscription
+}
+
+// ProducerStats is the block production performance of a signer since the
+// tracker started.
+type ProducerStats struct {
+	Address common.Address `json:"address"`
+
+	// Expected is the number of blocks the signer was the primary producer of
+	Expected uint64 `json:"expected"`
+
+	// InTurn and OutOfTurn are the number of blocks the signer produced as the
+	// primary producer and as a backup of another signer
+	InTurn    uint64 `json:"inTurn"`
+	OutOfTurn uint64 `json:"outOfTurn"`
+
+	// Missed is the number of blocks the signer was the primary producer of
+	// but which were produced by a backup
+	Missed uint64 `json:"missed"`
+
+	// Reorged is the number of blocks of the signer reorged out of the chain
+	Reorged uint64 `json:"reorged"`
+
+	// Successions is the number of blocks produced out-of-turn per succession
+	// number, and BackupDelay the total delay in seconds they added over the
+	// in-turn production, as computed by CalcProducerDelay
+	Successions map[int]uint64 `json:"successions"`
+	BackupDelay uint64         `json:"backupDelay"`
+}
+
+var (
+	// The producer metrics aggregated over all the signers
+	producerInTurnCounter    = metrics.NewRegisteredCounter("bor/producer/inturn", nil)
+	producerOutOfTurnCounter = metrics.NewRegisteredCounter("bor/producer/outofturn", nil)
+	producerMissedCounter    = metrics.NewRegisteredCounter("bor/producer/missed", nil)
+	producerReorgedCounter   = metrics.NewRegisteredCounter("bor/producer/reorged", nil)
+	producerDelayCounter     = metrics.NewRegisteredCounter("bor/producer/backupdelay", nil)
+)
+
+// producerMetrics are the metrics of a signer.
+type producerMetrics struct {
+	inTurn    metrics.Counter
+	outOfTurn metrics.Counter
+	missed    metrics.Counter
+	reorged   metrics.Counter
+	delay     metrics.Counter
+}
+
+func newProducerMetrics(signer common.Address) *producerMetrics {
+	prefix := fmt.Sprintf("bor/producer/%s/", signer.Hex())
+
+	return &producerMetrics{
+		inTurn:    metrics.GetOrRegisterCounter(prefix+"inturn", nil),
+		outOfTurn: metrics.GetOrRegisterCounter(prefix+"outofturn", nil),
+		missed:    metrics.GetOrRegisterCounter(prefix+"missed", nil),
+		reorged:   metrics.GetOrRegisterCounter(prefix+"reorged", nil),
+		delay:     metrics.GetOrRegisterCounter(prefix+"backupdelay", nil),
+	}
+}
+
+// trackedBlock is a block processed by the tracker, with its contribution to
+// the stats reverted when it is reorged out.
+type trackedBlock struct {
+	hash       common.Hash
+	author     common.Address
+	primary    common.Address
+	succession int
+	delay      uint64
+}
+
+// ProducerTracker follows the chain head, comparing the producer of every
+// block with the primary producer expected by the snapshot of its parent.
+type ProducerTracker struct {
+	bor   *Bor
+	chain TrackerChain
+
+	stats   map[common.Address]*ProducerStats
+	metrics map[common.Address]*producerMetrics
+	blocks  map[uint64]trackedBlock
+	head    uint64
+	lock    sync.RWMutex
+
+	sub  event.Subscription
+	quit chan struct{}
+	wg   sync.WaitGroup
+}
+
+// NewProducerTracker creates a tracker of the producers of the given chain.
+func NewProducerTracker(bor *Bor, chain TrackerChain) *ProducerTracker {
+	return &ProducerTracker{
+		bor:     bor,
+		chain:   chain,
+		stats:   make(map[common.Address]*ProducerStats),
+		metrics: make(map[common.Address]*producerMetrics),
+		blocks:  make(map[uint64]trackedBlock),
+		quit:    make(chan struct{}),
+	}
+}
+
+// Start starts following the chain head.
+func (t *ProducerTracker) Start() {
+	heads := make(chan core.ChainHeadEvent, 16)
+	t.sub = t.chain.SubscribeChainHeadEvent(heads)
+
+	t.wg.Add(1)
+
+	go func() {
+		defer t.wg.Done()
+
+		for {
+			select {
+			case ev := <-heads:
+				t.ProcessHead(ev.Block.Header())
+			case <-t.sub.Err():
+				return
+			case <-t.quit:
+				return
+			}
+		}
+	}()
+}
+
+// Stop stops following the chain head.
+func (t *ProducerTracker) Stop() {
+	if t.sub != nil {
+		t.sub.Unsubscribe()
+	}
+
+	close(t.quit)
+	t.wg.Wait()
+}
+
+// ProcessHead processes the blocks of the chain up to the given head not yet
+// processed, counting the ones replaced since as reorged.
+func (t *ProducerTracker) ProcessHead(head *types.Header) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	number := head.Number.Uint64()
+
+	// Blocks above a lower head were rewound
+	for n := number + 1; n <= t.head; n++ {
+		t.reorged(n)
+	}
+
+	var headers []*types.Header
+
+	for header := head; header != nil && header.Number.Uint64() > 0 && len(headers) < producerTrackerMaxDepth; {
+		n := header.Number.Uint64()
+
+		if block, ok := t.blocks[n]; ok {
+			if block.hash == header.Hash() {
+				break
+			}
+
+			t.reorged(n)
+		} else if t.head == 0 || n <= t.head {
+			// The first head, or a block older than the tracked ones
+			headers = append(headers, header)
+			break
+		}
+
+		headers = append(headers, header)
+		header = t.chain.GetHeader(header.ParentHash, n-1)
+	}
+
+	for i := len(headers) - 1; i >= 0; i-- {
+		if err := t.process(headers[i]); err != nil {
+			log.Debug("Failed to track block producer", "number", headers[i].Number, "hash", headers[i].Hash(), "err", err)
+		}
+	}
+
+	t.head = number
+
+	for n := range t.blocks {
+		if n+producerTrackerWindow <= number {
+			delete(t.blocks, n)
+		}
+	}
+}
+
+// process records the producer of a block.
+func (t *ProducerTracker) process(header *types.Header) error {
+	number := header.Number.Uint64()
+
+	p, err := t.bor.blockProducer(t.chain, header)
+	if err != nil {
+		return err
+	}
+
+	block := trackedBlock{hash: header.Hash(), author: p.address, primary: p.primary, succession: p.succession}
+	if p.succession > 0 {
+		block.delay = CalcProducerDelay(number, p.succession, t.bor.config) - CalcProducerDelay(number, 0, t.bor.config)
+	}
+
+	t.account(block, 1)
+	t.blocks[number] = block
+
+	return nil
+}
+
+// reorged counts the tracked block with the given number as reorged out,
+// reverting its contribution to the stats.
+func (t *ProducerTracker) reorged(number uint64) {
+	block, ok := t.blocks[number]
+	if !ok {
+		return
+	}
+
+	t.account(block, -1)
+
+	t.stat(block.author).Reorged++
+	t.signerMetrics(block.author).reorged.Inc(1)
+	producerReorgedCounter.Inc(1)
+
+	delete(t.blocks, number)
+}
+
+// account adds (sign 1) or removes (sign -1) the contribution of a block to
+// the stats and metrics.
+func (t *ProducerTracker) account(block trackedBlock, sign int64) {
+	var (
+		author  = t.stat(block.author)
+		primary = t.stat(block.primary)
+		delta   = uint64(sign) // wraps around to subtract
+	)
+
+	primary.Expected += delta
+
+	if block.succession == 0 {
+		author.InTurn += delta
+		t.signerMetrics(block.author).inTurn.Inc(sign)
+		producerInTurnCounter.Inc(sign)
+
+		return
+	}
+
+	author.OutOfTurn += delta
+	author.Successions[block.succession] += delta
+	author.BackupDelay += delta * block.delay
+	primary.Missed += delta
+
+	if author.Successions[block.succession] == 0 {
+		delete(author.Successions, block.succession)
+	}
+
+	authorMetrics := t.signerMetrics(block.author)
+	authorMetrics.outOfTurn.Inc(sign)
+	authorMetrics.delay.Inc(sign * int64(block.delay))
+	t.signerMetrics(block.primary).missed.Inc(sign)
+
+	producerOutOfTurnCounter.Inc(sign)
+	producerDelayCounter.Inc(sign * int64(block.delay))
+	producerMissedCounter.Inc(sign)
+}
+
+func (t *ProducerTracker) stat(signer common.Address) *ProducerStats {
+	s, ok := t.stats[signer]
+	if !ok {
+		s = &ProducerStats{Address: signer, Successions: make(map[int]uint64)}
+		t.stats[signer] = s
+	}
+
+	return s
+}
+
+// signerMetrics returns the metrics of a signer, registered the first time it
+// is seen.
+func (t *ProducerTracker) signerMetrics(signer common.Address) *producerMetrics {
+	m, ok := t.metrics[signer]
+	if !ok {
+		m = newProducerMetrics(signer)
+		t.metrics[signer] = m
+	}
+
+	return m
+}
+
+// Stats returns the stats of every signer seen, sorted by address.
+func (t *ProducerTracker) Stats() []*ProducerStats {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	stats := make([]*ProducerStats, 0, len(t.stats))
+
+	for _, s := range t.stats {
+		cpy := *s
+
+		cpy.Successions = make(map[int]uint64, len(s.Successions))
+		for succession, count := range s.Successions {
+			cpy.Successions[succession] = count
+		}
+
+		stats = append(stats, &cpy)
+	}
+
+	sort.Slice(stats, func(i, j int) bool {
+		return stats[i].Address.Cmp(stats[j].Address) < 0
+	})
+
+	return stats
+}
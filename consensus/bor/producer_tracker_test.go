@@ -0,0 +1,52 @@
+package bor
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/metrics"
+)
+
+func TestProducerTrackerSignerMetrics(t *testing.T) {
+	metrics.Enabled = true
+
+	var (
+		tracker = NewProducerTracker(nil, nil)
+		primary = common.HexToAddress("0x00000000000000000000000000000000000a11ce")
+		backup  = common.HexToAddress("0x0000000000000000000000000000000000000b0b")
+	)
+
+	count := func(signer common.Address, name string) int64 {
+		counter := metrics.DefaultRegistry.Get(fmt.Sprintf("bor/producer/%s/%s", signer.Hex(), name))
+		require.NotNil(t, counter, "metric %s of %s", name, signer)
+
+		return counter.(metrics.Counter).Snapshot().Count()
+	}
+
+	// An in-turn block of the primary, then a block it missed, produced by the
+	// backup, which is reorged out
+	for number, block := range []trackedBlock{
+		{author: primary, primary: primary},
+		{author: backup, primary: primary, succession: 1, delay: 4},
+	} {
+		tracker.account(block, 1)
+		tracker.blocks[uint64(number+1)] = block
+	}
+
+	require.Equal(t, int64(1), count(primary, "inturn"))
+	require.Equal(t, int64(1), count(primary, "missed"))
+	require.Equal(t, int64(1), count(backup, "outofturn"))
+	require.Equal(t, int64(4), count(backup, "backupdelay"))
+
+	tracker.reorged(2)
+
+	require.Equal(t, int64(1), count(primary, "inturn"))
+	require.Equal(t, int64(0), count(primary, "missed"))
+	require.Equal(t, int64(0), count(backup, "outofturn"))
+	require.Equal(t, int64(0), count(backup, "backupdelay"))
+	require.Equal(t, int64(1), count(backup, "reorged"))
+	require.Equal(t, int64(0), count(primary, "reorged"))
+}
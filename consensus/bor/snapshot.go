@@ -8,6 +8,7 @@ import (
 	lru "github.com/hashicorp/golang-lru"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/ethdb"
 	"github.com/ethereum/go-ethereum/params"
@@ -49,7 +50,7 @@ func newSnapshot(
 
 // loadSnapshot loads an existing snapshot from the database.
 func loadSnapshot(chainConfig *params.ChainConfig, config *params.BorConfig, sigcache *lru.ARCCache, db ethdb.Database, hash common.Hash) (*Snapshot, error) {
-	blob, err := db.Get(append([]byte("bor-"), hash[:]...))
+	blob, err := db.Get(append(rawdb.BorSnapshotPrefix, hash[:]...))
 	if err != nil {
 		return nil, err
 	}
@@ -80,7 +81,7 @@ func (s *Snapshot) store(db ethdb.Database) error {
 		return err
 	}
 
-	return db.Put(append([]byte("bor-"), s.Hash[:]...), blob)
+	return db.Put(append(rawdb.BorSnapshotPrefix, s.Hash[:]...), blob)
 }
 
 // copy creates a deep copy of the snapshot, though not the individual votes.
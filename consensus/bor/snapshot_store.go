@@ -0,0 +1,253 @@
+package bor
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/bor/valset"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+const (
+	// snapshotsKept is the number of most recent finalized snapshots kept
+	snapshotsKept = 8
+
+	// snapshotSparseInterval is the interval of the older finalized snapshots
+	// kept, bounding the headers replayed to retrieve a historical snapshot
+	snapshotSparseInterval = 64 * checkpointInterval
+
+	// snapshotLookback is the number of checkpoint intervals searched below
+	// the head for the latest stored snapshot
+	snapshotLookback = 64
+)
+
+var (
+	errNoSnapshot          = errors.New("no bor snapshot stored")
+	errNotCheckpoint       = fmt.Errorf("snapshot not at a multiple of %d blocks", checkpointInterval)
+	errNoSnapshotValidator = errors.New("snapshot without validators")
+	errUnknownSnapshot     = errors.New("snapshot of an unknown block, the headers up to it must be synced first")
+	errSnapshotMismatch    = errors.New("snapshot validators don't match the validators of the headers")
+)
+
+// storedSnapshot is a snapshot stored in the database.
+type storedSnapshot struct {
+	Number uint64      `json:"number"`
+	Hash   common.Hash `json:"hash"`
+}
+
+// PruneSnapshots deletes the snapshots of finalized blocks which are no more
+// needed: the ones of blocks reorged out of the chain, and the canonical ones
+// except the most recent and one every snapshotSparseInterval blocks. The
+// snapshots above the finalized block are kept.
+func PruneSnapshots(db ethdb.Database, finalized uint64) (int, error) {
+	var (
+		canonical []storedSnapshot
+		deleted   int
+	)
+
+	batch := db.NewBatch()
+	it := db.NewIterator(rawdb.BorSnapshotPrefix, nil)
+
+	for it.Next() {
+		if len(it.Key()) != len(rawdb.BorSnapshotPrefix)+common.HashLength {
+			continue
+		}
+
+		var snap storedSnapshot
+		if err := json.Unmarshal(it.Value(), &snap); err != nil {
+			log.Warn("Invalid bor snapshot", "key", common.Bytes2Hex(it.Key()), "err", err)
+			continue
+		}
+
+		if snap.Number > finalized {
+			continue
+		}
+
+		if rawdb.ReadCanonicalHash(db, snap.Number) != snap.Hash {
+			if err := batch.Delete(it.Key()); err != nil {
+				it.Release()
+				return 0, err
+			}
+
+			deleted++
+
+			continue
+		}
+
+		canonical = append(canonical, snap)
+	}
+
+	it.Release()
+
+	if err := it.Error(); err != nil {
+		return 0, err
+	}
+
+	sort.Slice(canonical, func(i, j int) bool {
+		return canonical[i].Number > canonical[j].Number
+	})
+
+	for i, snap := range canonical {
+		if i < snapshotsKept || snap.Number%snapshotSparseInterval == 0 {
+			continue
+		}
+
+		if err := batch.Delete(append(rawdb.BorSnapshotPrefix, snap.Hash[:]...)); err != nil {
+			return 0, err
+		}
+
+		deleted++
+	}
+
+	if err := batch.Write(); err != nil {
+		return 0, err
+	}
+
+	return deleted, nil
+}
+
+// pruneSnapshots prunes the snapshots in the background, up to the block last
+// finalized by a milestone.
+func (c *Bor) pruneSnapshots() {
+	if !c.pruningSnapshots.CompareAndSwap(false, true) {
+		return
+	}
+
+	go func() {
+		defer c.pruningSnapshots.Store(false)
+
+		finalized, _, err := rawdb.ReadFinality[*rawdb.Milestone](c.db)
+		if err != nil {
+			return
+		}
+
+		if _, err := PruneSnapshots(c.db, finalized); err != nil {
+			log.Warn("Failed to prune bor snapshots", "err", err)
+		}
+	}()
+}
+
+// LatestSnapshot returns the number of the latest canonical snapshot stored.
+func LatestSnapshot(db ethdb.Database) (uint64, error) {
+	head := rawdb.ReadHeadHeaderHash(db)
+
+	number := rawdb.ReadHeaderNumber(db, head)
+	if number == nil {
+		return 0, errNoSnapshot
+	}
+
+	n := *number - *number%checkpointInterval
+
+	for i := 0; i < snapshotLookback; i++ {
+		if blob, _ := db.Get(append(rawdb.BorSnapshotPrefix, rawdb.ReadCanonicalHash(db, n).Bytes()...)); len(blob) > 0 {
+			return n, nil
+		}
+
+		if n < checkpointInterval {
+			break
+		}
+
+		n -= checkpointInterval
+	}
+
+	return 0, errNoSnapshot
+}
+
+// ExportSnapshot returns the stored canonical snapshot of the given block.
+func ExportSnapshot(db ethdb.Database, number uint64) ([]byte, error) {
+	hash := rawdb.ReadCanonicalHash(db, number)
+
+	blob, err := db.Get(append(rawdb.BorSnapshotPrefix, hash[:]...))
+	if err != nil || len(blob) == 0 {
+		return nil, fmt.Errorf("%w at block %d", errNoSnapshot, number)
+	}
+
+	return blob, nil
+}
+
+// ImportSnapshot validates and stores an exported snapshot, returning it. The
+// snapshot is used in place of replaying the headers below its block, which
+// must be known as the validator set is checked against the one announced by
+// the sprint end header preceding it.
+func ImportSnapshot(db ethdb.Database, blob []byte) (*Snapshot, error) {
+	snap := new(Snapshot)
+
+	if err := json.Unmarshal(blob, snap); err != nil {
+		return nil, err
+	}
+
+	if snap.Number%checkpointInterval != 0 {
+		return nil, errNotCheckpoint
+	}
+
+	if snap.ValidatorSet == nil || len(snap.ValidatorSet.Validators) == 0 {
+		return nil, errNoSnapshotValidator
+	}
+
+	snap.ValidatorSet.UpdateValidatorMap()
+
+	if err := snap.ValidatorSet.UpdateTotalVotingPower(); err != nil {
+		return nil, err
+	}
+
+	if hash := rawdb.ReadCanonicalHash(db, snap.Number); hash != (common.Hash{}) && hash != snap.Hash {
+		return nil, fmt.Errorf("snapshot of block %d is not canonical: have %s, want %s", snap.Number, snap.Hash, hash)
+	}
+
+	if err := verifySnapshotValidators(db, snap); err != nil {
+		return nil, err
+	}
+
+	if err := db.Put(append(rawdb.BorSnapshotPrefix, snap.Hash[:]...), blob); err != nil {
+		return nil, err
+	}
+
+	return snap, nil
+}
+
+// verifySnapshotValidators checks the validator set of a snapshot against the
+// validators announced by the sprint end header preceding its block, which the
+// snapshot adopted when applying it.
+func verifySnapshotValidators(db ethdb.Database, snap *Snapshot) error {
+	config := rawdb.ReadChainConfig(db, rawdb.ReadCanonicalHash(db, 0))
+	if config == nil || config.Bor == nil {
+		return errors.New("bor chain config not found")
+	}
+
+	if snap.Number == 0 || !config.Bor.IsSprintStart(snap.Number) {
+		return fmt.Errorf("%w: block %d doesn't follow a sprint end", errSnapshotMismatch, snap.Number)
+	}
+
+	header := rawdb.ReadHeader(db, snap.Hash, snap.Number)
+	if header == nil {
+		return errUnknownSnapshot
+	}
+
+	parent := rawdb.ReadHeader(db, header.ParentHash, snap.Number-1)
+	if parent == nil {
+		return errUnknownSnapshot
+	}
+
+	validators, err := valset.ParseValidators(parent.GetValidatorBytes(config))
+	if err != nil {
+		return err
+	}
+
+	if len(validators) != len(snap.ValidatorSet.Validators) {
+		return fmt.Errorf("%w: have %d validators, want %d", errSnapshotMismatch, len(snap.ValidatorSet.Validators), len(validators))
+	}
+
+	for _, want := range validators {
+		_, have := snap.ValidatorSet.GetByAddress(want.Address)
+		if have == nil || have.VotingPower != want.VotingPower {
+			return fmt.Errorf("%w: validator %s", errSnapshotMismatch, want.Address)
+		}
+	}
+
+	return nil
+}
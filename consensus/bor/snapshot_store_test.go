@@ -0,0 +1,162 @@
+package bor
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/bor/valset"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+func storeTestSnapshot(t *testing.T, db ethdb.Database, number uint64, hash common.Hash) {
+	t.Helper()
+
+	validators := []*valset.Validator{valset.NewValidator(common.HexToAddress("0x1"), 10)}
+	require.NoError(t, newSnapshot(nil, nil, number, hash, validators).store(db))
+}
+
+func hasSnapshot(db ethdb.Database, hash common.Hash) bool {
+	ok, _ := db.Has(append(rawdb.BorSnapshotPrefix, hash[:]...))
+	return ok
+}
+
+func TestPruneSnapshots(t *testing.T) {
+	t.Parallel()
+
+	db := rawdb.NewMemoryDatabase()
+
+	hashes := make([]common.Hash, 20)
+
+	for i := range hashes {
+		number := uint64(i) * checkpointInterval
+		hashes[i] = common.BigToHash(new(big.Int).SetUint64(number + 1))
+
+		rawdb.WriteCanonicalHash(db, hashes[i], number)
+		storeTestSnapshot(t, db, number, hashes[i])
+	}
+
+	// A snapshot reorged out of the chain
+	side := common.HexToHash("0xdead")
+	storeTestSnapshot(t, db, 5*checkpointInterval, side)
+
+	rawdb.WriteHeadHeaderHash(db, hashes[19])
+	rawdb.WriteHeaderNumber(db, hashes[19], 19*checkpointInterval)
+
+	deleted, err := PruneSnapshots(db, 17*checkpointInterval)
+	require.NoError(t, err)
+	require.Equal(t, 10, deleted)
+
+	require.False(t, hasSnapshot(db, side))
+
+	for i, hash := range hashes {
+		// The genesis one is kept as sparse snapshot, along with the 8 most
+		// recent finalized ones and the ones not finalized yet
+		kept := i == 0 || i >= 10
+		require.Equal(t, kept, hasSnapshot(db, hash), "snapshot %d", i)
+	}
+
+	latest, err := LatestSnapshot(db)
+	require.NoError(t, err)
+	require.Equal(t, uint64(19*checkpointInterval), latest)
+}
+
+// writeSnapshotHeaders writes the chain config and the header of the given
+// block, preceded by a sprint end header announcing the given validator.
+func writeSnapshotHeaders(t *testing.T, db ethdb.Database, number uint64, validator common.Address, power int64) common.Hash {
+	t.Helper()
+
+	genesis := common.HexToHash("0x01")
+	rawdb.WriteCanonicalHash(db, genesis, 0)
+	rawdb.WriteChainConfig(db, genesis, &params.ChainConfig{ChainID: big.NewInt(137), Bor: &params.BorConfig{Sprint: map[string]uint64{"0": 16}}})
+
+	extra := make([]byte, types.ExtraVanityLength)
+	extra = append(extra, validator.Bytes()...)
+	extra = append(extra, common.LeftPadBytes(big.NewInt(power).Bytes(), 20)...)
+	extra = append(extra, make([]byte, types.ExtraSealLength)...)
+
+	parent := &types.Header{Number: new(big.Int).SetUint64(number - 1), Extra: extra}
+	header := &types.Header{Number: new(big.Int).SetUint64(number), ParentHash: parent.Hash()}
+
+	rawdb.WriteHeader(db, parent)
+	rawdb.WriteHeader(db, header)
+
+	return header.Hash()
+}
+
+func TestExportImportSnapshot(t *testing.T) {
+	t.Parallel()
+
+	var (
+		source = rawdb.NewMemoryDatabase()
+		target = rawdb.NewMemoryDatabase()
+		number = uint64(3 * checkpointInterval)
+		hash   = writeSnapshotHeaders(t, source, number, common.HexToAddress("0x1"), 10)
+	)
+
+	rawdb.WriteCanonicalHash(source, hash, number)
+	storeTestSnapshot(t, source, number, hash)
+
+	_, err := ExportSnapshot(source, number+checkpointInterval)
+	require.ErrorIs(t, err, errNoSnapshot)
+
+	blob, err := ExportSnapshot(source, number)
+	require.NoError(t, err)
+
+	// The snapshot of an unknown block is rejected
+	_, err = ImportSnapshot(target, blob)
+	require.Error(t, err)
+
+	// A different canonical block in the target rejects the snapshot
+	writeSnapshotHeaders(t, target, number, common.HexToAddress("0x1"), 10)
+	rawdb.WriteCanonicalHash(target, types.EmptyRootHash, number)
+
+	_, err = ImportSnapshot(target, blob)
+	require.Error(t, err)
+
+	rawdb.WriteCanonicalHash(target, hash, number)
+
+	snap, err := ImportSnapshot(target, blob)
+	require.NoError(t, err)
+	require.Equal(t, number, snap.Number)
+	require.Equal(t, int64(10), snap.ValidatorSet.TotalVotingPower())
+
+	loaded, err := loadSnapshot(nil, nil, nil, target, hash)
+	require.NoError(t, err)
+	require.Equal(t, hash, loaded.Hash)
+}
+
+func TestImportSnapshotValidators(t *testing.T) {
+	t.Parallel()
+
+	number := uint64(checkpointInterval)
+
+	// The validators announced by the headers differ from the snapshot ones,
+	// holding 0x1 with a power of 10
+	for _, tt := range []struct {
+		validator common.Address
+		power     int64
+	}{
+		{common.HexToAddress("0x2"), 10},
+		{common.HexToAddress("0x1"), 20},
+	} {
+		source, target := rawdb.NewMemoryDatabase(), rawdb.NewMemoryDatabase()
+
+		hash := writeSnapshotHeaders(t, source, number, tt.validator, tt.power)
+		rawdb.WriteCanonicalHash(source, hash, number)
+		storeTestSnapshot(t, source, number, hash)
+
+		blob, err := ExportSnapshot(source, number)
+		require.NoError(t, err)
+
+		writeSnapshotHeaders(t, target, number, tt.validator, tt.power)
+
+		_, err = ImportSnapshot(target, blob)
+		require.ErrorIs(t, err, errSnapshotMismatch)
+	}
+}
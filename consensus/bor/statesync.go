@@ -0,0 +1,139 @@
+package bor
+
+import (
+	"context"
+	"errors"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus"
+	"github.com/ethereum/go-ethereum/consensus/bor/clerk"
+	"github.com/ethereum/go-ethereum/consensus/bor/statefull"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+var (
+	// ErrNotSprintStart is returned when looking up the state-syncs of a block
+	// which does not start a sprint, as only those commit state-sync events.
+	ErrNotSprintStart = errors.New("not a sprint start block")
+
+	// stateCommittedTopic is the topic of the StateCommitted(stateId, success)
+	// event emitted by the state receiver contract for every committed event.
+	stateCommittedTopic = crypto.Keccak256Hash([]byte("StateCommitted(uint256,bool)"))
+)
+
+// StateSyncResult is the outcome of applying a state-sync event.
+type StateSyncResult struct {
+	Record  *clerk.EventRecordWithTime `json:"record"`
+	Applied bool                       `json:"applied"`
+	GasUsed uint64                     `json:"gasUsed"`
+	Success bool                       `json:"success"`
+	Error   string                     `json:"error,omitempty"`
+}
+
+// AppliedStateSync is a state-sync event committed by a block, as logged by the
+// state receiver contract, with the gas it used and the logs emitted by its
+// receiver.
+type AppliedStateSync struct {
+	ID      uint64       `json:"id"`
+	Success bool         `json:"success"`
+	GasUsed uint64       `json:"gasUsed"`
+	Logs    []*types.Log `json:"logs"`
+}
+
+// AppliedStateSyncs returns the state-sync events committed by a block from the
+// logs of its state-sync receipt, in the order they were applied.
+func (c *Bor) AppliedStateSyncs(logs []*types.Log) []*AppliedStateSync {
+	var (
+		applied = make([]*AppliedStateSync, 0)
+		pending []*types.Log
+
+		validatorSet = common.HexToAddress(c.config.ValidatorContract)
+	)
+
+	for _, log := range logs {
+		// The span committed before the state-syncs is not part of any event
+		if log.Address == validatorSet {
+			continue
+		}
+
+		id, success, ok := c.stateCommittedLog(log)
+		if !ok {
+			pending = append(pending, log)
+			continue
+		}
+
+		applied = append(applied, &AppliedStateSync{ID: id, Success: success, Logs: pending})
+		pending = nil
+	}
+
+	return applied
+}
+
+// StateSyncGas commits again the state-sync events of a sprint-start block on
+// the state its transactions left, as done when finalizing it, and returns the
+// gas used by each event by id. The state is modified.
+func (c *Bor) StateSyncGas(ctx context.Context, state *state.StateDB, header *types.Header, chain consensus.ChainHeaderReader) (map[uint64]uint64, error) {
+	gasUsed := make(map[uint64]uint64)
+	if c.HeimdallClient == nil {
+		return gasUsed, nil
+	}
+
+	cx := statefull.ChainContext{Chain: chain, Bor: c}
+	if err := c.checkAndCommitSpan(ctx, state, header, cx); err != nil {
+		return nil, err
+	}
+
+	stateSyncs, stateSyncGas, err := c.commitStates(ctx, state, header, cx)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, stateSync := range stateSyncs {
+		gasUsed[stateSync.ID] = stateSyncGas[i]
+	}
+
+	return gasUsed, nil
+}
+
+// SimulateStateSync applies a state-sync event on the given state, regardless
+// of whether it follows the last committed one.
+func (c *Bor) SimulateStateSync(eventRecord *clerk.EventRecordWithTime, state *state.StateDB, header *types.Header, chain consensus.ChainHeaderReader) (*StateSyncResult, error) {
+	state.SetTxContext(common.Hash{}, 0)
+
+	gasUsed, err := c.GenesisContractsClient.CommitState(eventRecord, state, header, statefull.ChainContext{Chain: chain, Bor: c})
+	if err != nil {
+		return nil, err
+	}
+
+	return &StateSyncResult{
+		Record:  eventRecord,
+		Applied: true,
+		GasUsed: gasUsed,
+		Success: c.stateCommitted(state, eventRecord.ID),
+	}, nil
+}
+
+// stateCommitted reports whether the receiver of the state-sync event with the
+// given id succeeded, as logged by the state receiver contract.
+func (c *Bor) stateCommitted(state *state.StateDB, id uint64) bool {
+	for _, log := range state.GetLogs(common.Hash{}, 0, common.Hash{}) {
+		if committed, success, ok := c.stateCommittedLog(log); ok && committed == id {
+			return success
+		}
+	}
+
+	return false
+}
+
+// stateCommittedLog decodes the StateCommitted log of the state receiver
+// contract, returning the id of the committed event and whether its receiver
+// succeeded.
+func (c *Bor) stateCommittedLog(log *types.Log) (uint64, bool, bool) {
+	if log.Address != common.HexToAddress(c.config.StateReceiverContract) || len(log.Topics) < 2 || log.Topics[0] != stateCommittedTopic {
+		return 0, false, false
+	}
+
+	return log.Topics[1].Big().Uint64(), len(log.Data) > 0 && log.Data[len(log.Data)-1] != 0, true
+}
@@ -0,0 +1,70 @@
+package bor
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+func TestAppliedStateSyncs(t *testing.T) {
+	t.Parallel()
+
+	var (
+		validatorSet = common.HexToAddress("0x0000000000000000000000000000000000001000")
+		receiver     = common.HexToAddress("0x0000000000000000000000000000000000001001")
+		target       = common.HexToAddress("0x0000000000000000000000000000000000002000")
+
+		committed = func(id uint64, success bool) *types.Log {
+			data := make([]byte, 32)
+			if success {
+				data[31] = 1
+			}
+
+			return &types.Log{
+				Address: receiver,
+				Topics:  []common.Hash{stateCommittedTopic, common.BigToHash(new(big.Int).SetUint64(id))},
+				Data:    data,
+			}
+		}
+	)
+
+	b := &Bor{
+		config: &params.BorConfig{
+			ValidatorContract:     validatorSet.Hex(),
+			StateReceiverContract: receiver.Hex(),
+		},
+	}
+
+	// The span commit log is skipped, the receiver logs are attached to their event
+	logs := []*types.Log{
+		{Address: validatorSet},
+		{Address: target, Data: []byte{1}},
+		committed(5, true),
+		committed(6, false),
+		{Address: target, Data: []byte{2}},
+		{Address: target, Data: []byte{3}},
+		committed(7, true),
+	}
+
+	applied := b.AppliedStateSyncs(logs)
+	require.Len(t, applied, 3)
+
+	require.Equal(t, uint64(5), applied[0].ID)
+	require.True(t, applied[0].Success)
+	require.Equal(t, []*types.Log{logs[1]}, applied[0].Logs)
+
+	require.Equal(t, uint64(6), applied[1].ID)
+	require.False(t, applied[1].Success)
+	require.Empty(t, applied[1].Logs)
+
+	require.Equal(t, uint64(7), applied[2].ID)
+	require.True(t, applied[2].Success)
+	require.Equal(t, []*types.Log{logs[4], logs[5]}, applied[2].Logs)
+
+	require.Empty(t, b.AppliedStateSyncs(nil))
+}
@@ -268,9 +268,10 @@ type BlockChain struct {
 	engine                       consensus.Engine
 	validator                    Validator // Block and state validator interface
 	prefetcher                   Prefetcher
-	processor                    Processor // Block transaction processor interface
-	parallelProcessor            Processor // Parallel block transaction processor interface
-	parallelSpeculativeProcesses int       // Number of parallel speculative processes
+	processor                    Processor               // Block transaction processor interface
+	parallelProcessor            *ParallelStateProcessor // Parallel block transaction processor
+	parallelSpeculativeProcesses int                     // Number of parallel speculative processes
+	parallelPolicy               parallelPolicy          // Concurrency of the blocks executed in parallel
 	forker                       *ForkChoice
 	vmConfig                     vm.Config
 
@@ -279,6 +280,9 @@ type BlockChain struct {
 	stateSyncData    []*types.StateSyncData                  // State sync data
 	stateSyncFeed    event.Feed                              // State sync feed
 	chain2HeadFeed   event.Feed                              // Reorg/NewHead/Fork data feed
+	reorgFeed        event.Feed                              // Reorgs records feed
+	blockedReorgs    *lru.Cache[common.Hash, struct{}]       // Tips of the recently blocked reorgs, recorded once
+	parallelShadow   atomic.Pointer[parallelShadow]          // Blocks executed by both processors, nil if disabled
 }
 
 // NewBlockChain returns a fully initialised block chain using information
@@ -333,6 +337,7 @@ func NewBlockChain(db ethdb.Database, cacheConfig *CacheConfig, genesis *Genesis
 		vmConfig:      vmConfig,
 
 		borReceiptsCache: lru.NewCache[common.Hash, *types.Receipt](receiptsCacheLimit),
+		blockedReorgs:    lru.NewCache[common.Hash, struct{}](blockedReorgsCacheLimit),
 	}
 	bc.flushInterval.Store(int64(cacheConfig.TrieTimeLimit))
 	bc.forker = NewForkChoice(bc, shouldPreserve, checker)
@@ -544,6 +549,11 @@ func NewParallelBlockChain(db ethdb.Database, cacheConfig *CacheConfig, genesis
 }
 
 func (bc *BlockChain) ProcessBlock(block *types.Block, parent *types.Header) (types.Receipts, []*types.Log, uint64, *state.StateDB, error) {
+	// Compare the serial and parallel processors on the sampled blocks
+	if shadow := bc.shadowed(block); shadow != nil {
+		return bc.processBlockShadow(block, parent, shadow)
+	}
+
 	// Process the block using processor and parallelProcessor at the same time, take the one which finishes first, cancel the other, and return the result
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
@@ -561,7 +571,20 @@ func (bc *BlockChain) ProcessBlock(block *types.Block, parent *types.Header) (ty
 
 	processorCount := 0
 
-	if bc.parallelProcessor != nil {
+	parallel := bc.parallelProcessor != nil
+	procs := bc.parallelSpeculativeProcesses
+
+	// Leave the block to the serial processor if parallel execution would lose
+	if parallel {
+		decision := bc.parallelPolicy.decide(block, bc.GetTxDependency(block), procs)
+		if decision.serial {
+			parallel = bc.processor == nil
+		} else {
+			procs = decision.procs
+		}
+	}
+
+	if parallel {
 		parallelStatedb, err := state.New(parent.Root, bc.stateCache, bc.snaps)
 		if err != nil {
 			return nil, nil, 0, nil, err
@@ -571,7 +594,7 @@ func (bc *BlockChain) ProcessBlock(block *types.Block, parent *types.Header) (ty
 
 		go func() {
 			parallelStatedb.StartPrefetcher("chain")
-			receipts, logs, usedGas, err := bc.parallelProcessor.Process(block, parallelStatedb, bc.vmConfig, ctx)
+			receipts, logs, usedGas, err := bc.parallelProcessor.process(block, parallelStatedb, bc.vmConfig, ctx, procs, nil)
 			resultChan <- Result{receipts, logs, usedGas, err, parallelStatedb, blockExecutionParallelCounter}
 		}()
 	}
@@ -1955,6 +1978,8 @@ func (bc *BlockChain) insertChain(chain types.Blocks, setHead bool) (int, error)
 	if !isValid {
 		// The chain to be imported is invalid as the blocks doesn't match with
 		// the whitelisted block number.
+		bc.recordBlockedReorg(headers)
+
 		return it.index, whitelist.ErrMismatch
 	}
 
@@ -2414,6 +2439,10 @@ func (bc *BlockChain) insertSideChain(block *types.Block, it *insertIterator) (i
 		return it.index, err
 	}
 
+	if reorg && !isValid {
+		bc.recordBlockedReorg(headers)
+	}
+
 	if !reorg || !isValid {
 		localTd := bc.GetTd(current.Hash(), current.Number.Uint64())
 		log.Info("Sidechain written to disk", "start", it.first().NumberU64(), "end", it.previous().Number, "sidetd", externTd, "localtd", localTd)
@@ -2669,6 +2698,8 @@ func (bc *BlockChain) reorg(oldHead *types.Header, newHead *types.Block) error {
 		blockReorgAddMeter.Mark(int64(len(newChain)))
 		blockReorgDropMeter.Mark(int64(len(oldChain)))
 		blockReorgMeter.Mark(1)
+
+		bc.recordReorg(commonBlock.Header(), blockHeaders(oldChain), blockHeaders(newChain), true)
 	} else if len(newChain) > 0 {
 		// Special case happens in the post merge stage that current head is
 		// the ancestor of new head while these two blocks are not consecutive
@@ -3146,6 +3177,13 @@ func (bc *BlockChain) SetTrieFlushInterval(interval time.Duration) {
 	bc.flushInterval.Store(int64(interval))
 }
 
+// SetParallelAdaptive configures whether the number of speculative processes
+// is adjusted to every block executed in parallel, leaving the blocks parallel
+// execution would lose on to the serial processor.
+func (bc *BlockChain) SetParallelAdaptive(adaptive bool) {
+	bc.parallelPolicy.adaptive.Store(adaptive)
+}
+
 // GetTrieFlushInterval gets the in-memory tries flush interval
 func (bc *BlockChain) GetTrieFlushInterval() time.Duration {
 	return time.Duration(bc.flushInterval.Load())
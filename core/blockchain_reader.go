@@ -261,6 +261,13 @@ func (bc *BlockChain) GetReceiptsByHash(hash common.Hash) types.Receipts {
 	return receipts
 }
 
+// GetTxDependency returns the dependencies between the transactions of a block,
+// from its header metadata or else from the ones computed when the block was
+// executed without it. It returns nil if they are unknown.
+func (bc *BlockChain) GetTxDependency(block *types.Block) [][]uint64 {
+	return ReadTxDependency(bc.db, block)
+}
+
 // GetUnclesInChain retrieves all the uncles from a given block backwards until
 // a specific distance is reached.
 func (bc *BlockChain) GetUnclesInChain(block *types.Block, length int) []*types.Header {
@@ -387,6 +394,17 @@ func (bc *BlockChain) Processor() Processor {
 	return bc.processor
 }
 
+// ReplayProcessor returns the processor to re-execute the given block with: the
+// parallel one when the dependencies between its transactions are known, from
+// its header metadata or a previous execution, or else the current one.
+func (bc *BlockChain) ReplayProcessor(block *types.Block) Processor {
+	if bc.parallelProcessor != nil && bc.GetTxDependency(block) != nil {
+		return bc.parallelProcessor
+	}
+
+	return bc.processor
+}
+
 // StateCache returns the caching database underpinning the blockchain instance.
 func (bc *BlockChain) StateCache() state.Database {
 	return bc.stateCache
@@ -2,6 +2,7 @@ package blockstm
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 	"time"
 
@@ -40,29 +41,21 @@ func BuildDAG(deps TxnInputOutput) (d DAG) {
 	d = DAG{dag.NewDAG()}
 	ids := make(map[int]string)
 
+	// Add every transaction, the independent ones included, for the vertices
+	// to cover the whole block
+	for i := 0; i < len(deps.inputs); i++ {
+		ids[i], _ = d.AddVertex(i)
+	}
+
 	for i := len(deps.inputs) - 1; i > 0; i-- {
 		txTo := deps.inputs[i]
-
-		var txToId string
-
-		if _, ok := ids[i]; ok {
-			txToId = ids[i]
-		} else {
-			txToId, _ = d.AddVertex(i)
-			ids[i] = txToId
-		}
+		txToId := ids[i]
 
 		for j := i - 1; j >= 0; j-- {
 			txFrom := deps.allOutputs[j]
 
 			if HasReadDep(txFrom, txTo) {
-				var txFromId string
-				if _, ok := ids[j]; ok {
-					txFromId = ids[j]
-				} else {
-					txFromId, _ = d.AddVertex(j)
-					ids[j] = txFromId
-				}
+				txFromId := ids[j]
 
 				err := d.AddEdge(txFromId, txToId)
 				if err != nil {
@@ -75,6 +68,55 @@ func BuildDAG(deps TxnInputOutput) (d DAG) {
 	return
 }
 
+// TxDependencyDAG builds the DAG of the transactions from their dependencies,
+// in the format of the block header metadata.
+func TxDependencyDAG(deps [][]uint64) (d DAG) {
+	d = DAG{dag.NewDAG()}
+	ids := make([]string, len(deps))
+
+	for i := range deps {
+		ids[i], _ = d.AddVertex(i)
+	}
+
+	for i, parents := range deps {
+		for _, p := range parents {
+			if p >= uint64(i) {
+				continue
+			}
+
+			if err := d.AddEdge(ids[p], ids[i]); err != nil {
+				log.Warn("Failed to add edge", "from", ids[p], "to", ids[i], "err", err)
+			}
+		}
+	}
+
+	return
+}
+
+// TxDependency returns the transactions each of the n transactions depends on,
+// in the format of the block header metadata.
+func (d DAG) TxDependency(n int) [][]uint64 {
+	deps := make([][]uint64, n)
+
+	for id, v := range d.GetVertices() {
+		i := v.(int)
+		if i >= n {
+			continue
+		}
+
+		parents, _ := d.GetParents(id)
+
+		deps[i] = make([]uint64, 0, len(parents))
+		for _, p := range parents {
+			deps[i] = append(deps[i], uint64(p.(int)))
+		}
+
+		sort.Slice(deps[i], func(a, b int) bool { return deps[i][a] < deps[i][b] })
+	}
+
+	return deps
+}
+
 func depsHelper(dependencies map[int]map[int]bool, txFrom TxnOutput, txTo TxnInput, i int, j int) map[int]map[int]bool {
 	if HasReadDep(txFrom, txTo) {
 		dependencies[i][j] = true
@@ -125,6 +167,10 @@ func GetDep(deps TxnInputOutput) map[int]map[int]bool {
 
 // Find the longest execution path in the DAG
 func (d DAG) LongestPath(stats map[int]ExecutionStat) ([]int, uint64) {
+	if len(d.GetVertices()) == 0 {
+		return nil, 0
+	}
+
 	prev := make(map[int]int, len(d.GetVertices()))
 
 	for i := 0; i < len(d.GetVertices()); i++ {
@@ -164,6 +164,19 @@ type ParallelExecutionResult struct {
 	Stats   *map[int]ExecutionStat
 	Deps    *DAG
 	AllDeps map[int]map[int]bool
+	Summary *ExecutionSummary
+}
+
+// ExecutionSummary counts the work done by a parallel execution.
+type ExecutionSummary struct {
+	Executions         int           // Executions of all the incarnations
+	Aborts             int           // Incarnations aborted on a read dependency or failure
+	Validations        int           // Validations of the executed incarnations
+	ValidationFailures int           // Incarnations invalidated, to be executed again
+	Incarnations       []int         // Incarnations of each transaction, its number of re-executions
+	Workers            int           // Number of execution workers, speculative ones included
+	Busy               time.Duration // Time spent executing by all the workers, only when profiling
+	Duration           time.Duration // Time of the whole parallel execution
 }
 
 const numGoProcs = 1
@@ -240,6 +253,9 @@ type ParallelExecutor struct {
 	// Enable profiling
 	profile bool
 
+	// Time spent executing by all the workers, only when profiling
+	busy time.Duration
+
 	// Worker wait group
 	workerWg sync.WaitGroup
 }
@@ -353,6 +369,7 @@ func (pe *ParallelExecutor) Prepare() error {
 					end := time.Since(pe.begin)
 
 					pe.statsMutex.Lock()
+					pe.busy += end - start
 					pe.stats[res.ver.TxnIndex] = ExecutionStat{
 						TxIdx:       res.ver.TxnIndex,
 						Incarnation: res.ver.Incarnation,
@@ -567,7 +584,18 @@ func (pe *ParallelExecutor) Step(res *ExecResult) (result ParallelExecutionResul
 			deps = BuildDAG(*pe.lastTxIO)
 		}
 
-		return ParallelExecutionResult{pe.lastTxIO, &pe.stats, &deps, allDeps}, err
+		summary := &ExecutionSummary{
+			Executions:         pe.cntExec,
+			Aborts:             pe.cntAbort,
+			Validations:        pe.cntTotalValidations,
+			ValidationFailures: pe.cntValidationFail,
+			Incarnations:       append([]int(nil), pe.txIncarnations...),
+			Workers:            pe.numSpeculativeProcs + numGoProcs,
+			Busy:               pe.busy,
+			Duration:           time.Since(pe.begin),
+		}
+
+		return ParallelExecutionResult{pe.lastTxIO, &pe.stats, &deps, allDeps, summary}, err
 	}
 
 	// Send the next immediate pending transaction to be executed
@@ -603,7 +631,7 @@ type PropertyCheck func(*ParallelExecutor) error
 
 func executeParallelWithCheck(tasks []ExecTask, profile bool, check PropertyCheck, metadata bool, numProcs int, interruptCtx context.Context) (result ParallelExecutionResult, err error) {
 	if len(tasks) == 0 {
-		return ParallelExecutionResult{MakeTxnInputOutput(len(tasks)), nil, nil, nil}, nil
+		return ParallelExecutionResult{MakeTxnInputOutput(len(tasks)), nil, nil, nil, &ExecutionSummary{}}, nil
 	}
 
 	pe := NewParallelExecutor(tasks, profile, metadata, numProcs)
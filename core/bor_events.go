@@ -1,6 +1,9 @@
 package core
 
 import (
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
 )
 
@@ -21,3 +24,17 @@ type Chain2HeadEvent struct {
 	OldChain []*types.Block
 	Type     string
 }
+
+// ReorgEvent is the record of a reorganisation of the canonical chain, or of one
+// blocked by the whitelisted milestones and checkpoints.
+type ReorgEvent struct {
+	Time           time.Time        `json:"time"`
+	Number         uint64           `json:"number"`   // Number of the common ancestor
+	Ancestor       common.Hash      `json:"ancestor"` // Hash of the common ancestor
+	Depth          uint64           `json:"depth"`    // Number of canonical blocks dropped
+	Dropped        []common.Hash    `json:"dropped"`
+	Added          []common.Hash    `json:"added"`
+	DroppedAuthors []common.Address `json:"droppedAuthors"`
+	AddedAuthors   []common.Address `json:"addedAuthors"`
+	Allowed        bool             `json:"allowed"` // Whether the whitelist, milestone lock included, allowed it
+}
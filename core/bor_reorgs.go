@@ -0,0 +1,181 @@
+package core
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/event"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
+)
+
+const (
+	// reorgRetention is how long the records of the reorgs are kept
+	reorgRetention = 7 * 24 * time.Hour
+
+	// maxReorgBlocks is the maximum number of dropped and added blocks recorded
+	// for a reorg, the deepest ones being omitted
+	maxReorgBlocks = 1024
+
+	// maxReorgs is the maximum number of reorgs returned at once
+	maxReorgs = 1000
+
+	// blockedReorgsCacheLimit is the number of blocked reorgs remembered, not
+	// to record again the chains retried by the peers
+	blockedReorgsCacheLimit = 64
+)
+
+var (
+	reorgDepthHistogram        = metrics.NewRegisteredHistogram("chain/reorg/depth", nil, metrics.NewExpDecaySample(1028, 0.015))
+	blockedReorgDepthHistogram = metrics.NewRegisteredHistogram("chain/reorg/blocked/depth", nil, metrics.NewExpDecaySample(1028, 0.015))
+)
+
+// SubscribeReorgEvent registers a subscription of ReorgEvent.
+func (bc *BlockChain) SubscribeReorgEvent(ch chan<- ReorgEvent) event.Subscription {
+	return bc.scope.Track(bc.reorgFeed.Subscribe(ch))
+}
+
+// GetReorgs returns the records of the reorgs since the given time, oldest first.
+func (bc *BlockChain) GetReorgs(from time.Time) []*ReorgEvent {
+	blobs := rawdb.ReadReorgs(bc.db, uint64(from.UnixNano()), maxReorgs)
+
+	reorgs := make([]*ReorgEvent, 0, len(blobs))
+
+	for _, blob := range blobs {
+		reorg := new(ReorgEvent)
+		if err := json.Unmarshal(blob, reorg); err != nil {
+			log.Error("Invalid reorg record", "err", err)
+			continue
+		}
+
+		reorgs = append(reorgs, reorg)
+	}
+
+	return reorgs
+}
+
+// recordReorg records a reorg from the common ancestor, given the dropped and
+// added blocks from the highest.
+func (bc *BlockChain) recordReorg(ancestor *types.Header, dropped []*types.Header, added []*types.Header, allowed bool) {
+	ev := ReorgEvent{
+		Time:     time.Now(),
+		Number:   ancestor.Number.Uint64(),
+		Ancestor: ancestor.Hash(),
+		Depth:    uint64(len(dropped)),
+		Allowed:  allowed,
+	}
+
+	ev.Dropped, ev.DroppedAuthors = bc.reorgBlocks(dropped)
+	ev.Added, ev.AddedAuthors = bc.reorgBlocks(added)
+
+	if allowed {
+		reorgDepthHistogram.Update(int64(ev.Depth))
+	} else {
+		blockedReorgDepthHistogram.Update(int64(ev.Depth))
+	}
+
+	if blob, err := json.Marshal(ev); err != nil {
+		log.Error("Failed to encode reorg", "err", err)
+	} else {
+		now := uint64(ev.Time.UnixNano())
+
+		rawdb.WriteReorg(bc.db, now, blob)
+		rawdb.DeleteReorgsBefore(bc.db, now-uint64(reorgRetention))
+	}
+
+	bc.reorgFeed.Send(ev)
+}
+
+// reorgBlocks returns the hashes and authors of the given blocks, lowest first.
+func (bc *BlockChain) reorgBlocks(headers []*types.Header) ([]common.Hash, []common.Address) {
+	if len(headers) > maxReorgBlocks {
+		headers = headers[:maxReorgBlocks]
+	}
+
+	hashes := make([]common.Hash, len(headers))
+	authors := make([]common.Address, len(headers))
+
+	for i, header := range headers {
+		j := len(headers) - 1 - i
+
+		hashes[j] = header.Hash()
+		authors[j], _ = bc.engine.Author(header)
+	}
+
+	return hashes, authors
+}
+
+// recordBlockedReorg records the reorg the given chain would have caused, had
+// it not been rejected by the whitelist.
+func (bc *BlockChain) recordBlockedReorg(chain []*types.Header) {
+	// Skip the blocks already canonical
+	for len(chain) > 0 && bc.GetCanonicalHash(chain[0].Number.Uint64()) == chain[0].Hash() {
+		chain = chain[1:]
+	}
+
+	if len(chain) == 0 {
+		return
+	}
+
+	tip := chain[len(chain)-1].Hash()
+	if bc.blockedReorgs.Contains(tip) {
+		return
+	}
+
+	// Collect the side blocks down to the common ancestor
+	var added []*types.Header
+
+	for i := len(chain) - 1; i >= 0; i-- {
+		added = append(added, chain[i])
+	}
+
+	ancestor := bc.GetHeader(chain[0].ParentHash, chain[0].Number.Uint64()-1)
+
+	for ancestor != nil && bc.GetCanonicalHash(ancestor.Number.Uint64()) != ancestor.Hash() {
+		if len(added) >= maxReorgBlocks {
+			return
+		}
+
+		added = append(added, ancestor)
+		ancestor = bc.GetHeader(ancestor.ParentHash, ancestor.Number.Uint64()-1)
+	}
+
+	if ancestor == nil {
+		return
+	}
+
+	// Collect the canonical blocks which would have been dropped
+	var (
+		head    = bc.CurrentBlock()
+		dropped []*types.Header
+	)
+
+	for number := head.Number.Uint64(); number > ancestor.Number.Uint64() && len(dropped) < maxReorgBlocks; number-- {
+		if header := bc.GetHeaderByNumber(number); header != nil {
+			dropped = append(dropped, header)
+		}
+	}
+
+	// Extending the canonical chain is no reorg
+	if len(dropped) == 0 {
+		return
+	}
+
+	bc.blockedReorgs.Add(tip, struct{}{})
+
+	log.Info("Chain reorg blocked by whitelist", "number", ancestor.Number, "hash", ancestor.Hash(), "drop", len(dropped), "add", len(added))
+
+	bc.recordReorg(ancestor, dropped, added, false)
+}
+
+func blockHeaders(blocks types.Blocks) []*types.Header {
+	headers := make([]*types.Header, len(blocks))
+	for i, block := range blocks {
+		headers[i] = block.Header()
+	}
+
+	return headers
+}
@@ -0,0 +1,94 @@
+package core
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/ethash"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/eth/downloader/whitelist"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/trie"
+)
+
+func TestReorgRecords(t *testing.T) {
+	t.Parallel()
+
+	var (
+		db      = rawdb.NewMemoryDatabase()
+		gspec   = &Genesis{Config: params.TestChainConfig}
+		genesis = gspec.MustCommit(db, trie.NewDatabase(db, trie.HashDefaults))
+		allowed = true
+	)
+
+	validator := newChainValidatorFake(func(*types.Header, []*types.Header) (bool, error) {
+		return allowed, nil
+	})
+
+	blockchain, err := NewBlockChain(db, nil, gspec, nil, ethash.NewFaker(), vm.Config{}, nil, nil, validator)
+	require.NoError(t, err)
+
+	defer blockchain.Stop()
+
+	reorgs := make(chan ReorgEvent, 4)
+	sub := blockchain.SubscribeReorgEvent(reorgs)
+
+	defer sub.Unsubscribe()
+
+	chainA, _ := GenerateChain(gspec.Config, genesis, ethash.NewFaker(), db, 5, func(i int, gen *BlockGen) {})
+	_, err = blockchain.InsertChain(chainA)
+	require.NoError(t, err)
+
+	// A longer fork from the second block replaces the last three
+	chainB, _ := GenerateChain(gspec.Config, chainA[1], ethash.NewFaker(), db, 5, func(i int, gen *BlockGen) {
+		gen.SetCoinbase(common.Address{0x01})
+	})
+	_, err = blockchain.InsertChain(chainB)
+	require.NoError(t, err)
+
+	ev := <-reorgs
+	require.True(t, ev.Allowed)
+	require.Equal(t, uint64(2), ev.Number)
+	require.Equal(t, chainA[1].Hash(), ev.Ancestor)
+	require.Equal(t, uint64(3), ev.Depth)
+	require.Equal(t, []common.Hash{chainA[2].Hash(), chainA[3].Hash(), chainA[4].Hash()}, ev.Dropped)
+	// The reorg happens once the fork is heavier, the last block then extends it
+	require.Equal(t, []common.Hash{chainB[0].Hash(), chainB[1].Hash(), chainB[2].Hash(), chainB[3].Hash()}, ev.Added)
+	require.Equal(t, common.Address{0x01}, ev.AddedAuthors[0])
+
+	// A longer fork rejected by the whitelist is recorded once as blocked
+	allowed = false
+
+	chainC, _ := GenerateChain(gspec.Config, chainB[1], ethash.NewFaker(), db, 5, func(i int, gen *BlockGen) {
+		gen.SetCoinbase(common.Address{0x02})
+	})
+
+	for i := 0; i < 2; i++ {
+		_, err = blockchain.InsertChain(chainC)
+		require.ErrorIs(t, err, whitelist.ErrMismatch)
+	}
+
+	ev = <-reorgs
+	require.False(t, ev.Allowed)
+	require.Equal(t, chainB[1].Hash(), ev.Ancestor)
+	require.Equal(t, uint64(3), ev.Depth)
+	require.Len(t, ev.Added, 5)
+
+	select {
+	case ev = <-reorgs:
+		t.Fatalf("blocked reorg recorded twice: %v", ev)
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	records := blockchain.GetReorgs(time.Unix(0, 0))
+	require.Len(t, records, 2)
+	require.True(t, records[0].Allowed)
+	require.False(t, records[1].Allowed)
+
+	require.Empty(t, blockchain.GetReorgs(time.Now()))
+}
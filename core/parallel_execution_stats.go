@@ -0,0 +1,95 @@
+package core
+
+import (
+	"errors"
+	"runtime"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/blockstm"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// ParallelExecutionStats reports how a block executed with Block-STM.
+type ParallelExecutionStats struct {
+	Number       uint64      `json:"number"`
+	Hash         common.Hash `json:"hash"`
+	Transactions int         `json:"transactions"`
+	Metadata     bool        `json:"metadata"` // Whether the dependencies were known before the execution
+
+	Executions         int   `json:"executions"`         // Executions of all the incarnations
+	Reexecutions       int   `json:"reexecutions"`       // Executions past the first one of each transaction
+	Aborts             int   `json:"aborts"`             // Incarnations aborted on a read dependency or failure
+	ValidationFailures int   `json:"validationFailures"` // Incarnations invalidated, to be executed again
+	Incarnations       []int `json:"incarnations"`       // Re-executions of each transaction
+
+	LongestPath          []int         `json:"longestPath"`          // Longest chain of dependent transactions
+	LongestPathTime      time.Duration `json:"longestPathTime"`      // Execution time of the longest chain
+	SerialTime           time.Duration `json:"serialTime"`           // Execution time of all the transactions
+	ExecutionTime        time.Duration `json:"executionTime"`        // Time of the whole parallel execution
+	SpeculativeProcesses int           `json:"speculativeProcesses"` // Number of speculative workers
+	Utilisation          float64       `json:"utilisation"`          // Ratio of the workers time spent executing
+	EstimatedSpeedup     float64       `json:"estimatedSpeedup"`     // Ideal speedup against the serial execution
+}
+
+// fill sets the statistics of the given profiled execution of the block.
+func (s *ParallelExecutionStats) fill(block *types.Block, metadata bool, numProcs int, result blockstm.ParallelExecutionResult) {
+	s.Number = block.NumberU64()
+	s.Hash = block.Hash()
+	s.Transactions = len(block.Transactions())
+	s.Metadata = metadata
+	s.SpeculativeProcesses = numProcs
+
+	if summary := result.Summary; summary != nil {
+		s.Executions = summary.Executions
+		s.Reexecutions = summary.Executions - s.Transactions
+		s.Aborts = summary.Aborts
+		s.ValidationFailures = summary.ValidationFailures
+		s.Incarnations = summary.Incarnations
+		s.ExecutionTime = summary.Duration
+
+		if available := summary.Duration * time.Duration(summary.Workers); available > 0 {
+			s.Utilisation = float64(summary.Busy) / float64(available)
+		}
+	}
+
+	if result.Deps == nil || result.Stats == nil || s.Transactions == 0 {
+		return
+	}
+
+	path, weight := result.Deps.LongestPath(*result.Stats)
+
+	for _, stat := range *result.Stats {
+		s.SerialTime += time.Duration(stat.End - stat.Start)
+	}
+
+	s.LongestPath = path
+	s.LongestPathTime = time.Duration(weight)
+
+	if weight > 0 {
+		s.EstimatedSpeedup = float64(s.SerialTime) / float64(weight)
+	}
+}
+
+// ParallelExecutionStats executes the given block with Block-STM on the state
+// of its parent, and reports how the execution went.
+func (bc *BlockChain) ParallelExecutionStats(block *types.Block, statedb *state.StateDB) (*ParallelExecutionStats, error) {
+	if block.NumberU64() == 0 {
+		return nil, errors.New("genesis is not executable")
+	}
+
+	numProcs := bc.parallelSpeculativeProcesses
+	if numProcs <= 0 {
+		numProcs = runtime.NumCPU()
+	}
+
+	stats := new(ParallelExecutionStats)
+
+	processor := NewParallelStateProcessor(bc.chainConfig, bc, bc.engine)
+	if _, _, _, err := processor.process(block, statedb, bc.vmConfig, nil, numProcs, stats); err != nil {
+		return nil, err
+	}
+
+	return stats, nil
+}
@@ -0,0 +1,469 @@
+package core
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"math/big"
+	"slices"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/blockstm"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// ParallelMessage is a message of a batch executed by ExecuteMessages.
+type ParallelMessage struct {
+	Msg    *Message
+	TxHash common.Hash // Hash the logs of the message are recorded with
+
+	// Dependencies are the indices of the earlier messages of the batch whose
+	// writes the message reads, as found from the access sets of a previous
+	// execution of the batch. Nil when unknown, the dependencies of the batch
+	// then being derived from the access sets of its messages if given, or
+	// else discovered during the execution.
+	Dependencies []int
+
+	// Access set of the message, e.g. the one of the result of a previous
+	// execution of the batch. Reads are nil when unknown.
+	Reads  []blockstm.ReadDescriptor
+	Writes []blockstm.WriteDescriptor
+
+	// NewTracer, if set, returns the tracer of an execution of the message on
+	// the given EVM, and a function called once the execution is done. The
+	// tracer of the settled execution is returned in the message result.
+	NewTracer func(evm *vm.EVM) (vm.EVMLogger, func(), error)
+}
+
+// MessageResult is the outcome of a message of a batch executed by
+// ExecuteMessages.
+type MessageResult struct {
+	Result *ExecutionResult // Result of the execution, nil if the message is not applicable
+	Err    error            // Error making the message not applicable, leaving the state untouched
+	Logs   []*types.Log     // Logs emitted by the message, the fee transfer log included
+	Tracer vm.EVMLogger     // Tracer of the execution, if the message has one
+
+	// Access set of the message, the keys read with the versions they were
+	// read at and the keys written, when the batch was executed in parallel
+	Reads  []blockstm.ReadDescriptor
+	Writes []blockstm.WriteDescriptor
+}
+
+// MessagesResult is the outcome of a batch executed by ExecuteMessages.
+type MessagesResult struct {
+	Results []*MessageResult
+
+	// Writes are the resulting versioned writes of the batch, the last one of
+	// every key written, versioned with the index and incarnation of the
+	// message writing it. Nil if the batch was executed serially.
+	Writes []blockstm.WriteDescriptor
+
+	// Dependencies between the messages of the batch found from their access
+	// sets, to be passed to the next executions of the batch. Nil if the batch
+	// was executed serially.
+	Dependencies [][]uint64
+
+	Parallel bool                       // Whether the batch was executed in parallel, else serially
+	Summary  *blockstm.ExecutionSummary // Work done by the parallel execution, if any
+}
+
+// ExecuteMessages executes a batch of messages on top of the given state with
+// Block-STM, using at most numProcs speculative processes, and applies them to
+// the state in order. The outcome is the one of executing the messages one
+// after the other, a message which is not applicable (e.g. on a nonce or
+// balance error) being skipped rather than failing the batch.
+//
+// Every message has its own gas pool of its gas limit, the batch not being
+// bound by the gas limit of the block. The fees are paid when settling every
+// message, and the batch is executed serially if a message can't have them
+// delayed, sent by the coinbase or reading the balances the fees are paid to.
+// On failure, the state is left untouched.
+func ExecuteMessages(ctx context.Context, config *params.ChainConfig, blockCtx vm.BlockContext, statedb *state.StateDB, msgs []ParallelMessage, numProcs int, cfg vm.Config) (*MessagesResult, error) {
+	dependencies, err := messageDependencies(msgs)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		tasks   = make([]blockstm.ExecTask, 0, len(msgs))
+		results = make([]*MessageResult, len(msgs))
+	)
+
+	for i, msg := range msgs {
+		// The fees paid by the coinbase can't be delayed
+		if msg.Msg.From == blockCtx.Coinbase {
+			return executeMessagesSerially(config, blockCtx, statedb, msgs, cfg)
+		}
+
+		results[i] = new(MessageResult)
+
+		task := &messageTask{
+			config:       config,
+			blockCtx:     blockCtx,
+			evmConfig:    cfg,
+			msg:          msg,
+			index:        i,
+			cleanStateDB: statedb.Copy(),
+			finalStateDB: statedb,
+			result:       results[i],
+		}
+
+		if dependencies != nil {
+			task.dependencies = dependencies[i]
+		}
+
+		tasks = append(tasks, task)
+	}
+
+	if len(tasks) == 0 {
+		return &MessagesResult{Results: results, Parallel: true, Summary: new(blockstm.ExecutionSummary)}, nil
+	}
+
+	backupStateDB := statedb.Copy()
+
+	result, err := blockstm.ExecuteParallel(tasks, false, dependencies != nil, numProcs, ctx)
+	if err != nil {
+		// The messages settled before the failure were applied to the state
+		statedb.StopPrefetcher()
+		*statedb = *backupStateDB
+
+		return nil, err
+	}
+
+	for _, task := range tasks {
+		if task.(*messageTask).readsFees {
+			log.Debug("Executing messages serially, fees read", "hash", task.Hash())
+
+			statedb.StopPrefetcher()
+			*statedb = *backupStateDB
+
+			return executeMessagesSerially(config, blockCtx, statedb, msgs, cfg)
+		}
+	}
+
+	var found [][]uint64
+	if result.TxIO != nil {
+		found = blockstm.BuildDAG(*result.TxIO).TxDependency(len(tasks))
+	}
+
+	return &MessagesResult{
+		Results:      results,
+		Writes:       lastWrites(results),
+		Dependencies: found,
+		Parallel:     true,
+		Summary:      result.Summary,
+	}, nil
+}
+
+// messageDependencies returns the dependencies of the messages of a batch, the
+// given ones or else the ones derived from their access sets, or nil if they
+// are unknown for any message.
+func messageDependencies(msgs []ParallelMessage) ([][]int, error) {
+	given, accessSets := true, true
+
+	for i, msg := range msgs {
+		for _, dep := range msg.Dependencies {
+			if dep < 0 || dep >= i {
+				return nil, fmt.Errorf("invalid dependency %d of message %d", dep, i)
+			}
+		}
+
+		given = given && msg.Dependencies != nil
+		accessSets = accessSets && msg.Reads != nil
+	}
+
+	dependencies := make([][]int, len(msgs))
+
+	switch {
+	case given:
+		for i, msg := range msgs {
+			dependencies[i] = msg.Dependencies
+		}
+
+	case accessSets:
+		var (
+			txio   = blockstm.MakeTxnInputOutput(len(msgs))
+			reads  = make([][]blockstm.ReadDescriptor, len(msgs))
+			writes = make([][]blockstm.WriteDescriptor, len(msgs))
+		)
+
+		for i, msg := range msgs {
+			reads[i], writes[i] = msg.Reads, msg.Writes
+		}
+
+		txio.RecordReadAtOnce(reads)
+		txio.RecordAllWriteAtOnce(writes)
+
+		for i, deps := range blockstm.BuildDAG(*txio).TxDependency(len(msgs)) {
+			dependencies[i] = make([]int, 0, len(deps))
+			for _, dep := range deps {
+				dependencies[i] = append(dependencies[i], int(dep))
+			}
+		}
+
+	default:
+		return nil, nil
+	}
+
+	return dependencies, nil
+}
+
+// executeMessagesSerially applies the messages to the state one after the
+// other, reverting the ones which are not applicable.
+func executeMessagesSerially(config *params.ChainConfig, blockCtx vm.BlockContext, statedb *state.StateDB, msgs []ParallelMessage, cfg vm.Config) (*MessagesResult, error) {
+	results := make([]*MessageResult, len(msgs))
+	evm := vm.NewEVM(blockCtx, vm.TxContext{}, statedb, config, cfg)
+
+	for i, msg := range msgs {
+		statedb.SetTxContext(msg.TxHash, i)
+		evm.Reset(NewEVMTxContext(msg.Msg), statedb)
+
+		tracer, done, err := msg.tracer(evm)
+		if err != nil {
+			return nil, err
+		}
+
+		snapshot := statedb.Snapshot()
+
+		result, err := ApplyMessage(evm, msg.Msg, new(GasPool).AddGas(msg.Msg.GasLimit), nil)
+
+		done()
+
+		evm.Config.Tracer = cfg.Tracer
+
+		if err != nil {
+			statedb.RevertToSnapshot(snapshot)
+
+			results[i] = &MessageResult{Err: err, Tracer: tracer}
+
+			continue
+		}
+
+		statedb.Finalise(config.IsEIP158(blockCtx.BlockNumber))
+
+		results[i] = &MessageResult{
+			Result: result,
+			Logs:   statedb.GetLogs(msg.TxHash, blockCtx.BlockNumber.Uint64(), common.Hash{}),
+			Tracer: tracer,
+		}
+	}
+
+	return &MessagesResult{Results: results}, nil
+}
+
+// tracer sets up the tracer of the message, if any, on the EVM executing it,
+// returning it with the function to call once the execution is done.
+func (msg *ParallelMessage) tracer(evm *vm.EVM) (vm.EVMLogger, func(), error) {
+	if msg.NewTracer == nil {
+		return nil, func() {}, nil
+	}
+
+	tracer, done, err := msg.NewTracer(evm)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	evm.Config.Tracer = tracer
+
+	return tracer, done, nil
+}
+
+// lastWrites returns the last write of every key written by the messages, in
+// the order of the messages.
+func lastWrites(results []*MessageResult) []blockstm.WriteDescriptor {
+	var (
+		writes  []blockstm.WriteDescriptor
+		written = make(map[blockstm.Key]struct{})
+	)
+
+	for i := len(results) - 1; i >= 0; i-- {
+		for j := len(results[i].Writes) - 1; j >= 0; j-- {
+			write := results[i].Writes[j]
+			if _, ok := written[write.Path]; ok {
+				continue
+			}
+
+			written[write.Path] = struct{}{}
+			writes = append(writes, write)
+		}
+	}
+
+	slices.Reverse(writes)
+
+	return writes
+}
+
+// messageTask executes a message of a batch with Block-STM, recording the
+// outcome of its validated incarnation on settlement.
+type messageTask struct {
+	config    *params.ChainConfig
+	blockCtx  vm.BlockContext
+	evmConfig vm.Config
+	msg       ParallelMessage
+	index     int
+
+	dependencies []int
+
+	cleanStateDB *state.StateDB // Initial state of the batch, not modified
+	finalStateDB *state.StateDB // State after the settled messages
+	statedb      *state.StateDB // State of the last incarnation
+
+	execResult *ExecutionResult
+	execErr    error
+	execTracer vm.EVMLogger
+	result     *MessageResult
+
+	// Whether the fees paid can't be delayed to the settlement, as the
+	// message reads the balances they are paid to
+	readsFees bool
+}
+
+func (task *messageTask) Execute(mvh *blockstm.MVHashMap, incarnation int) (err error) {
+	task.statedb = task.cleanStateDB.Copy()
+	task.statedb.SetTxContext(task.msg.TxHash, task.index)
+	task.statedb.SetMVHashmap(mvh)
+	task.statedb.SetIncarnation(incarnation)
+
+	evm := vm.NewEVM(task.blockCtx, NewEVMTxContext(task.msg.Msg), task.statedb, task.config, task.evmConfig)
+
+	tracer, done, err := task.msg.tracer(evm)
+	if err != nil {
+		return err
+	}
+
+	defer done()
+
+	defer func() {
+		if r := recover(); r != nil {
+			// Premature executions may panic, they are aborted and retried
+			log.Debug("Recovered from EVM failure while executing message", "err", r)
+
+			err = blockstm.ErrExecAbortError{Dependency: task.statedb.DepTxIndex()}
+		}
+	}()
+
+	// The fees are paid on settlement, not to conflict with every message
+	result, applyErr := ApplyMessageNoFeeBurnOrTip(evm, *task.msg.Msg, new(GasPool).AddGas(task.msg.Msg.GasLimit), nil)
+
+	if task.statedb.HadInvalidRead() {
+		return blockstm.ErrExecAbortError{Dependency: task.statedb.DepTxIndex()}
+	}
+
+	task.execResult, task.execErr, task.execTracer, task.readsFees = result, applyErr, tracer, false
+
+	// A message which is not applicable leaves the state untouched, its reads
+	// only being validated for it to be executed again if they change
+	if applyErr != nil {
+		task.statedb.ClearWriteMap()
+		return nil
+	}
+
+	reads := task.statedb.MVReadMap()
+
+	_, coinbaseRead := reads[blockstm.NewSubpathKey(task.blockCtx.Coinbase, state.BalancePath)]
+	_, burntContractRead := reads[blockstm.NewSubpathKey(result.BurntContractAddress, state.BalancePath)]
+
+	task.readsFees = coinbaseRead || burntContractRead
+
+	task.statedb.Finalise(task.config.IsEIP158(task.blockCtx.BlockNumber))
+
+	return nil
+}
+
+func (task *messageTask) MVReadList() []blockstm.ReadDescriptor {
+	return task.statedb.MVReadList()
+}
+
+func (task *messageTask) MVWriteList() []blockstm.WriteDescriptor {
+	return task.statedb.MVWriteList()
+}
+
+func (task *messageTask) MVFullWriteList() []blockstm.WriteDescriptor {
+	return task.statedb.MVFullWriteList()
+}
+
+func (task *messageTask) Sender() common.Address {
+	return task.msg.Msg.From
+}
+
+func (task *messageTask) Hash() common.Hash {
+	return task.msg.TxHash
+}
+
+func (task *messageTask) Dependencies() []int {
+	return task.dependencies
+}
+
+// Settle applies the message and its fees to the final state, and records the
+// outcome and access set of its validated incarnation.
+func (task *messageTask) Settle() {
+	task.result.Reads = sortedReads(task.statedb.MVReadList())
+	task.result.Tracer = task.execTracer
+
+	if task.execErr != nil {
+		task.result.Err = task.execErr
+		return
+	}
+
+	writes := task.statedb.MVFullWriteList()
+
+	task.finalStateDB.SetTxContext(task.msg.TxHash, task.index)
+
+	coinbaseBalance := task.finalStateDB.GetBalance(task.blockCtx.Coinbase)
+
+	task.finalStateDB.ApplyMVWriteSet(writes)
+
+	for _, l := range task.statedb.GetLogs(task.msg.TxHash, task.blockCtx.BlockNumber.Uint64(), common.Hash{}) {
+		task.finalStateDB.AddLog(l)
+	}
+
+	if task.config.IsLondon(task.blockCtx.BlockNumber) {
+		task.finalStateDB.AddBalance(task.execResult.BurntContractAddress, task.execResult.FeeBurnt)
+	}
+
+	task.finalStateDB.AddBalance(task.blockCtx.Coinbase, task.execResult.FeeTipped)
+
+	output1 := new(big.Int).SetBytes(task.execResult.SenderInitBalance.Bytes())
+	output2 := new(big.Int).SetBytes(coinbaseBalance.Bytes())
+
+	// Deprecating transfer log and will be removed in future fork. PLEASE DO NOT USE this transfer log going forward. Parameters won't get updated as expected going forward with EIP1559
+	// add transfer log
+	AddFeeTransferLog(
+		task.finalStateDB,
+
+		task.msg.Msg.From,
+		task.blockCtx.Coinbase,
+
+		task.execResult.FeeTipped,
+		task.execResult.SenderInitBalance,
+		coinbaseBalance,
+		output1.Sub(output1, task.execResult.FeeTipped),
+		output2.Add(output2, task.execResult.FeeTipped),
+	)
+
+	task.finalStateDB.Finalise(task.config.IsEIP158(task.blockCtx.BlockNumber))
+
+	task.result.Result = task.execResult
+	task.result.Logs = task.finalStateDB.GetLogs(task.msg.TxHash, task.blockCtx.BlockNumber.Uint64(), common.Hash{})
+	task.result.Writes = sortedWrites(writes)
+}
+
+func sortedReads(reads []blockstm.ReadDescriptor) []blockstm.ReadDescriptor {
+	slices.SortFunc(reads, func(a, b blockstm.ReadDescriptor) int {
+		return bytes.Compare(a.Path[:], b.Path[:])
+	})
+
+	return reads
+}
+
+func sortedWrites(writes []blockstm.WriteDescriptor) []blockstm.WriteDescriptor {
+	slices.SortFunc(writes, func(a, b blockstm.WriteDescriptor) int {
+		return bytes.Compare(a.Path[:], b.Path[:])
+	})
+
+	return writes
+}
@@ -0,0 +1,319 @@
+package core
+
+import (
+	"context"
+	"math/big"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/blockstm"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/eth/tracers/logger"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+var messagesTestCoinbase = common.HexToAddress("0xc0ffee")
+
+// newMessagesTestState returns a state holding funded senders and a counter
+// contract, incrementing its first slot and emitting a log on every call, with
+// the context of a block executing messages on top of it.
+func newMessagesTestState(t *testing.T, senders []common.Address, counter common.Address) (*state.StateDB, vm.BlockContext) {
+	t.Helper()
+
+	statedb, err := state.New(types.EmptyRootHash, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+	require.NoError(t, err)
+
+	for _, sender := range senders {
+		statedb.SetBalance(sender, big.NewInt(params.Ether))
+	}
+
+	// PUSH1 0 SLOAD PUSH1 1 ADD PUSH1 0 SSTORE PUSH1 0 PUSH1 0 LOG0 STOP
+	statedb.SetCode(counter, common.FromHex("0x60005460010160005560006000a000"))
+
+	root, err := statedb.Commit(0, true)
+	require.NoError(t, err)
+
+	statedb, err = state.New(root, statedb.Database(), nil)
+	require.NoError(t, err)
+
+	header := &types.Header{
+		Number:     big.NewInt(1),
+		Time:       1,
+		GasLimit:   30_000_000,
+		BaseFee:    big.NewInt(params.InitialBaseFee),
+		Difficulty: big.NewInt(1),
+	}
+
+	return statedb, NewEVMBlockContext(header, nil, &messagesTestCoinbase)
+}
+
+func newTestMessage(from common.Address, nonce uint64, to common.Address, value int64) ParallelMessage {
+	msg := &Message{
+		From:      from,
+		To:        &to,
+		Nonce:     nonce,
+		Value:     big.NewInt(value),
+		GasLimit:  100_000,
+		GasPrice:  big.NewInt(2 * params.InitialBaseFee),
+		GasFeeCap: big.NewInt(2 * params.InitialBaseFee),
+		GasTipCap: big.NewInt(params.InitialBaseFee),
+	}
+
+	return ParallelMessage{Msg: msg, TxHash: common.BytesToHash(append(from.Bytes(), byte(nonce)))}
+}
+
+// Tests that a batch of messages executed in parallel has the outcome of the
+// serial execution, with and without the dependencies or access sets of a
+// previous execution.
+func TestExecuteMessages(t *testing.T) {
+	t.Parallel()
+
+	var (
+		senders = []common.Address{{0x1}, {0x2}, {0x3}, {0x4}}
+		counter = common.HexToAddress("0xc1")
+	)
+
+	statedb, blockCtx := newMessagesTestState(t, senders, counter)
+
+	msgs := []ParallelMessage{
+		newTestMessage(senders[0], 0, common.Address{0x10}, 1000),
+		newTestMessage(senders[1], 0, counter, 0),
+		newTestMessage(senders[2], 0, common.Address{0x11}, 1000),
+		newTestMessage(senders[0], 1, senders[3], 5000),
+		newTestMessage(senders[3], 0, counter, 0),
+		newTestMessage(senders[2], 5, common.Address{0x12}, 1000), // Nonce too high
+		newTestMessage(senders[3], 1, common.Address{0x10}, 2000),
+	}
+
+	serialStateDB := statedb.Copy()
+	serial, err := executeMessagesSerially(params.TestChainConfig, blockCtx, serialStateDB, msgs, vm.Config{})
+	require.NoError(t, err)
+
+	root := serialStateDB.IntermediateRoot(true)
+
+	require.False(t, serial.Parallel)
+	require.ErrorIs(t, serial.Results[5].Err, ErrNonceTooHigh)
+
+	check := func(result *MessagesResult, parallelStateDB *state.StateDB) {
+		t.Helper()
+
+		require.True(t, result.Parallel)
+		require.Equal(t, root, parallelStateDB.IntermediateRoot(true))
+		require.Len(t, result.Results, len(msgs))
+
+		for i, have := range result.Results {
+			want := serial.Results[i]
+
+			require.Equal(t, want.Err, have.Err, "message %d", i)
+
+			if want.Err != nil {
+				require.Nil(t, have.Result, "message %d", i)
+				require.Empty(t, have.Writes, "message %d", i)
+
+				continue
+			}
+
+			require.Equal(t, want.Result.UsedGas, have.Result.UsedGas, "message %d", i)
+			require.Equal(t, want.Result.Failed(), have.Result.Failed(), "message %d", i)
+			require.Equal(t, want.Logs, have.Logs, "message %d", i)
+			require.NotEmpty(t, have.Reads, "message %d", i)
+			require.NotEmpty(t, have.Writes, "message %d", i)
+		}
+
+		// The counter is last written by the second message calling it
+		slot := blockstm.NewStateKey(counter, common.Hash{})
+
+		var found bool
+
+		for _, write := range result.Writes {
+			if write.Path == slot {
+				require.Equal(t, 4, write.V.TxnIndex)
+
+				found = true
+			}
+		}
+
+		require.True(t, found)
+
+		// The messages depend on the earlier ones of the same senders, and on the
+		// ones calling the counter or funding them
+		require.Contains(t, result.Dependencies[3], uint64(0))
+		require.Contains(t, result.Dependencies[4], uint64(1))
+		require.Contains(t, result.Dependencies[4], uint64(3))
+		require.Contains(t, result.Dependencies[6], uint64(4))
+	}
+
+	for _, procs := range []int{1, 4, 8} {
+		parallelStateDB := statedb.Copy()
+
+		result, err := ExecuteMessages(context.Background(), params.TestChainConfig, blockCtx, parallelStateDB, msgs, procs, vm.Config{})
+		require.NoError(t, err)
+
+		check(result, parallelStateDB)
+
+		// Executing the batch again with the dependencies found
+		known := make([]ParallelMessage, len(msgs))
+
+		for i, msg := range msgs {
+			known[i] = msg
+			known[i].Dependencies = make([]int, 0, len(result.Dependencies[i]))
+
+			for _, dep := range result.Dependencies[i] {
+				known[i].Dependencies = append(known[i].Dependencies, int(dep))
+			}
+		}
+
+		parallelStateDB = statedb.Copy()
+
+		withDeps, err := ExecuteMessages(context.Background(), params.TestChainConfig, blockCtx, parallelStateDB, known, procs, vm.Config{})
+		require.NoError(t, err)
+
+		check(withDeps, parallelStateDB)
+
+		// Executing the batch again with the access sets found
+		accessed := make([]ParallelMessage, len(msgs))
+
+		for i, msg := range msgs {
+			accessed[i] = msg
+			accessed[i].Reads = result.Results[i].Reads
+			accessed[i].Writes = result.Results[i].Writes
+		}
+
+		deps, err := messageDependencies(accessed)
+		require.NoError(t, err)
+		require.Equal(t, result.Dependencies, toUint64Deps(deps))
+
+		parallelStateDB = statedb.Copy()
+
+		result, err = ExecuteMessages(context.Background(), params.TestChainConfig, blockCtx, parallelStateDB, accessed, procs, vm.Config{})
+		require.NoError(t, err)
+
+		check(result, parallelStateDB)
+	}
+
+	// Dependencies on later messages are rejected
+	invalid := append([]ParallelMessage{}, msgs...)
+	invalid[1].Dependencies = []int{1}
+
+	_, err = ExecuteMessages(context.Background(), params.TestChainConfig, blockCtx, statedb.Copy(), invalid, 4, vm.Config{})
+	require.Error(t, err)
+
+	// A failed execution leaves the state untouched
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	cancelledStateDB := statedb.Copy()
+	initial := cancelledStateDB.IntermediateRoot(true)
+
+	_, err = ExecuteMessages(ctx, params.TestChainConfig, blockCtx, cancelledStateDB, msgs, 4, vm.Config{})
+	require.ErrorIs(t, err, context.Canceled)
+	require.Equal(t, initial, cancelledStateDB.IntermediateRoot(true))
+}
+
+func toUint64Deps(deps [][]int) [][]uint64 {
+	result := make([][]uint64, len(deps))
+
+	for i, txDeps := range deps {
+		result[i] = make([]uint64, 0, len(txDeps))
+		for _, dep := range txDeps {
+			result[i] = append(result[i], uint64(dep))
+		}
+	}
+
+	return result
+}
+
+// Tests that the messages of a batch are traced, the tracer of their settled
+// execution being returned.
+func TestExecuteMessagesTracer(t *testing.T) {
+	t.Parallel()
+
+	var (
+		senders = []common.Address{{0x1}, {0x2}}
+		counter = common.HexToAddress("0xc1")
+	)
+
+	statedb, blockCtx := newMessagesTestState(t, senders, counter)
+
+	msgs := []ParallelMessage{
+		newTestMessage(senders[0], 0, counter, 0),
+		newTestMessage(senders[1], 0, counter, 0),
+		newTestMessage(senders[0], 1, common.Address{0x10}, 1000),
+	}
+
+	var done atomic.Int32
+
+	for i := range msgs {
+		msgs[i].NewTracer = func(evm *vm.EVM) (vm.EVMLogger, func(), error) {
+			return logger.NewStructLogger(nil), func() { done.Add(1) }, nil
+		}
+	}
+
+	serial, err := executeMessagesSerially(params.TestChainConfig, blockCtx, statedb.Copy(), msgs, vm.Config{})
+	require.NoError(t, err)
+	require.Equal(t, int32(len(msgs)), done.Load())
+
+	result, err := ExecuteMessages(context.Background(), params.TestChainConfig, blockCtx, statedb.Copy(), msgs, 4, vm.Config{})
+	require.NoError(t, err)
+	require.True(t, result.Parallel)
+	require.GreaterOrEqual(t, done.Load(), int32(2*len(msgs)))
+
+	for i := range msgs {
+		want := serial.Results[i].Tracer.(*logger.StructLogger)
+		have := result.Results[i].Tracer.(*logger.StructLogger)
+
+		require.Equal(t, want.StructLogs(), have.StructLogs(), "message %d", i)
+	}
+
+	require.NotEmpty(t, result.Results[0].Tracer.(*logger.StructLogger).StructLogs())
+	require.Empty(t, result.Results[2].Tracer.(*logger.StructLogger).StructLogs())
+}
+
+// Tests that a batch of messages which can't have their fees delayed is
+// executed serially.
+func TestExecuteMessagesFeesRead(t *testing.T) {
+	t.Parallel()
+
+	senders := []common.Address{{0x1}, {0x2}}
+
+	statedb, blockCtx := newMessagesTestState(t, append(senders, messagesTestCoinbase), common.HexToAddress("0xc1"))
+
+	tests := [][]ParallelMessage{
+		// Paying the coinbase
+		{
+			newTestMessage(senders[0], 0, common.Address{0x10}, 1000),
+			newTestMessage(senders[1], 0, blockCtx.Coinbase, 1000),
+		},
+		// Sent by the coinbase
+		{
+			newTestMessage(senders[0], 0, common.Address{0x10}, 1000),
+			newTestMessage(blockCtx.Coinbase, 0, common.Address{0x11}, 1000),
+		},
+	}
+
+	for i, msgs := range tests {
+		serialStateDB := statedb.Copy()
+		serial, err := executeMessagesSerially(params.TestChainConfig, blockCtx, serialStateDB, msgs, vm.Config{})
+		require.NoError(t, err)
+
+		parallelStateDB := statedb.Copy()
+
+		result, err := ExecuteMessages(context.Background(), params.TestChainConfig, blockCtx, parallelStateDB, msgs, 4, vm.Config{})
+		require.NoError(t, err)
+
+		require.False(t, result.Parallel, "test %d", i)
+		require.Nil(t, result.Writes, "test %d", i)
+		require.Equal(t, serialStateDB.IntermediateRoot(true), parallelStateDB.IntermediateRoot(true), "test %d", i)
+
+		for j := range msgs {
+			require.NoError(t, result.Results[j].Err, "test %d", i)
+			require.Equal(t, serial.Results[j].Result.UsedGas, result.Results[j].Result.UsedGas, "test %d", i)
+		}
+	}
+}
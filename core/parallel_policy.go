@@ -0,0 +1,161 @@
+package core
+
+import (
+	"math"
+	"sync"
+	"sync/atomic"
+
+	"github.com/ethereum/go-ethereum/core/blockstm"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
+)
+
+const (
+	// parallelMinTransactions is the number of transactions below which blocks
+	// are executed serially, the parallel execution not paying off its setup
+	parallelMinTransactions = 4
+
+	// parallelMinSpeedup is the ideal speedup of the dependencies of a block
+	// below which it is executed serially
+	parallelMinSpeedup = 1.5
+
+	// parallelMaxAbortRate is the moving average of the aborted incarnations per
+	// transaction above which blocks without dependencies are executed serially
+	parallelMaxAbortRate = 1.0
+
+	// parallelAbortRateWeight is the weight of the last block in the moving
+	// average of the aborted incarnations per transaction
+	parallelAbortRateWeight = 0.1
+)
+
+// Reasons of the parallel execution decisions
+const (
+	parallelReasonFixed        = "fixed"        // The adaptive concurrency is disabled
+	parallelReasonTransactions = "transactions" // The number of transactions of the block
+	parallelReasonDependencies = "dependencies" // The dependencies of the block metadata
+	parallelReasonAborts       = "aborts"       // The aborts of the recent blocks without dependencies
+)
+
+var (
+	parallelProcsGauge     = metrics.NewRegisteredGauge("chain/execution/policy/procs", nil)
+	parallelAbortRateGauge = metrics.NewRegisteredGaugeFloat64("chain/execution/policy/abortrate", nil)
+)
+
+// parallelDecision is how the parallel state processor executes a block.
+type parallelDecision struct {
+	serial bool   // Whether the block is left to the serial processor
+	procs  int    // Number of speculative processes executing the block in parallel
+	reason string // What the decision is based on
+}
+
+// parallelPolicy decides the concurrency of every block executed by the
+// parallel state processor. When adaptive, the speculative processes are sized
+// to the width of the dependencies of the block metadata, or else to the rate
+// of the recent aborts, and the blocks parallel execution would clearly lose
+// on are left to the serial processor.
+//
+// The zero value is ready to use, executing every block with the configured
+// number of speculative processes.
+type parallelPolicy struct {
+	adaptive atomic.Bool
+
+	abortRate float64 // Moving average of the aborted incarnations per transaction
+	lock      sync.Mutex
+}
+
+// decide returns how to execute the block with the given dependencies, using
+// at most procs speculative processes.
+func (p *parallelPolicy) decide(block *types.Block, txDependency [][]uint64, procs int) parallelDecision {
+	decision := p.makeDecision(len(block.Transactions()), txDependency, procs)
+
+	kind := "parallel"
+	if decision.serial {
+		kind = "serial"
+	} else {
+		parallelProcsGauge.Update(int64(decision.procs))
+	}
+
+	metrics.GetOrRegisterMeter("chain/execution/policy/"+kind+"/"+decision.reason, nil).Mark(1)
+
+	log.Debug("Decided block parallel execution", "number", block.Number(), "hash", block.Hash(), "serial", decision.serial, "procs", decision.procs, "reason", decision.reason)
+
+	return decision
+}
+
+func (p *parallelPolicy) makeDecision(n int, txDependency [][]uint64, procs int) parallelDecision {
+	if !p.adaptive.Load() {
+		return parallelDecision{procs: procs, reason: parallelReasonFixed}
+	}
+
+	if n < parallelMinTransactions {
+		return parallelDecision{serial: true, reason: parallelReasonTransactions}
+	}
+
+	if txDependency != nil {
+		length, width := dependencyShape(txDependency)
+		if float64(n)/float64(length) < parallelMinSpeedup {
+			return parallelDecision{serial: true, reason: parallelReasonDependencies}
+		}
+
+		return parallelDecision{procs: min(procs, width), reason: parallelReasonDependencies}
+	}
+
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	if p.abortRate >= parallelMaxAbortRate {
+		// Decay the rate while executing serially, for the parallel execution
+		// to be tried again later on
+		p.abortRate *= 1 - parallelAbortRateWeight
+		parallelAbortRateGauge.Update(p.abortRate)
+
+		return parallelDecision{serial: true, reason: parallelReasonAborts}
+	}
+
+	scaled := int(math.Ceil(float64(procs) * (1 - p.abortRate/parallelMaxAbortRate)))
+
+	return parallelDecision{procs: max(1, min(procs, scaled)), reason: parallelReasonAborts}
+}
+
+// record accounts for the aborts of a block of n transactions executed in
+// parallel without dependencies.
+func (p *parallelPolicy) record(n int, summary *blockstm.ExecutionSummary) {
+	if n == 0 || summary == nil {
+		return
+	}
+
+	rate := float64(summary.Aborts+summary.ValidationFailures) / float64(n)
+
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	p.abortRate = (1-parallelAbortRateWeight)*p.abortRate + parallelAbortRateWeight*rate
+	parallelAbortRateGauge.Update(p.abortRate)
+}
+
+// dependencyShape returns the length of the longest chain of dependent
+// transactions of the given block metadata, and the largest number of
+// transactions at the same depth of the chains, executable concurrently.
+func dependencyShape(txDependency [][]uint64) (length int, width int) {
+	depths := make([]int, len(txDependency))
+	counts := make(map[int]int)
+
+	for i, parents := range txDependency {
+		depth := 1
+
+		for _, p := range parents {
+			if p < uint64(i) && depths[p]+1 > depth {
+				depth = depths[p] + 1
+			}
+		}
+
+		depths[i] = depth
+		counts[depth]++
+
+		length = max(length, depth)
+		width = max(width, counts[depth])
+	}
+
+	return length, width
+}
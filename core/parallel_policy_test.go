@@ -0,0 +1,74 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum/go-ethereum/core/blockstm"
+)
+
+func TestDependencyShape(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		deps          [][]uint64
+		length, width int
+	}{
+		{deps: [][]uint64{{}, {}, {}, {}}, length: 1, width: 4},
+		{deps: [][]uint64{{}, {0}, {1}, {2}}, length: 4, width: 1},
+		{deps: [][]uint64{{}, {}, {0, 1}, {0}, {3}}, length: 3, width: 2},
+	}
+
+	for _, tt := range tests {
+		length, width := dependencyShape(tt.deps)
+		require.Equal(t, tt.length, length, tt.deps)
+		require.Equal(t, tt.width, width, tt.deps)
+	}
+}
+
+func TestParallelPolicy(t *testing.T) {
+	t.Parallel()
+
+	var policy parallelPolicy
+
+	independent := [][]uint64{{}, {}, {}, {}, {}, {}}
+	sequential := [][]uint64{{}, {0}, {1}, {2}, {3}, {4}}
+
+	// Every block is executed with the configured processes unless adaptive
+	require.Equal(t, parallelDecision{procs: 8, reason: parallelReasonFixed}, policy.makeDecision(1, nil, 8))
+	require.Equal(t, parallelDecision{procs: 8, reason: parallelReasonFixed}, policy.makeDecision(6, sequential, 8))
+
+	policy.adaptive.Store(true)
+
+	// Small blocks and chains of dependent transactions are executed serially
+	require.Equal(t, parallelDecision{serial: true, reason: parallelReasonTransactions}, policy.makeDecision(2, nil, 8))
+	require.Equal(t, parallelDecision{serial: true, reason: parallelReasonDependencies}, policy.makeDecision(6, sequential, 8))
+
+	// The processes are sized to the independent transactions
+	require.Equal(t, parallelDecision{procs: 6, reason: parallelReasonDependencies}, policy.makeDecision(6, independent, 8))
+	require.Equal(t, parallelDecision{procs: 4, reason: parallelReasonDependencies}, policy.makeDecision(6, independent, 4))
+
+	// The processes are reduced with the aborts of the blocks without dependencies
+	require.Equal(t, parallelDecision{procs: 8, reason: parallelReasonAborts}, policy.makeDecision(6, nil, 8))
+
+	policy.record(10, &blockstm.ExecutionSummary{Aborts: 30, ValidationFailures: 20})
+	require.InDelta(t, 0.5, policy.abortRate, 1e-9)
+	require.Equal(t, parallelDecision{procs: 4, reason: parallelReasonAborts}, policy.makeDecision(6, nil, 8))
+
+	// Past the maximum abort rate, the blocks are executed serially until the
+	// rate decays back
+	policy.record(10, &blockstm.ExecutionSummary{Aborts: 100})
+
+	decision := policy.makeDecision(6, nil, 8)
+	require.True(t, decision.serial)
+	require.Equal(t, parallelReasonAborts, decision.reason)
+
+	serial := 1
+	for policy.makeDecision(6, nil, 8).serial {
+		serial++
+	}
+
+	require.Equal(t, 4, serial)
+	require.Less(t, policy.abortRate, parallelMaxAbortRate)
+}
@@ -0,0 +1,367 @@
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/trie"
+)
+
+var (
+	shadowCheckedMeter  = metrics.NewRegisteredMeter("chain/execution/shadow/checked", nil)
+	shadowMismatchMeter = metrics.NewRegisteredMeter("chain/execution/shadow/mismatch", nil)
+)
+
+// parallelShadow configures the blocks executed by both the serial and the
+// parallel processors, for their results to be compared.
+type parallelShadow struct {
+	interval uint64 // Every how many blocks to compare the processors
+	dir      string // Directory the debug bundles of the mismatching blocks are written to
+}
+
+// shadowExecution is the outcome of the execution of a block by a processor.
+type shadowExecution struct {
+	receipts types.Receipts
+	logs     []*types.Log
+	usedGas  uint64
+	statedb  *state.StateDB
+	err      error
+}
+
+// SetParallelShadow configures the serial and parallel processors to both
+// execute every interval-th block, comparing their receipts, logs, gas used
+// and state roots. A debug bundle is written to dir for every mismatching
+// block. An interval of 0 disables the comparison.
+func (bc *BlockChain) SetParallelShadow(interval uint64, dir string) {
+	if interval == 0 {
+		bc.parallelShadow.Store(nil)
+		return
+	}
+
+	bc.parallelShadow.Store(&parallelShadow{interval: interval, dir: dir})
+}
+
+// shadowed returns the shadow configuration if the block is to be executed
+// by both processors, nil otherwise.
+func (bc *BlockChain) shadowed(block *types.Block) *parallelShadow {
+	shadow := bc.parallelShadow.Load()
+	if shadow == nil || bc.parallelProcessor == nil || bc.processor == nil {
+		return nil
+	}
+
+	if block.NumberU64()%shadow.interval != 0 {
+		return nil
+	}
+
+	return shadow
+}
+
+// processBlockShadow executes the block with both the serial and the parallel
+// processors, and returns the outcome of the serial one after comparing them.
+func (bc *BlockChain) processBlockShadow(block *types.Block, parent *types.Header, shadow *parallelShadow) (types.Receipts, []*types.Log, uint64, *state.StateDB, error) {
+	var (
+		serial, parallel shadowExecution
+		wg               sync.WaitGroup
+	)
+
+	for _, exec := range []*shadowExecution{&serial, &parallel} {
+		statedb, err := state.New(parent.Root, bc.stateCache, bc.snaps)
+		if err != nil {
+			return nil, nil, 0, nil, err
+		}
+
+		exec.statedb = statedb
+	}
+
+	wg.Add(2)
+
+	go func() {
+		defer wg.Done()
+
+		serial.statedb.StartPrefetcher("chain")
+		serial.receipts, serial.logs, serial.usedGas, serial.err = bc.processor.Process(block, serial.statedb, bc.vmConfig, nil)
+	}()
+
+	go func() {
+		defer wg.Done()
+
+		parallel.receipts, parallel.logs, parallel.usedGas, parallel.err = bc.parallelProcessor.process(block, parallel.statedb, bc.vmConfig, nil, bc.parallelSpeculativeProcesses, nil)
+	}()
+
+	wg.Wait()
+
+	blockExecutionSerialCounter.Inc(1)
+	shadowCheckedMeter.Mark(1)
+
+	deleteEmptyObjects := bc.chainConfig.IsEIP158(block.Number())
+
+	mismatches := compareShadowExecutions(&serial, &parallel, deleteEmptyObjects)
+	if len(mismatches) > 0 {
+		shadowMismatchMeter.Mark(1)
+
+		path, err := bc.writeShadowBundle(shadow.dir, block, parent, &serial, &parallel, mismatches)
+		if err != nil {
+			log.Error("Failed to write parallel execution mismatch bundle", "number", block.Number(), "hash", block.Hash(), "err", err)
+		}
+
+		log.Error("Parallel execution mismatch", "number", block.Number(), "hash", block.Hash(), "mismatches", len(mismatches), "first", mismatches[0], "bundle", path)
+	}
+
+	return serial.receipts, serial.logs, serial.usedGas, serial.statedb, serial.err
+}
+
+// compareShadowExecutions returns the differences between the executions of a
+// block by the serial and parallel processors.
+func compareShadowExecutions(serial, parallel *shadowExecution, deleteEmptyObjects bool) []string {
+	if serial.err != nil || parallel.err != nil {
+		if (serial.err == nil) != (parallel.err == nil) {
+			return []string{fmt.Sprintf("error: serial %v, parallel %v", serial.err, parallel.err)}
+		}
+
+		return nil
+	}
+
+	var mismatches []string
+
+	if serial.usedGas != parallel.usedGas {
+		mismatches = append(mismatches, fmt.Sprintf("gas used: serial %d, parallel %d", serial.usedGas, parallel.usedGas))
+	}
+
+	if len(serial.receipts) != len(parallel.receipts) {
+		mismatches = append(mismatches, fmt.Sprintf("receipts: serial %d, parallel %d", len(serial.receipts), len(parallel.receipts)))
+	} else {
+		for i := range serial.receipts {
+			if diff := compareReceipts(serial.receipts[i], parallel.receipts[i]); diff != "" {
+				mismatches = append(mismatches, fmt.Sprintf("receipt %d: %s", i, diff))
+			}
+		}
+	}
+
+	if len(serial.logs) != len(parallel.logs) {
+		mismatches = append(mismatches, fmt.Sprintf("logs: serial %d, parallel %d", len(serial.logs), len(parallel.logs)))
+	} else {
+		for i := range serial.logs {
+			if !equalLogs(serial.logs[i], parallel.logs[i]) {
+				mismatches = append(mismatches, fmt.Sprintf("log %d: serial tx %d index %d, parallel tx %d index %d", i, serial.logs[i].TxIndex, serial.logs[i].Index, parallel.logs[i].TxIndex, parallel.logs[i].Index))
+			}
+		}
+	}
+
+	serialRoot := serial.statedb.IntermediateRoot(deleteEmptyObjects)
+	parallelRoot := parallel.statedb.IntermediateRoot(deleteEmptyObjects)
+
+	if serialRoot != parallelRoot {
+		mismatches = append(mismatches, fmt.Sprintf("state root: serial %x, parallel %x", serialRoot, parallelRoot))
+	}
+
+	return mismatches
+}
+
+// compareReceipts describes the first difference between two receipts, or
+// returns an empty string if they match.
+func compareReceipts(serial, parallel *types.Receipt) string {
+	switch {
+	case serial.TxHash != parallel.TxHash:
+		return fmt.Sprintf("tx hash: serial %x, parallel %x", serial.TxHash, parallel.TxHash)
+	case serial.Status != parallel.Status:
+		return fmt.Sprintf("status: serial %d, parallel %d", serial.Status, parallel.Status)
+	case serial.GasUsed != parallel.GasUsed:
+		return fmt.Sprintf("gas used: serial %d, parallel %d", serial.GasUsed, parallel.GasUsed)
+	case serial.CumulativeGasUsed != parallel.CumulativeGasUsed:
+		return fmt.Sprintf("cumulative gas used: serial %d, parallel %d", serial.CumulativeGasUsed, parallel.CumulativeGasUsed)
+	case serial.ContractAddress != parallel.ContractAddress:
+		return fmt.Sprintf("contract address: serial %x, parallel %x", serial.ContractAddress, parallel.ContractAddress)
+	case serial.Bloom != parallel.Bloom:
+		return "bloom"
+	case len(serial.Logs) != len(parallel.Logs):
+		return fmt.Sprintf("logs: serial %d, parallel %d", len(serial.Logs), len(parallel.Logs))
+	}
+
+	for i := range serial.Logs {
+		if !equalLogs(serial.Logs[i], parallel.Logs[i]) {
+			return fmt.Sprintf("log %d", i)
+		}
+	}
+
+	return ""
+}
+
+func equalLogs(a, b *types.Log) bool {
+	return a.Address == b.Address && reflect.DeepEqual(a.Topics, b.Topics) && string(a.Data) == string(b.Data) &&
+		a.TxHash == b.TxHash && a.TxIndex == b.TxIndex && a.Index == b.Index
+}
+
+// shadowEnv is the block environment of a debug bundle, in the format of the
+// env input of `evm t8n`.
+type shadowEnv struct {
+	Coinbase    common.UnprefixedAddress            `json:"currentCoinbase"`
+	Difficulty  *math.HexOrDecimal256               `json:"currentDifficulty"`
+	GasLimit    math.HexOrDecimal64                 `json:"currentGasLimit"`
+	Number      math.HexOrDecimal64                 `json:"currentNumber"`
+	Timestamp   math.HexOrDecimal64                 `json:"currentTimestamp"`
+	BaseFee     *math.HexOrDecimal256               `json:"currentBaseFee,omitempty"`
+	BlockHashes map[math.HexOrDecimal64]common.Hash `json:"blockHashes,omitempty"`
+}
+
+// shadowReport describes the mismatching executions of a debug bundle.
+type shadowReport struct {
+	Number     uint64        `json:"number"`
+	Hash       common.Hash   `json:"hash"`
+	ParentRoot common.Hash   `json:"parentRoot"`
+	Mismatches []string      `json:"mismatches"`
+	Serial     shadowOutcome `json:"serial"`
+	Parallel   shadowOutcome `json:"parallel"`
+	Replay     string        `json:"replay"`
+}
+
+// shadowOutcome summarises the execution of a block by a processor.
+type shadowOutcome struct {
+	GasUsed      uint64      `json:"gasUsed"`
+	ReceiptsRoot common.Hash `json:"receiptsRoot"`
+	StateRoot    common.Hash `json:"stateRoot"`
+	Error        string      `json:"error,omitempty"`
+}
+
+// writeShadowBundle writes the debug bundle of a block whose executions by the
+// serial and parallel processors mismatch, returning its directory. The bundle
+// holds the block, the pre-state of the accounts accessed by the executions,
+// the dependencies of the transactions and the report of the mismatches. The
+// environment, pre-state and transactions are in the format of `evm t8n`, to
+// replay the transactions outside of the node. The state sync transactions of
+// the block are not replayed by it.
+func (bc *BlockChain) writeShadowBundle(dir string, block *types.Block, parent *types.Header, serial, parallel *shadowExecution, mismatches []string) (string, error) {
+	path := filepath.Join(dir, fmt.Sprintf("%d-%s", block.NumberU64(), block.Hash().Hex()))
+	if err := os.MkdirAll(path, 0755); err != nil {
+		return "", err
+	}
+
+	prestate, err := state.New(parent.Root, bc.stateCache, bc.snaps)
+	if err != nil {
+		return path, err
+	}
+
+	alloc := make(GenesisAlloc)
+
+	for _, execution := range []*shadowExecution{serial, parallel} {
+		for addr, slots := range execution.statedb.AccessedState() {
+			if !prestate.Exist(addr) {
+				continue
+			}
+
+			account, ok := alloc[addr]
+			if !ok {
+				account = GenesisAccount{
+					Balance: prestate.GetBalance(addr),
+					Nonce:   prestate.GetNonce(addr),
+					Code:    prestate.GetCode(addr),
+					Storage: make(map[common.Hash]common.Hash),
+				}
+			}
+
+			for _, slot := range slots {
+				if value := prestate.GetState(addr, slot); value != (common.Hash{}) {
+					account.Storage[slot] = value
+				}
+			}
+
+			alloc[addr] = account
+		}
+	}
+
+	header := block.Header()
+	env := shadowEnv{
+		Coinbase:    common.UnprefixedAddress(header.Coinbase),
+		Difficulty:  (*math.HexOrDecimal256)(header.Difficulty),
+		GasLimit:    math.HexOrDecimal64(header.GasLimit),
+		Number:      math.HexOrDecimal64(header.Number.Uint64()),
+		Timestamp:   math.HexOrDecimal64(header.Time),
+		BaseFee:     (*math.HexOrDecimal256)(header.BaseFee),
+		BlockHashes: map[math.HexOrDecimal64]common.Hash{math.HexOrDecimal64(parent.Number.Uint64()): parent.Hash()},
+	}
+
+	if coinbase, err := bc.engine.Author(header); err == nil {
+		env.Coinbase = common.UnprefixedAddress(coinbase)
+	}
+
+	deleteEmptyObjects := bc.chainConfig.IsEIP158(block.Number())
+
+	report := shadowReport{
+		Number:     block.NumberU64(),
+		Hash:       block.Hash(),
+		ParentRoot: parent.Root,
+		Mismatches: mismatches,
+		Serial:     newShadowOutcome(serial, deleteEmptyObjects),
+		Parallel:   newShadowOutcome(parallel, deleteEmptyObjects),
+		Replay:     fmt.Sprintf("evm t8n --input.alloc=alloc.json --input.env=env.json --input.txs=txs.json --state.fork=%s --state.chainid=%d", shadowFork(bc, header), bc.chainConfig.ChainID),
+	}
+
+	blockRLP, err := rlp.EncodeToBytes(block)
+	if err != nil {
+		return path, err
+	}
+
+	if err := os.WriteFile(filepath.Join(path, "block.rlp"), blockRLP, 0644); err != nil {
+		return path, err
+	}
+
+	files := map[string]interface{}{
+		"alloc.json":  alloc,
+		"env.json":    env,
+		"txs.json":    block.Transactions(),
+		"deps.json":   bc.GetTxDependency(block),
+		"report.json": report,
+	}
+
+	for name, content := range files {
+		data, err := json.MarshalIndent(content, "", "  ")
+		if err != nil {
+			return path, err
+		}
+
+		if err := os.WriteFile(filepath.Join(path, name), data, 0644); err != nil {
+			return path, err
+		}
+	}
+
+	return path, nil
+}
+
+func newShadowOutcome(execution *shadowExecution, deleteEmptyObjects bool) shadowOutcome {
+	if execution.err != nil {
+		return shadowOutcome{Error: execution.err.Error()}
+	}
+
+	return shadowOutcome{
+		GasUsed:      execution.usedGas,
+		ReceiptsRoot: types.DeriveSha(execution.receipts, trie.NewStackTrie(nil)),
+		StateRoot:    execution.statedb.IntermediateRoot(deleteEmptyObjects),
+	}
+}
+
+// shadowFork returns the name of the latest fork active at the header, as
+// known by `evm t8n`.
+func shadowFork(bc *BlockChain, header *types.Header) string {
+	switch config := bc.chainConfig; {
+	case config.IsCancun(header.Number):
+		return "Cancun"
+	case config.IsShanghai(header.Number):
+		return "Shanghai"
+	case config.IsLondon(header.Number):
+		return "London"
+	case config.IsBerlin(header.Number):
+		return "Berlin"
+	default:
+		return "Istanbul"
+	}
+}
@@ -0,0 +1,112 @@
+package core
+
+import (
+	"encoding/json"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+func TestParallelShadow(t *testing.T) {
+	t.Parallel()
+
+	blockchain, block := newParallelTestChain(t)
+	defer blockchain.Stop()
+
+	dir := t.TempDir()
+	blockchain.SetParallelShadow(1, dir)
+
+	// Both processors agree on the block, no bundle is written
+	_, err := blockchain.InsertChain(types.Blocks{block})
+	require.NoError(t, err)
+	require.Equal(t, block.Hash(), blockchain.CurrentBlock().Hash())
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	require.Empty(t, entries)
+
+	// Mismatching executions are reported with a debug bundle
+	execute := func() *shadowExecution {
+		execution := new(shadowExecution)
+
+		execution.statedb, err = blockchain.StateAt(blockchain.Genesis().Root())
+		require.NoError(t, err)
+
+		execution.receipts, execution.logs, execution.usedGas, execution.err = blockchain.processor.Process(block, execution.statedb, blockchain.vmConfig, nil)
+		require.NoError(t, execution.err)
+
+		return execution
+	}
+
+	serial, parallel := execute(), execute()
+	require.Empty(t, compareShadowExecutions(serial, parallel, true))
+
+	parallel.receipts[1].GasUsed++
+	parallel.usedGas++
+
+	mismatches := compareShadowExecutions(serial, parallel, true)
+	require.Equal(t, []string{"gas used: serial 63000, parallel 63001", "receipt 1: gas used: serial 21000, parallel 21001"}, mismatches)
+
+	path, err := blockchain.writeShadowBundle(dir, block, blockchain.Genesis().Header(), serial, parallel, mismatches)
+	require.NoError(t, err)
+
+	for _, name := range []string{"block.rlp", "alloc.json", "env.json", "txs.json", "deps.json", "report.json"} {
+		require.FileExists(t, filepath.Join(path, name))
+	}
+
+	data, err := os.ReadFile(filepath.Join(path, "alloc.json"))
+	require.NoError(t, err)
+
+	var alloc GenesisAlloc
+	require.NoError(t, json.Unmarshal(data, &alloc))
+
+	// The pre-state holds the accounts of the senders
+	for _, tx := range block.Transactions() {
+		from, err := types.Sender(types.LatestSigner(params.TestChainConfig), tx)
+		require.NoError(t, err)
+		require.Contains(t, alloc, from)
+		require.Equal(t, big.NewInt(params.Ether), alloc[from].Balance)
+	}
+
+	data, err = os.ReadFile(filepath.Join(path, "report.json"))
+	require.NoError(t, err)
+
+	var report shadowReport
+	require.NoError(t, json.Unmarshal(data, &report))
+	require.Equal(t, block.Hash(), report.Hash)
+	require.Equal(t, mismatches, report.Mismatches)
+	require.Equal(t, report.Serial.StateRoot, report.Parallel.StateRoot)
+	require.Equal(t, uint64(63000), report.Serial.GasUsed)
+	require.Equal(t, uint64(63001), report.Parallel.GasUsed)
+}
+
+func TestAccessedState(t *testing.T) {
+	t.Parallel()
+
+	blockchain, block := newParallelTestChain(t)
+	defer blockchain.Stop()
+
+	statedb, err := blockchain.StateAt(blockchain.Genesis().Root())
+	require.NoError(t, err)
+
+	_, _, _, err = blockchain.processor.Process(block, statedb, blockchain.vmConfig, nil)
+	require.NoError(t, err)
+
+	accessed := statedb.AccessedState()
+
+	// The senders and the recipients of the transfers are accessed
+	for j := 1; j <= 3; j++ {
+		require.Contains(t, accessed, common.Address{byte(j)})
+	}
+
+	key, _ := crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+	require.Contains(t, accessed, crypto.PubkeyToAddress(key.PublicKey))
+}
@@ -26,10 +26,12 @@ import (
 	"github.com/ethereum/go-ethereum/consensus"
 	"github.com/ethereum/go-ethereum/consensus/misc"
 	"github.com/ethereum/go-ethereum/core/blockstm"
+	"github.com/ethereum/go-ethereum/core/rawdb"
 	"github.com/ethereum/go-ethereum/core/state"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/core/vm"
 	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethdb"
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/ethereum/go-ethereum/metrics"
 	"github.com/ethereum/go-ethereum/params"
@@ -38,6 +40,9 @@ import (
 type ParallelEVMConfig struct {
 	Enable               bool
 	SpeculativeProcesses int
+	Adaptive             bool   // Adjust the speculative processes to every block, executing serially when parallel would lose
+	ShadowInterval       uint64 // Every how many blocks to compare the serial and parallel processors (0 = disabled)
+	ShadowDir            string // Directory of the debug bundles of the blocks the processors mismatch on
 }
 
 // StateProcessor is a basic Processor, which takes care of transitioning
@@ -261,8 +266,14 @@ var parallelizabilityTimer = metrics.NewRegisteredTimer("block/parallelizability
 // Process returns the receipts and logs accumulated during the process and
 // returns the amount of gas that was used in the process. If any of the
 // transactions failed to execute due to insufficient gas it will return an error.
-// nolint:gocognit
 func (p *ParallelStateProcessor) Process(block *types.Block, statedb *state.StateDB, cfg vm.Config, interruptCtx context.Context) (types.Receipts, []*types.Log, uint64, error) {
+	return p.process(block, statedb, cfg, interruptCtx, p.bc.parallelSpeculativeProcesses, nil)
+}
+
+// process executes the block with the given number of speculative processes,
+// profiling the execution into stats if not nil.
+// nolint:gocognit
+func (p *ParallelStateProcessor) process(block *types.Block, statedb *state.StateDB, cfg vm.Config, interruptCtx context.Context, numProcs int, stats *ParallelExecutionStats) (types.Receipts, []*types.Log, uint64, error) {
 	var (
 		receipts    types.Receipts
 		header      = block.Header()
@@ -284,15 +295,12 @@ func (p *ParallelStateProcessor) Process(block *types.Block, statedb *state.Stat
 
 	coinbase, _ := p.bc.Engine().Author(header)
 
-	blockTxDependency := block.GetTxDependency()
+	// Use the dependencies of the header metadata, or the ones computed on a
+	// previous execution of the block
+	blockTxDependency := p.bc.GetTxDependency(block)
 
 	deps := GetDeps(blockTxDependency)
 
-	if !VerifyDeps(deps) || len(blockTxDependency) != len(block.Transactions()) {
-		blockTxDependency = nil
-		deps = make(map[int][]int)
-	}
-
 	if blockTxDependency != nil {
 		metadata = true
 	}
@@ -341,8 +349,8 @@ func (p *ParallelStateProcessor) Process(block *types.Block, statedb *state.Stat
 
 	backupStateDB := statedb.Copy()
 
-	profile := false
-	result, err := blockstm.ExecuteParallel(tasks, profile, metadata, p.bc.parallelSpeculativeProcesses, interruptCtx)
+	profile := stats != nil
+	result, err := blockstm.ExecuteParallel(tasks, profile, metadata, numProcs, interruptCtx)
 
 	if err == nil && profile && result.Deps != nil {
 		_, weight := result.Deps.LongestPath(*result.Stats)
@@ -376,7 +384,7 @@ func (p *ParallelStateProcessor) Process(block *types.Block, statedb *state.Stat
 				t.totalUsedGas = usedGas
 			}
 
-			_, err = blockstm.ExecuteParallel(tasks, false, metadata, p.bc.parallelSpeculativeProcesses, interruptCtx)
+			result, err = blockstm.ExecuteParallel(tasks, profile, metadata, numProcs, interruptCtx)
 
 			break
 		}
@@ -386,6 +394,21 @@ func (p *ParallelStateProcessor) Process(block *types.Block, statedb *state.Stat
 		return nil, nil, 0, err
 	}
 
+	if stats != nil {
+		stats.fill(block, metadata, numProcs, result)
+	} else if !metadata {
+		p.bc.parallelPolicy.record(len(tasks), result.Summary)
+	}
+
+	// Store the dependencies found when executing the block without metadata,
+	// for the next executions. They are unusable if the fees were not delayed.
+	if !metadata && shouldDelayFeeCal && len(tasks) > 0 && result.TxIO != nil {
+		blockTxDependency = blockstm.BuildDAG(*result.TxIO).TxDependency(len(tasks))
+		if VerifyDeps(GetDeps(blockTxDependency)) {
+			rawdb.WriteTxDependency(p.bc.db, blockHash, blockNumber.Uint64(), blockTxDependency)
+		}
+	}
+
 	// Finalize the block, applying any consensus engine specific extras (e.g. block rewards)
 	p.engine.Finalize(p.bc, header, statedb, block.Transactions(), block.Uncles(), nil)
 
@@ -406,6 +429,29 @@ func GetDeps(txDependency [][]uint64) map[int][]int {
 	return deps
 }
 
+// ReadTxDependency returns the dependencies between the transactions of a block,
+// from its header metadata or else from the ones stored in the database when
+// the block was executed without it. It returns nil if they are unknown.
+func ReadTxDependency(db ethdb.KeyValueReader, block *types.Block) [][]uint64 {
+	n := len(block.Transactions())
+
+	if deps := block.GetTxDependency(); validTxDependency(deps, n) {
+		return deps
+	}
+
+	if deps := rawdb.ReadTxDependency(db, block.Hash(), block.NumberU64()); validTxDependency(deps, n) {
+		return deps
+	}
+
+	return nil
+}
+
+// validTxDependency returns true if the given dependencies are usable for a
+// block of n transactions.
+func validTxDependency(txDependency [][]uint64, n int) bool {
+	return len(txDependency) > 0 && len(txDependency) == n && VerifyDeps(GetDeps(txDependency))
+}
+
 // returns true if dependencies are correct
 func VerifyDeps(deps map[int][]int) bool {
 	// number of transactions in the block
@@ -1,11 +1,60 @@
 package core
 
 import (
+	"crypto/ecdsa"
+	"math/big"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/ethash"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
 )
 
+// newParallelTestChain creates a parallel blockchain, and a block on top of its
+// genesis whose third transaction depends on the first one, from the same sender.
+func newParallelTestChain(t *testing.T) (*BlockChain, *types.Block) {
+	t.Helper()
+
+	var (
+		key1, _ = crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+		key2, _ = crypto.HexToECDSA("8a1f9a8f95be41cd7ccb6168179afb4504aefe388d1e14474d32c45c72ce7b7a")
+		addr1   = crypto.PubkeyToAddress(key1.PublicKey)
+		addr2   = crypto.PubkeyToAddress(key2.PublicKey)
+		gspec   = &Genesis{
+			Config: params.TestChainConfig,
+			Alloc: GenesisAlloc{
+				addr1: {Balance: big.NewInt(params.Ether)},
+				addr2: {Balance: big.NewInt(params.Ether)},
+			},
+		}
+		signer = types.LatestSigner(gspec.Config)
+	)
+
+	blockchain, err := NewParallelBlockChain(rawdb.NewMemoryDatabase(), nil, gspec, nil, ethash.NewFaker(), vm.Config{}, nil, nil, nil, 4)
+	require.NoError(t, err)
+
+	_, blocks, _ := GenerateChainWithGenesis(gspec, ethash.NewFaker(), 1, func(i int, gen *BlockGen) {
+		for j, key := range []*ecdsa.PrivateKey{key1, key2, key1} {
+			nonce := gen.TxNonce(crypto.PubkeyToAddress(key.PublicKey))
+
+			tx, err := types.SignTx(types.NewTransaction(nonce, common.Address{byte(j + 1)}, big.NewInt(1000), params.TxGas, gen.BaseFee(), nil), signer, key)
+			require.NoError(t, err)
+
+			gen.AddTx(tx)
+		}
+	})
+
+	return blockchain, blocks[0]
+}
+
 func TestMetadata(t *testing.T) {
 	t.Parallel()
 
@@ -28,3 +77,71 @@ func TestMetadata(t *testing.T) {
 	temp = GetDeps(wrongTxDependencyOutOfRange)
 	assert.Equal(t, false, VerifyDeps(temp))
 }
+
+func TestParallelTxDependencyCache(t *testing.T) {
+	t.Parallel()
+
+	blockchain, block := newParallelTestChain(t)
+	defer blockchain.Stop()
+
+	db := blockchain.db
+
+	require.Nil(t, blockchain.GetTxDependency(block))
+	require.Equal(t, blockchain.processor, blockchain.ReplayProcessor(block))
+
+	process := func() {
+		statedb, err := state.New(blockchain.Genesis().Root(), blockchain.stateCache, nil)
+		require.NoError(t, err)
+
+		_, _, _, err = blockchain.parallelProcessor.Process(block, statedb, vm.Config{}, nil)
+		require.NoError(t, err)
+	}
+
+	process()
+
+	expected := [][]uint64{{}, {}, {0}}
+	require.Equal(t, expected, rawdb.ReadTxDependency(db, block.Hash(), block.NumberU64()))
+	require.Equal(t, expected, blockchain.GetTxDependency(block))
+	require.Equal(t, blockchain.parallelProcessor, blockchain.ReplayProcessor(block))
+
+	// Executing again uses the cached dependencies
+	process()
+
+	rawdb.DeleteBlock(db, block.Hash(), block.NumberU64())
+	require.Nil(t, rawdb.ReadTxDependency(db, block.Hash(), block.NumberU64()))
+}
+
+func TestParallelExecutionStats(t *testing.T) {
+	t.Parallel()
+
+	blockchain, block := newParallelTestChain(t)
+	defer blockchain.Stop()
+
+	statedb, err := state.New(blockchain.Genesis().Root(), blockchain.stateCache, nil)
+	require.NoError(t, err)
+
+	stats, err := blockchain.ParallelExecutionStats(block, statedb)
+	require.NoError(t, err)
+
+	require.Equal(t, block.Hash(), stats.Hash)
+	require.Equal(t, 3, stats.Transactions)
+	require.False(t, stats.Metadata)
+	require.Equal(t, 4, stats.SpeculativeProcesses)
+	require.GreaterOrEqual(t, stats.Executions, 3)
+	require.Equal(t, stats.Executions-3, stats.Reexecutions)
+	require.Len(t, stats.Incarnations, 3)
+	require.NotEmpty(t, stats.LongestPath)
+	require.IsIncreasing(t, stats.LongestPath)
+	require.Positive(t, stats.SerialTime)
+	require.GreaterOrEqual(t, stats.SerialTime, stats.LongestPathTime)
+	require.GreaterOrEqual(t, stats.EstimatedSpeedup, 1.0)
+	require.Greater(t, stats.Utilisation, 0.0)
+
+	// The dependencies are known on the next execution
+	statedb, err = state.New(blockchain.Genesis().Root(), blockchain.stateCache, nil)
+	require.NoError(t, err)
+
+	stats, err = blockchain.ParallelExecutionStats(block, statedb)
+	require.NoError(t, err)
+	require.True(t, stats.Metadata)
+}
@@ -929,6 +929,8 @@ func DeleteBlock(db ethdb.KeyValueWriter, hash common.Hash, number uint64) {
 
 	// delete bor receipt
 	DeleteBorReceipt(db, hash, number)
+
+	DeleteTxDependency(db, hash, number)
 }
 
 // DeleteBlockWithoutNumber removes all block data associated with a hash, except
@@ -941,6 +943,8 @@ func DeleteBlockWithoutNumber(db ethdb.KeyValueWriter, hash common.Hash, number
 
 	// delete bor receipt
 	DeleteBorReceipt(db, hash, number)
+
+	DeleteTxDependency(db, hash, number)
 }
 
 const badBlockToKeep = 10
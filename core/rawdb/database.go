@@ -497,6 +497,9 @@ func InspectDatabase(db ethdb.Database, keyPrefix, keyStart []byte) error {
 		bloomBits       stat
 		beaconHeaders   stat
 		cliqueSnaps     stat
+		borSnaps        stat
+		txDependencies  stat
+		heimdallCache   stat
 
 		// Les statistic
 		chtTrieNodes   stat
@@ -561,6 +564,15 @@ func InspectDatabase(db ethdb.Database, keyPrefix, keyStart []byte) error {
 			beaconHeaders.Add(size)
 		case bytes.HasPrefix(key, CliqueSnapshotPrefix) && len(key) == 7+common.HashLength:
 			cliqueSnaps.Add(size)
+		case bytes.HasPrefix(key, BorSnapshotPrefix) && len(key) == len(BorSnapshotPrefix)+common.HashLength:
+			borSnaps.Add(size)
+		case bytes.HasPrefix(key, txDependencyPrefix) && len(key) == len(txDependencyPrefix)+8+common.HashLength:
+			txDependencies.Add(size)
+		case bytes.HasPrefix(key, heimdallSpanPrefix) && len(key) == len(heimdallSpanPrefix)+8,
+			bytes.HasPrefix(key, heimdallCheckpointPrefix) && len(key) == len(heimdallCheckpointPrefix)+8,
+			bytes.HasPrefix(key, heimdallEventPrefix) && len(key) == len(heimdallEventPrefix)+8,
+			bytes.HasPrefix(key, heimdallEventRangePrefix) && len(key) == len(heimdallEventRangePrefix)+8:
+			heimdallCache.Add(size)
 		case bytes.HasPrefix(key, ChtTablePrefix) ||
 			bytes.HasPrefix(key, ChtIndexTablePrefix) ||
 			bytes.HasPrefix(key, ChtPrefix): // Canonical hash trie
@@ -619,6 +631,9 @@ func InspectDatabase(db ethdb.Database, keyPrefix, keyStart []byte) error {
 		{"Key-Value store", "Storage snapshot", storageSnaps.Size(), storageSnaps.Count()},
 		{"Key-Value store", "Beacon sync headers", beaconHeaders.Size(), beaconHeaders.Count()},
 		{"Key-Value store", "Clique snapshots", cliqueSnaps.Size(), cliqueSnaps.Count()},
+		{"Key-Value store", "Bor snapshots", borSnaps.Size(), borSnaps.Count()},
+		{"Key-Value store", "Block-STM dependencies", txDependencies.Size(), txDependencies.Count()},
+		{"Key-Value store", "Heimdall cache", heimdallCache.Size(), heimdallCache.Count()},
 		{"Key-Value store", "Singleton metadata", metadata.Size(), metadata.Count()},
 		{"Light client", "CHT trie nodes", chtTrieNodes.Size(), chtTrieNodes.Count()},
 		{"Light client", "Bloom trie nodes", bloomTrieNodes.Size(), bloomTrieNodes.Count()},
@@ -0,0 +1,111 @@
+package rawdb
+
+import (
+	"encoding/binary"
+	"fmt"
+	"time"
+
+	json "github.com/json-iterator/go"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/generics"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+var (
+	milestoneHistoryPrefix  = []byte("MilestoneHistory-")  // milestoneHistoryPrefix + seq (uint64 big endian) -> FinalityRecord
+	checkpointHistoryPrefix = []byte("CheckpointHistory-") // checkpointHistoryPrefix + seq (uint64 big endian) -> FinalityRecord
+)
+
+// FinalityRecord is the processing of a milestone or checkpoint by the node.
+type FinalityRecord struct {
+	Seq        uint64         `json:"seq"`
+	StartBlock uint64         `json:"startBlock"`
+	EndBlock   uint64         `json:"endBlock"`
+	Hash       common.Hash    `json:"hash"` // End block hash of a milestone, root hash of a checkpoint
+	Proposer   common.Address `json:"proposer"`
+	Timestamp  uint64         `json:"timestamp"` // Creation time in Heimdall
+
+	Outcome  string `json:"outcome"`
+	Error    string `json:"error,omitempty"`
+	Head     uint64 `json:"head"`               // Local head when verified
+	RewindTo uint64 `json:"rewindTo,omitempty"` // Block the chain was rewound to on mismatch
+	Attempts uint64 `json:"attempts"`           // Number of times it was verified
+
+	FirstSeen      time.Time     `json:"firstSeen"`
+	LastSeen       time.Time     `json:"lastSeen"`
+	VerifyDuration time.Duration `json:"verifyDuration"` // Duration of the last verification
+}
+
+func getHistoryPrefix[T BlockFinality[T]]() []byte {
+	switch any(generics.Empty[T]().clone()).(type) {
+	case *Milestone:
+		return milestoneHistoryPrefix
+	case *Checkpoint:
+		return checkpointHistoryPrefix
+	}
+
+	return nil
+}
+
+func finalityRecordKey[T BlockFinality[T]](seq uint64) []byte {
+	prefix := getHistoryPrefix[T]()
+
+	key := make([]byte, len(prefix)+8)
+	copy(key, prefix)
+	binary.BigEndian.PutUint64(key[len(prefix):], seq)
+
+	return key
+}
+
+// ReadFinalityHistory retrieves all the stored milestone or checkpoint records,
+// in the order they were processed.
+func ReadFinalityHistory[T BlockFinality[T]](db ethdb.Iteratee) ([]*FinalityRecord, error) {
+	prefix := getHistoryPrefix[T]()
+
+	it := db.NewIterator(prefix, nil)
+	defer it.Release()
+
+	var records []*FinalityRecord
+
+	for it.Next() {
+		if len(it.Key()) != len(prefix)+8 {
+			continue
+		}
+
+		record := new(FinalityRecord)
+		if err := json.Unmarshal(it.Value(), record); err != nil {
+			return nil, fmt.Errorf("%w(%v) for %s%x", ErrIncorrectFinality, err, string(prefix), it.Key()[len(prefix):])
+		}
+
+		records = append(records, record)
+	}
+
+	return records, it.Error()
+}
+
+// WriteFinalityRecord stores a milestone or checkpoint record under its sequence number.
+func WriteFinalityRecord[T BlockFinality[T]](db ethdb.KeyValueWriter, record *FinalityRecord) error {
+	enc, err := json.Marshal(record)
+	if err != nil {
+		log.Error("Failed to marshal the finality record", "err", err)
+
+		return fmt.Errorf("%w: %v for finality record", ErrIncorrectFinalityToStore, err)
+	}
+
+	if err := db.Put(finalityRecordKey[T](record.Seq), enc); err != nil {
+		log.Error("Failed to store the finality record", "err", err)
+
+		return fmt.Errorf("%w: %v for finality record", ErrDBNotResponding, err)
+	}
+
+	return nil
+}
+
+// DeleteFinalityRecord removes the milestone or checkpoint record of the given sequence number.
+func DeleteFinalityRecord[T BlockFinality[T]](db ethdb.KeyValueWriter, seq uint64) {
+	if err := db.Delete(finalityRecordKey[T](seq)); err != nil {
+		log.Crit("Failed to delete finality record", "err", err)
+	}
+}
@@ -0,0 +1,113 @@
+package rawdb
+
+import (
+	"encoding/binary"
+
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// The Heimdall cache keys share the matic- namespace of the other Bor keys,
+// none of their prefixes being a prefix of another key prefix.
+var (
+	// heimdallSpanPrefix + span id (uint64 big endian) -> JSON encoded span
+	heimdallSpanPrefix = []byte("matic-hspan-")
+
+	// heimdallCheckpointPrefix + checkpoint number (uint64 big endian) -> JSON encoded checkpoint
+	heimdallCheckpointPrefix = []byte("matic-hcheckpoint-")
+
+	// heimdallEventPrefix + event id (uint64 big endian) -> JSON encoded state-sync event record
+	heimdallEventPrefix = []byte("matic-hevent-")
+
+	// heimdallEventRangePrefix + from id (uint64 big endian) -> last id (uint64 big endian) + to time (uint64 big endian)
+	heimdallEventRangePrefix = []byte("matic-hrange-")
+)
+
+// heimdallSpanKey = heimdallSpanPrefix + id (uint64 big endian)
+func heimdallSpanKey(id uint64) []byte {
+	return append(heimdallSpanPrefix, encodeBlockNumber(id)...)
+}
+
+// heimdallCheckpointKey = heimdallCheckpointPrefix + number (uint64 big endian)
+func heimdallCheckpointKey(number uint64) []byte {
+	return append(heimdallCheckpointPrefix, encodeBlockNumber(number)...)
+}
+
+// heimdallEventKey = heimdallEventPrefix + id (uint64 big endian)
+func heimdallEventKey(id uint64) []byte {
+	return append(heimdallEventPrefix, encodeBlockNumber(id)...)
+}
+
+// heimdallEventRangeKey = heimdallEventRangePrefix + from id (uint64 big endian)
+func heimdallEventRangeKey(fromID uint64) []byte {
+	return append(heimdallEventRangePrefix, encodeBlockNumber(fromID)...)
+}
+
+// ReadHeimdallSpan retrieves the cached Heimdall span with the given id.
+func ReadHeimdallSpan(db ethdb.KeyValueReader, id uint64) []byte {
+	data, _ := db.Get(heimdallSpanKey(id))
+	return data
+}
+
+// WriteHeimdallSpan stores a Heimdall span in the cache.
+func WriteHeimdallSpan(db ethdb.KeyValueWriter, id uint64, data []byte) {
+	if err := db.Put(heimdallSpanKey(id), data); err != nil {
+		log.Crit("Failed to store heimdall span", "id", id, "err", err)
+	}
+}
+
+// DeleteHeimdallSpan removes a Heimdall span from the cache.
+func DeleteHeimdallSpan(db ethdb.KeyValueWriter, id uint64) {
+	if err := db.Delete(heimdallSpanKey(id)); err != nil {
+		log.Crit("Failed to delete heimdall span", "id", id, "err", err)
+	}
+}
+
+// ReadHeimdallCheckpoint retrieves the cached Heimdall checkpoint with the given number.
+func ReadHeimdallCheckpoint(db ethdb.KeyValueReader, number uint64) []byte {
+	data, _ := db.Get(heimdallCheckpointKey(number))
+	return data
+}
+
+// WriteHeimdallCheckpoint stores a Heimdall checkpoint in the cache.
+func WriteHeimdallCheckpoint(db ethdb.KeyValueWriter, number uint64, data []byte) {
+	if err := db.Put(heimdallCheckpointKey(number), data); err != nil {
+		log.Crit("Failed to store heimdall checkpoint", "number", number, "err", err)
+	}
+}
+
+// ReadHeimdallEventRecord retrieves the cached state-sync event record with the given id.
+func ReadHeimdallEventRecord(db ethdb.KeyValueReader, id uint64) []byte {
+	data, _ := db.Get(heimdallEventKey(id))
+	return data
+}
+
+// WriteHeimdallEventRecord stores a state-sync event record in the cache.
+func WriteHeimdallEventRecord(db ethdb.KeyValueWriter, id uint64, data []byte) {
+	if err := db.Put(heimdallEventKey(id), data); err != nil {
+		log.Crit("Failed to store heimdall event record", "id", id, "err", err)
+	}
+}
+
+// ReadHeimdallEventRange retrieves the range of state-sync events known to be
+// complete from the given id: all the events with an id of at least fromID and
+// a record time before toTime have an id of at most lastID.
+func ReadHeimdallEventRange(db ethdb.KeyValueReader, fromID uint64) (lastID uint64, toTime uint64, ok bool) {
+	data, _ := db.Get(heimdallEventRangeKey(fromID))
+	if len(data) != 16 {
+		return 0, 0, false
+	}
+
+	return binary.BigEndian.Uint64(data[:8]), binary.BigEndian.Uint64(data[8:]), true
+}
+
+// WriteHeimdallEventRange stores a complete range of state-sync events.
+func WriteHeimdallEventRange(db ethdb.KeyValueWriter, fromID uint64, lastID uint64, toTime uint64) {
+	data := make([]byte, 16)
+	binary.BigEndian.PutUint64(data[:8], lastID)
+	binary.BigEndian.PutUint64(data[8:], toTime)
+
+	if err := db.Put(heimdallEventRangeKey(fromID), data); err != nil {
+		log.Crit("Failed to store heimdall event range", "from", fromID, "err", err)
+	}
+}
@@ -0,0 +1,71 @@
+package rawdb
+
+import (
+	"encoding/binary"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// reorgPrefix + time (uint64 big endian unix nanoseconds) -> JSON encoded reorg record
+var reorgPrefix = []byte("reorg-")
+
+// reorgKey = reorgPrefix + time (uint64 big endian)
+func reorgKey(time uint64) []byte {
+	return append(reorgPrefix, encodeBlockNumber(time)...)
+}
+
+// WriteReorg stores the record of a chain reorganisation at the given time.
+func WriteReorg(db ethdb.KeyValueWriter, time uint64, data []byte) {
+	if err := db.Put(reorgKey(time), data); err != nil {
+		log.Crit("Failed to store reorg", "err", err)
+	}
+}
+
+// ReadReorgs retrieves up to limit records of chain reorganisations from the
+// given time on, oldest first.
+func ReadReorgs(db ethdb.Iteratee, from uint64, limit int) [][]byte {
+	it := db.NewIterator(reorgPrefix, encodeBlockNumber(from))
+	defer it.Release()
+
+	var records [][]byte
+
+	for it.Next() && len(records) < limit {
+		if len(it.Key()) != len(reorgPrefix)+8 {
+			continue
+		}
+
+		records = append(records, common.CopyBytes(it.Value()))
+	}
+
+	return records
+}
+
+// DeleteReorgsBefore removes the records of the chain reorganisations older than
+// the given time.
+func DeleteReorgsBefore(db ethdb.KeyValueStore, time uint64) {
+	it := db.NewIterator(reorgPrefix, nil)
+	defer it.Release()
+
+	batch := db.NewBatch()
+
+	for it.Next() {
+		key := it.Key()
+		if len(key) != len(reorgPrefix)+8 {
+			continue
+		}
+
+		if binary.BigEndian.Uint64(key[len(reorgPrefix):]) >= time {
+			break
+		}
+
+		if err := batch.Delete(key); err != nil {
+			log.Crit("Failed to delete reorg", "err", err)
+		}
+	}
+
+	if err := batch.Write(); err != nil {
+		log.Crit("Failed to delete reorgs", "err", err)
+	}
+}
@@ -0,0 +1,52 @@
+package rawdb
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// txDependencyPrefix + num (uint64 big endian) + hash -> transaction dependencies of a block
+var txDependencyPrefix = []byte("blockstm-deps-")
+
+// txDependencyKey = txDependencyPrefix + num (uint64 big endian) + hash
+func txDependencyKey(number uint64, hash common.Hash) []byte {
+	return append(append(txDependencyPrefix, encodeBlockNumber(number)...), hash.Bytes()...)
+}
+
+// ReadTxDependency retrieves the transaction dependencies computed for a block
+// which had none in its header, in the format of the header metadata.
+func ReadTxDependency(db ethdb.KeyValueReader, hash common.Hash, number uint64) [][]uint64 {
+	data, _ := db.Get(txDependencyKey(number, hash))
+	if len(data) == 0 {
+		return nil
+	}
+
+	var deps [][]uint64
+	if err := rlp.DecodeBytes(data, &deps); err != nil {
+		log.Error("Invalid transaction dependencies RLP", "hash", hash, "err", err)
+		return nil
+	}
+
+	return deps
+}
+
+// WriteTxDependency stores the transaction dependencies computed for a block.
+func WriteTxDependency(db ethdb.KeyValueWriter, hash common.Hash, number uint64, deps [][]uint64) {
+	data, err := rlp.EncodeToBytes(deps)
+	if err != nil {
+		log.Crit("Failed to RLP encode transaction dependencies", "err", err)
+	}
+
+	if err := db.Put(txDependencyKey(number, hash), data); err != nil {
+		log.Crit("Failed to store transaction dependencies", "err", err)
+	}
+}
+
+// DeleteTxDependency removes the transaction dependencies computed for a block.
+func DeleteTxDependency(db ethdb.KeyValueWriter, hash common.Hash, number uint64) {
+	if err := db.Delete(txDependencyKey(number, hash)); err != nil {
+		log.Crit("Failed to delete transaction dependencies", "err", err)
+	}
+}
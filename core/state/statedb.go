@@ -264,6 +264,35 @@ func (s *StateDB) HadInvalidRead() bool {
 	return s.dep >= 0
 }
 
+// AccessedState returns the accounts loaded or destructed by the state, with
+// the storage slots read or written of each of them.
+func (s *StateDB) AccessedState() map[common.Address][]common.Hash {
+	accessed := make(map[common.Address][]common.Hash, len(s.stateObjects)+len(s.stateObjectsDestruct))
+
+	for addr := range s.stateObjectsDestruct {
+		accessed[addr] = nil
+	}
+
+	for addr, obj := range s.stateObjects {
+		slots := make(map[common.Hash]struct{})
+
+		for _, storage := range []Storage{obj.originStorage, obj.pendingStorage, obj.dirtyStorage} {
+			for key := range storage {
+				slots[key] = struct{}{}
+			}
+		}
+
+		keys := accessed[addr]
+		for key := range slots {
+			keys = append(keys, key)
+		}
+
+		accessed[addr] = keys
+	}
+
+	return accessed
+}
+
 func (s *StateDB) DepTxIndex() int {
 	return s.dep
 }
@@ -429,8 +429,35 @@ func (pool *TxPool) MevBundles(blockNumber *big.Int, blockTimestamp uint64) ([]t
 	return ret, nil
 }
 
+// CountMevBundles returns the number of bundles valid for the given
+// blockNumber/blockTimestamp without pruning the outdated ones.
+func (pool *TxPool) CountMevBundles(blockNumber *big.Int, blockTimestamp uint64) int {
+	pool.mu.Lock()
+	defer pool.mu.Unlock()
+
+	count := 0
+
+	for _, bundle := range pool.mevBundles {
+		if bundle.MaxTimestamp != 0 && blockTimestamp > bundle.MaxTimestamp {
+			continue
+		}
+
+		if bundle.MinTimestamp != 0 && blockTimestamp < bundle.MinTimestamp {
+			continue
+		}
+
+		if blockNumber.Cmp(bundle.BlockNumber) != 0 || len(bundle.Txs) == 0 {
+			continue
+		}
+
+		count++
+	}
+
+	return count
+}
+
 // AddMevBundle adds a mev bundle to the pool
-func (pool *TxPool) AddMevBundle(txs types.Transactions, blockNumber *big.Int, minTimestamp, maxTimestamp uint64, revertingTxHashes []common.Hash) error {
+func (pool *TxPool) AddMevBundle(txs types.Transactions, blockNumber *big.Int, minTimestamp, maxTimestamp uint64, revertingTxHashes []common.Hash, targetTxHash *common.Hash) error {
 	pool.mu.Lock()
 	defer pool.mu.Unlock()
 
@@ -440,6 +467,7 @@ func (pool *TxPool) AddMevBundle(txs types.Transactions, blockNumber *big.Int, m
 		MinTimestamp:      minTimestamp,
 		MaxTimestamp:      maxTimestamp,
 		RevertingTxHashes: revertingTxHashes,
+		TargetTxHash:      targetTxHash,
 	})
 	return nil
 }
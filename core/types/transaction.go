@@ -654,4 +654,5 @@ type MevBundle struct {
 	MinTimestamp      uint64
 	MaxTimestamp      uint64
 	RevertingTxHashes []common.Hash
+	TargetTxHash      *common.Hash // Pending transaction the bundle is placed after, nil for top-of-block
 }
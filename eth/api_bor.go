@@ -0,0 +1,190 @@
+package eth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/consensus/bor"
+	"github.com/ethereum/go-ethereum/consensus/bor/clerk"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/eth/tracers"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// stateSyncReexec is the number of blocks re-executed to regenerate a missing
+// historical state when simulating state-syncs.
+const stateSyncReexec = 128
+
+var errNotBor = errors.New("bor consensus is not in use")
+
+// BorAPI provides the Bor APIs needing the full node, like the re-execution
+// of historical state-syncs.
+type BorAPI struct {
+	eth *Ethereum
+}
+
+// NewBorAPI creates a new BorAPI instance.
+func NewBorAPI(eth *Ethereum) *BorAPI {
+	return &BorAPI{eth: eth}
+}
+
+// GetStateSyncEvents returns the state-sync events committed by a sprint-start
+// block, read from its state-sync receipt, with whether their receiver succeeded,
+// the gas they used and the logs their receiver emitted.
+func (api *BorAPI) GetStateSyncEvents(ctx context.Context, number rpc.BlockNumber) ([]*bor.AppliedStateSync, error) {
+	engine, ok := api.eth.engine.(*bor.Bor)
+	if !ok {
+		return nil, errNotBor
+	}
+
+	block, err := api.blockByNumber(number)
+	if err != nil {
+		return nil, err
+	}
+
+	if config := api.eth.blockchain.Config().Bor; !config.IsSprintStart(block.NumberU64()) {
+		return nil, bor.ErrNotSprintStart
+	}
+
+	// The blocks without state-sync have no receipt
+	receipt := api.eth.blockchain.GetBorReceiptByHash(block.Hash())
+	if receipt == nil {
+		return []*bor.AppliedStateSync{}, nil
+	}
+
+	applied := engine.AppliedStateSyncs(receipt.Logs)
+
+	// The gas used by the events is not stored, they are committed again on the
+	// state they were committed on
+	statedb, release, err := api.stateAfterTransactions(ctx, block)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	gasUsed, err := engine.StateSyncGas(ctx, statedb, block.Header(), api.eth.blockchain)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, event := range applied {
+		event.GasUsed = gasUsed[event.ID]
+	}
+
+	return applied, nil
+}
+
+// stateAfterTransactions returns the state of the block after its transactions
+// and before its finalization, by executing them on the state of its parent.
+func (api *BorAPI) stateAfterTransactions(ctx context.Context, block *types.Block) (*state.StateDB, tracers.StateReleaseFunc, error) {
+	parent := api.eth.blockchain.GetBlock(block.ParentHash(), block.NumberU64()-1)
+	if parent == nil {
+		return nil, nil, fmt.Errorf("parent %#x not found", block.ParentHash())
+	}
+
+	statedb, release, err := api.eth.stateAtBlock(ctx, parent, stateSyncReexec, nil, true, false)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var (
+		config  = api.eth.blockchain.Config()
+		signer  = types.MakeSigner(config, block.Number(), block.Time())
+		context = core.NewEVMBlockContext(block.Header(), api.eth.blockchain, nil)
+	)
+
+	for idx, tx := range block.Transactions() {
+		msg, _ := core.TransactionToMessage(tx, signer, block.BaseFee())
+		vmenv := vm.NewEVM(context, core.NewEVMTxContext(msg), statedb, config, vm.Config{})
+		statedb.SetTxContext(tx.Hash(), idx)
+
+		// nolint : contextcheck
+		if _, err := core.ApplyMessage(vmenv, msg, new(core.GasPool).AddGas(tx.Gas()), nil); err != nil {
+			release()
+			return nil, nil, fmt.Errorf("transaction %#x failed: %v", tx.Hash(), err)
+		}
+
+		statedb.Finalise(config.IsEIP158(block.Number()))
+	}
+
+	return statedb, release, nil
+}
+
+// SimulateStateSync applies a state-sync event on top of the state of the given
+// block, without committing it.
+func (api *BorAPI) SimulateStateSync(ctx context.Context, eventRecord clerk.EventRecordWithTime, number rpc.BlockNumber) (*bor.StateSyncResult, error) {
+	engine, ok := api.eth.engine.(*bor.Bor)
+	if !ok {
+		return nil, errNotBor
+	}
+
+	block, err := api.blockByNumber(number)
+	if err != nil {
+		return nil, err
+	}
+
+	statedb, release, err := api.eth.stateAtBlock(ctx, block, stateSyncReexec, nil, true, false)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	return engine.SimulateStateSync(&eventRecord, statedb, block.Header(), api.eth.blockchain)
+}
+
+// GetProducerStats returns the block production performance of every signer
+// seen since the node started: the blocks produced in-turn and out-of-turn,
+// the turns missed and the blocks reorged out of the chain.
+func (api *BorAPI) GetProducerStats() ([]*bor.ProducerStats, error) {
+	if api.eth.producerTracker == nil {
+		return nil, errNotBor
+	}
+
+	return api.eth.producerTracker.Stats(), nil
+}
+
+// GetMilestoneHistory returns the latest milestones processed by the node, oldest
+// first, with the outcome of their verification against the local chain. All the
+// milestones kept are returned if no limit is given.
+func (api *BorAPI) GetMilestoneHistory(limit *int) []*rawdb.FinalityRecord {
+	return api.eth.finalityHistory.Milestones(historyLimit(limit))
+}
+
+// GetCheckpointHistory returns the latest checkpoints processed by the node, oldest
+// first, with the outcome of their verification against the local chain. All the
+// checkpoints kept are returned if no limit is given.
+func (api *BorAPI) GetCheckpointHistory(limit *int) []*rawdb.FinalityRecord {
+	return api.eth.finalityHistory.Checkpoints(historyLimit(limit))
+}
+
+func historyLimit(limit *int) int {
+	if limit == nil {
+		return 0
+	}
+
+	return *limit
+}
+
+func (api *BorAPI) blockByNumber(number rpc.BlockNumber) (*types.Block, error) {
+	var block *types.Block
+
+	switch number {
+	case rpc.LatestBlockNumber, rpc.PendingBlockNumber:
+		if header := api.eth.blockchain.CurrentBlock(); header != nil {
+			block = api.eth.blockchain.GetBlock(header.Hash(), header.Number.Uint64())
+		}
+	default:
+		block = api.eth.blockchain.GetBlockByNumber(uint64(number.Int64()))
+	}
+
+	if block == nil {
+		return nil, fmt.Errorf("block #%d not found", number)
+	}
+
+	return block, nil
+}
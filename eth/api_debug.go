@@ -24,6 +24,7 @@ import (
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/rawdb"
 	"github.com/ethereum/go-ethereum/core/state"
 	"github.com/ethereum/go-ethereum/core/types"
@@ -443,3 +444,75 @@ func (api *DebugAPI) GetTrieFlushInterval() (string, error) {
 	}
 	return api.eth.blockchain.GetTrieFlushInterval().String(), nil
 }
+
+// GetReorgs returns the chain reorganisations since the given unix time, oldest
+// first, including the ones blocked by the whitelisted milestones and checkpoints.
+func (api *DebugAPI) GetReorgs(fromTime uint64) []*core.ReorgEvent {
+	return api.eth.blockchain.GetReorgs(time.Unix(int64(fromTime), 0))
+}
+
+// Reorgs send a notification each time the chain is reorganised, or a reorg is
+// blocked by the whitelisted milestones and checkpoints.
+func (api *DebugAPI) Reorgs(ctx context.Context) (*rpc.Subscription, error) {
+	notifier, supported := rpc.NotifierFromContext(ctx)
+	if !supported {
+		return &rpc.Subscription{}, rpc.ErrNotificationsUnsupported
+	}
+
+	rpcSub := notifier.CreateSubscription()
+
+	go func() {
+		reorgs := make(chan core.ReorgEvent, 16)
+		reorgsSub := api.eth.blockchain.SubscribeReorgEvent(reorgs)
+
+		defer reorgsSub.Unsubscribe()
+
+		for {
+			select {
+			case ev := <-reorgs:
+				notifier.Notify(rpcSub.ID, ev)
+			case <-rpcSub.Err():
+				return
+			case <-notifier.Closed():
+				return
+			}
+		}
+	}()
+
+	return rpcSub, nil
+}
+
+// parallelStatsReexec is the number of blocks re-executed to regenerate a missing
+// historical state when reporting a parallel execution.
+const parallelStatsReexec = 128
+
+// GetParallelExecutionStats executes the given block with Block-STM on the state
+// of its parent, and reports its re-executions, longest dependency chain, use of
+// the speculative processes and estimated speedup against the serial execution.
+func (api *DebugAPI) GetParallelExecutionStats(ctx context.Context, blockNrOrHash rpc.BlockNumberOrHash) (*core.ParallelExecutionStats, error) {
+	block, err := api.eth.APIBackend.BlockByNumberOrHash(ctx, blockNrOrHash)
+	if err != nil {
+		return nil, err
+	}
+
+	if block == nil {
+		return nil, fmt.Errorf("block %v not found", blockNrOrHash.String())
+	}
+
+	if block.NumberU64() == 0 {
+		return nil, errors.New("genesis is not executable")
+	}
+
+	parent := api.eth.blockchain.GetBlock(block.ParentHash(), block.NumberU64()-1)
+	if parent == nil {
+		return nil, fmt.Errorf("parent %#x not found", block.ParentHash())
+	}
+
+	statedb, release, err := api.eth.stateAtBlock(ctx, parent, parallelStatsReexec, nil, true, false)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	return api.eth.blockchain.ParallelExecutionStats(block, statedb)
+}
@@ -22,6 +22,7 @@ import (
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/miner"
 )
 
 // MinerAPI provides an API to control the miner.
@@ -83,3 +84,34 @@ func (api *MinerAPI) SetEtherbase(etherbase common.Address) bool {
 func (api *MinerAPI) SetRecommitInterval(interval int) {
 	api.e.Miner().SetRecommitInterval(time.Duration(interval) * time.Millisecond)
 }
+
+// SetMaxMergedBundles sets the maximum number of bundles merged into a block.
+func (api *MinerAPI) SetMaxMergedBundles(maxMergedBundles hexutil.Uint64) bool {
+	api.e.Miner().SetMaxMergedBundles(uint64(maxMergedBundles))
+	return true
+}
+
+// EnableBundles enables building blocks with bundles.
+func (api *MinerAPI) EnableBundles() bool {
+	api.e.Miner().SetBundlesEnabled(true)
+	return true
+}
+
+// DisableBundles disables building blocks with bundles.
+func (api *MinerAPI) DisableBundles() bool {
+	api.e.Miner().SetBundlesEnabled(false)
+	return true
+}
+
+// GetConfig returns the live configuration of the miner.
+func (api *MinerAPI) GetConfig() miner.Config {
+	config := api.e.Miner().Config()
+
+	api.e.lock.RLock()
+	if api.e.gasPrice != nil {
+		config.GasPrice = new(big.Int).Set(api.e.gasPrice)
+	}
+	api.e.lock.RUnlock()
+
+	return config
+}
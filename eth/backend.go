@@ -109,6 +109,9 @@ type Ethereum struct {
 
 	closeCh chan struct{} // Channel to signal the background processes to exit
 
+	producerTracker *bor.ProducerTracker // Tracks the block producers of a Bor chain
+	finalityHistory *whitelist.History   // History of the milestones and checkpoints processed
+
 	shutdownTracker *shutdowncheck.ShutdownTracker // Tracks if and when the node has shutdown ungracefully
 }
 
@@ -242,11 +245,22 @@ func New(stack *node.Node, config *ethconfig.Config) (*Ethereum, error) {
 	)
 
 	checker := whitelist.NewService(chainDb)
+	eth.finalityHistory = whitelist.NewHistory(chainDb)
 
 	// check if Parallel EVM is enabled
 	// if enabled, use parallel state processor
 	if config.ParallelEVM.Enable {
 		eth.blockchain, err = core.NewParallelBlockChain(chainDb, cacheConfig, config.Genesis, &overrides, eth.engine, vmConfig, eth.shouldPreserve, &config.TxLookupLimit, checker, config.ParallelEVM.SpeculativeProcesses)
+		if err == nil {
+			eth.blockchain.SetParallelAdaptive(config.ParallelEVM.Adaptive)
+
+			shadowDir := config.ParallelEVM.ShadowDir
+			if shadowDir == "" {
+				shadowDir = stack.ResolvePath("blockstm-mismatches")
+			}
+
+			eth.blockchain.SetParallelShadow(config.ParallelEVM.ShadowInterval, shadowDir)
+		}
 	} else {
 		eth.blockchain, err = core.NewBlockChain(chainDb, cacheConfig, config.Genesis, &overrides, eth.engine, vmConfig, eth.shouldPreserve, &config.TxLookupLimit, checker)
 	}
@@ -264,6 +278,10 @@ func New(stack *node.Node, config *ethconfig.Config) (*Ethereum, error) {
 
 	eth.bloomIndexer.Start(eth.blockchain)
 
+	if b, ok := eth.engine.(*bor.Bor); ok {
+		eth.producerTracker = bor.NewProducerTracker(b, eth.blockchain)
+	}
+
 	if config.BlobPool.Datadir != "" {
 		config.BlobPool.Datadir = stack.ResolvePath(config.BlobPool.Datadir)
 	}
@@ -291,12 +309,17 @@ func New(stack *node.Node, config *ethconfig.Config) (*Ethereum, error) {
 		RequiredBlocks:      config.RequiredBlocks,
 		EthAPI:              blockChainAPI,
 		checker:             checker,
+		finalityHistory:     eth.finalityHistory,
 		txArrivalWait:       eth.p2pServer.TxArrivalWait,
 		enableBlockTracking: eth.config.EnableBlockTracking,
 	}); err != nil {
 		return nil, err
 	}
 
+	if config.Miner.BuilderKey != nil && !miner.SupportsBuilderMode(eth.engine) {
+		return nil, errors.New("builder mode is not supported by the consensus engine")
+	}
+
 	eth.miner = miner.New(eth, &config.Miner, eth.blockchain.Config(), eth.EventMux(), eth.engine, eth.isLocalBlock)
 	eth.miner.SetExtra(makeExtraData(config.Miner.ExtraData))
 
@@ -382,6 +405,9 @@ func (s *Ethereum) APIs() []rpc.API {
 		}, {
 			Namespace: "debug",
 			Service:   NewDebugAPI(s),
+		}, {
+			Namespace: "bor",
+			Service:   NewBorAPI(s),
 		}, {
 			Namespace: "net",
 			Service:   s.netRPCService,
@@ -624,6 +650,10 @@ func (s *Ethereum) Start() error {
 	go s.startNoAckMilestoneService()
 	go s.startNoAckMilestoneByIDService()
 
+	if s.producerTracker != nil {
+		s.producerTracker.Start()
+	}
+
 	return nil
 }
 
@@ -831,6 +861,10 @@ func (s *Ethereum) Stop() error {
 	// Close all bg processes
 	close(s.closeCh)
 
+	if s.producerTracker != nil {
+		s.producerTracker.Stop()
+	}
+
 	s.txPool.Close()
 	s.miner.Close()
 	s.blockchain.Stop()
@@ -131,7 +131,7 @@ func borVerify(ctx context.Context, eth *Ethereum, handler *ethHandler, start ui
 
 		rewindBack(eth, head, rewindTo)
 
-		return hash, errHashMismatch
+		return hash, fmt.Errorf("%w: local %s, remote %s", errHashMismatch, localHash, hash)
 	}
 
 	// fetch the end block hash
@@ -0,0 +1,135 @@
+package whitelist
+
+import (
+	"sync"
+
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// historyLimit is the number of milestones and checkpoints records kept each
+const historyLimit = 1024
+
+// Outcomes of the verification of a milestone or checkpoint against the local chain
+const (
+	OutcomeMatched  = "matched"  // The local chain matched, the blocks were whitelisted
+	OutcomeRewound  = "rewound"  // The local chain mismatched and was rewound
+	OutcomeRejected = "rejected" // The local chain could not be verified
+	OutcomeFuture   = "future"   // The local chain was behind, the verification is retried
+)
+
+// History is the persisted history of the milestones and checkpoints processed
+// by the node, with the outcome of their verification.
+type History struct {
+	milestones  records[*rawdb.Milestone]
+	checkpoints records[*rawdb.Checkpoint]
+}
+
+// NewHistory loads the history stored in the given database.
+func NewHistory(db ethdb.Database) *History {
+	return &History{
+		milestones:  loadRecords[*rawdb.Milestone](db),
+		checkpoints: loadRecords[*rawdb.Checkpoint](db),
+	}
+}
+
+// RecordMilestone records the verification of a milestone.
+func (h *History) RecordMilestone(record *rawdb.FinalityRecord) {
+	h.milestones.add(record)
+}
+
+// RecordCheckpoint records the verification of a checkpoint.
+func (h *History) RecordCheckpoint(record *rawdb.FinalityRecord) {
+	h.checkpoints.add(record)
+}
+
+// Milestones returns up to the given number of latest milestone records, oldest first.
+func (h *History) Milestones(limit int) []*rawdb.FinalityRecord {
+	return h.milestones.latest(limit)
+}
+
+// Checkpoints returns up to the given number of latest checkpoint records, oldest first.
+func (h *History) Checkpoints(limit int) []*rawdb.FinalityRecord {
+	return h.checkpoints.latest(limit)
+}
+
+// records keeps the latest records of a kind of finality, mirroring the database.
+type records[T rawdb.BlockFinality[T]] struct {
+	list []*rawdb.FinalityRecord
+	lock sync.RWMutex
+	db   ethdb.Database
+}
+
+func loadRecords[T rawdb.BlockFinality[T]](db ethdb.Database) records[T] {
+	list, err := rawdb.ReadFinalityHistory[T](db)
+	if err != nil {
+		log.Error("Failed to load the finality history", "err", err)
+	}
+
+	return records[T]{list: list, db: db}
+}
+
+// add appends a record, or updates the last one if verifying the same blocks
+// again, pruning the oldest records past the limit.
+func (r *records[T]) add(record *rawdb.FinalityRecord) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	record = copyRecord(record)
+	record.Attempts = 1
+
+	if n := len(r.list); n > 0 {
+		last := r.list[n-1]
+
+		if last.StartBlock == record.StartBlock && last.EndBlock == record.EndBlock && last.Hash == record.Hash {
+			record.Seq = last.Seq
+			record.Attempts = last.Attempts + 1
+			record.FirstSeen = last.FirstSeen
+
+			r.list[n-1] = record
+		} else {
+			record.Seq = last.Seq + 1
+			r.list = append(r.list, record)
+		}
+	} else {
+		r.list = append(r.list, record)
+	}
+
+	batch := r.db.NewBatch()
+
+	for len(r.list) > historyLimit {
+		rawdb.DeleteFinalityRecord[T](batch, r.list[0].Seq)
+		r.list = r.list[1:]
+	}
+
+	if err := rawdb.WriteFinalityRecord[T](batch, record); err != nil {
+		return
+	}
+
+	if err := batch.Write(); err != nil {
+		log.Error("Failed to store the finality history", "err", err)
+	}
+}
+
+func (r *records[T]) latest(limit int) []*rawdb.FinalityRecord {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	list := r.list
+	if limit > 0 && len(list) > limit {
+		list = list[len(list)-limit:]
+	}
+
+	result := make([]*rawdb.FinalityRecord, 0, len(list))
+	for _, record := range list {
+		result = append(result, copyRecord(record))
+	}
+
+	return result
+}
+
+func copyRecord(record *rawdb.FinalityRecord) *rawdb.FinalityRecord {
+	cpy := *record
+	return &cpy
+}
@@ -0,0 +1,46 @@
+package whitelist
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+)
+
+func TestHistory(t *testing.T) {
+	t.Parallel()
+
+	db := rawdb.NewMemoryDatabase()
+	history := NewHistory(db)
+
+	// The same milestone verified again updates its record
+	history.RecordMilestone(&rawdb.FinalityRecord{StartBlock: 1, EndBlock: 16, Hash: common.HexToHash("0x1"), Outcome: OutcomeFuture})
+	history.RecordMilestone(&rawdb.FinalityRecord{StartBlock: 1, EndBlock: 16, Hash: common.HexToHash("0x1"), Outcome: OutcomeMatched})
+	history.RecordMilestone(&rawdb.FinalityRecord{StartBlock: 17, EndBlock: 32, Hash: common.HexToHash("0x2"), Outcome: OutcomeRewound, RewindTo: 16})
+	history.RecordCheckpoint(&rawdb.FinalityRecord{StartBlock: 0, EndBlock: 255, Hash: common.HexToHash("0x3"), Outcome: OutcomeMatched})
+
+	milestones := history.Milestones(0)
+	require.Len(t, milestones, 2)
+	require.Equal(t, OutcomeMatched, milestones[0].Outcome)
+	require.Equal(t, uint64(2), milestones[0].Attempts)
+	require.Equal(t, uint64(1), milestones[1].Seq)
+	require.Equal(t, uint64(16), milestones[1].RewindTo)
+
+	require.Len(t, history.Milestones(1), 1)
+	require.Equal(t, uint64(32), history.Milestones(1)[0].EndBlock)
+	require.Len(t, history.Checkpoints(0), 1)
+
+	// The history is reloaded from the database
+	require.Equal(t, milestones, NewHistory(db).Milestones(0))
+
+	// The oldest records are pruned past the limit
+	for i := uint64(0); i < historyLimit; i++ {
+		history.RecordCheckpoint(&rawdb.FinalityRecord{StartBlock: 256 * (i + 1), EndBlock: 256*(i+2) - 1, Outcome: OutcomeMatched})
+	}
+
+	checkpoints := NewHistory(db).Checkpoints(0)
+	require.Len(t, checkpoints, historyLimit)
+	require.Equal(t, uint64(256), checkpoints[0].StartBlock)
+}
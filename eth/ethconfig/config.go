@@ -20,6 +20,7 @@ package ethconfig
 import (
 	"errors"
 	"math/big"
+	"strings"
 	"time"
 
 	"github.com/ethereum/go-ethereum/common"
@@ -30,7 +31,11 @@ import (
 	"github.com/ethereum/go-ethereum/consensus/bor/heimdall" //nolint:typecheck
 	"github.com/ethereum/go-ethereum/consensus/bor/heimdall/span"
 	"github.com/ethereum/go-ethereum/consensus/bor/heimdallapp"
+	"github.com/ethereum/go-ethereum/consensus/bor/heimdallarchive"
+	"github.com/ethereum/go-ethereum/consensus/bor/heimdallcache"
 	"github.com/ethereum/go-ethereum/consensus/bor/heimdallgrpc"
+	"github.com/ethereum/go-ethereum/consensus/bor/heimdallmulti"
+	"github.com/ethereum/go-ethereum/consensus/bor/heimdalltelemetry"
 	"github.com/ethereum/go-ethereum/consensus/clique"
 	"github.com/ethereum/go-ethereum/consensus/ethash"
 	"github.com/ethereum/go-ethereum/core"
@@ -186,15 +191,27 @@ type Config struct {
 	// OverrideCancun (TODO: remove after the fork)
 	OverrideCancun *big.Int `toml:",omitempty"`
 
-	// URL to connect to Heimdall node
+	// URL to connect to Heimdall node, comma separated for several nodes
 	HeimdallURL string
 
 	// No heimdall service
 	WithoutHeimdall bool
 
-	// Address to connect to Heimdall gRPC server
+	// Address to connect to Heimdall gRPC server, comma separated for several servers
 	HeimdallgRPCAddress string
 
+	// Number of Heimdall endpoints that must agree on spans and milestones (0 or 1 to disable)
+	HeimdallQuorum int
+
+	// Path of a Heimdall archive to serve Heimdall data from, without connecting to Heimdall
+	HeimdallArchive string
+
+	// Don't cache the immutable Heimdall responses in the chain database
+	HeimdallNoCache bool
+
+	// Number of the latest spans kept in the Heimdall cache (0 = all)
+	HeimdallCacheSpans uint64
+
 	// Run heimdall service as a child process
 	RunHeimdall bool
 
@@ -239,13 +256,30 @@ func CreateConsensusEngine(chainConfig *params.ChainConfig, ethConfig *Config, d
 				log.Warn("Sanitizing DevFakeAuthor", "Use DevFakeAuthor with", "--bor.withoutheimdall")
 			}
 
+			if ethConfig.HeimdallArchive != "" {
+				archiveClient, err := heimdallarchive.NewHeimdallArchiveClient(ethConfig.HeimdallArchive)
+				if err != nil {
+					return nil, err
+				}
+
+				heimdallClient := heimdalltelemetry.NewHeimdallTelemetryClient(archiveClient, "archive", ethConfig.HeimdallArchive)
+
+				return bor.New(chainConfig, db, blockchainAPI, spanner, heimdallClient, genesisContractsClient, false), nil
+			}
+
 			var heimdallClient bor.IHeimdallClient
 			if ethConfig.RunHeimdall && ethConfig.UseHeimdallApp {
-				heimdallClient = heimdallapp.NewHeimdallAppClient()
-			} else if ethConfig.HeimdallgRPCAddress != "" {
-				heimdallClient = heimdallgrpc.NewHeimdallGRPCClient(ethConfig.HeimdallgRPCAddress)
+				heimdallClient = heimdalltelemetry.NewHeimdallTelemetryClient(heimdallapp.NewHeimdallAppClient(), "app", "")
 			} else {
-				heimdallClient = heimdall.NewHeimdallClient(ethConfig.HeimdallURL)
+				var err error
+				if heimdallClient, err = newHeimdallClient(ethConfig); err != nil {
+					return nil, err
+				}
+			}
+
+			// Spans, checkpoints and finalized state-sync events never change, keep them around
+			if !ethConfig.HeimdallNoCache {
+				heimdallClient = heimdallcache.NewHeimdallCacheClient(heimdallClient, db, ethConfig.HeimdallCacheSpans)
 			}
 
 			return bor.New(chainConfig, db, blockchainAPI, spanner, heimdallClient, genesisContractsClient, false), nil
@@ -259,3 +293,51 @@ func CreateConsensusEngine(chainConfig *params.ChainConfig, ethConfig *Config, d
 	}
 	return beacon.New(ethash.NewFaker()), nil
 }
+
+// newHeimdallClient creates the client for the configured Heimdall endpoints,
+// instrumenting each of them. The gRPC endpoints are preferred over the HTTP
+// ones, and a client failing over between them is returned when more than one
+// endpoint is configured. The default HTTP client is returned when none is.
+func newHeimdallClient(ethConfig *Config) (bor.IHeimdallClient, error) {
+	var endpoints []heimdallmulti.Endpoint
+
+	for _, address := range splitEndpoints(ethConfig.HeimdallgRPCAddress) {
+		client := heimdalltelemetry.NewHeimdallTelemetryClient(heimdallgrpc.NewHeimdallGRPCClient(address), "grpc", address)
+		endpoints = append(endpoints, heimdallmulti.Endpoint{Name: address, Client: client})
+	}
+
+	for _, url := range splitEndpoints(ethConfig.HeimdallURL) {
+		client := heimdalltelemetry.NewHeimdallTelemetryClient(heimdall.NewHeimdallClient(url), "http", url)
+		endpoints = append(endpoints, heimdallmulti.Endpoint{Name: url, Client: client})
+	}
+
+	// Without any endpoint configured, keep the default HTTP client
+	if len(endpoints) == 0 {
+		client := heimdall.NewHeimdallClient(ethConfig.HeimdallURL)
+		return heimdalltelemetry.NewHeimdallTelemetryClient(client, "http", ethConfig.HeimdallURL), nil
+	}
+
+	if len(endpoints) == 1 && ethConfig.HeimdallQuorum <= 1 {
+		return endpoints[0].Client, nil
+	}
+
+	client, err := heimdallmulti.NewHeimdallMultiClient(endpoints, ethConfig.HeimdallQuorum)
+	if err != nil {
+		return nil, err
+	}
+
+	return client, nil
+}
+
+// splitEndpoints splits a comma separated list of endpoints.
+func splitEndpoints(list string) []string {
+	var endpoints []string
+
+	for _, endpoint := range strings.Split(list, ",") {
+		if endpoint = strings.TrimSpace(endpoint); endpoint != "" {
+			endpoints = append(endpoints, endpoint)
+		}
+	}
+
+	return endpoints
+}
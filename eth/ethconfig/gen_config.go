@@ -62,6 +62,10 @@ func (c Config) MarshalTOML() (interface{}, error) {
 		HeimdallURL                          string
 		WithoutHeimdall                      bool
 		HeimdallgRPCAddress                  string
+		HeimdallQuorum                       int
+		HeimdallArchive                      string
+		HeimdallNoCache                      bool
+		HeimdallCacheSpans                   uint64
 		RunHeimdall                          bool
 		RunHeimdallArgs                      string
 		UseHeimdallApp                       bool
@@ -115,6 +119,10 @@ func (c Config) MarshalTOML() (interface{}, error) {
 	enc.HeimdallURL = c.HeimdallURL
 	enc.WithoutHeimdall = c.WithoutHeimdall
 	enc.HeimdallgRPCAddress = c.HeimdallgRPCAddress
+	enc.HeimdallQuorum = c.HeimdallQuorum
+	enc.HeimdallArchive = c.HeimdallArchive
+	enc.HeimdallNoCache = c.HeimdallNoCache
+	enc.HeimdallCacheSpans = c.HeimdallCacheSpans
 	enc.RunHeimdall = c.RunHeimdall
 	enc.RunHeimdallArgs = c.RunHeimdallArgs
 	enc.UseHeimdallApp = c.UseHeimdallApp
@@ -172,6 +180,10 @@ func (c *Config) UnmarshalTOML(unmarshal func(interface{}) error) error {
 		HeimdallURL                          *string
 		WithoutHeimdall                      *bool
 		HeimdallgRPCAddress                  *string
+		HeimdallQuorum                       *int
+		HeimdallArchive                      *string
+		HeimdallNoCache                      *bool
+		HeimdallCacheSpans                   *uint64
 		RunHeimdall                          *bool
 		RunHeimdallArgs                      *string
 		UseHeimdallApp                       *bool
@@ -316,6 +328,18 @@ func (c *Config) UnmarshalTOML(unmarshal func(interface{}) error) error {
 	if dec.HeimdallgRPCAddress != nil {
 		c.HeimdallgRPCAddress = *dec.HeimdallgRPCAddress
 	}
+	if dec.HeimdallQuorum != nil {
+		c.HeimdallQuorum = *dec.HeimdallQuorum
+	}
+	if dec.HeimdallArchive != nil {
+		c.HeimdallArchive = *dec.HeimdallArchive
+	}
+	if dec.HeimdallNoCache != nil {
+		c.HeimdallNoCache = *dec.HeimdallNoCache
+	}
+	if dec.HeimdallCacheSpans != nil {
+		c.HeimdallCacheSpans = *dec.HeimdallCacheSpans
+	}
 	if dec.RunHeimdall != nil {
 		c.RunHeimdall = *dec.RunHeimdall
 	}
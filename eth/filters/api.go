@@ -39,6 +39,7 @@ var (
 	errFilterNotFound    = errors.New("filter not found")
 	errInvalidBlockRange = errors.New("invalid block range params")
 	errExceedMaxTopics   = errors.New("exceed max topics")
+	errFinalizedOnly     = errors.New("finalizedOnly is only supported by log subscriptions")
 )
 
 // The maximum number of topic criteria allowed, vm.LOG4 - vm.LOG0
@@ -270,6 +271,10 @@ func (api *FilterAPI) Logs(ctx context.Context, crit FilterCriteria) (*rpc.Subsc
 		return &rpc.Subscription{}, rpc.ErrNotificationsUnsupported
 	}
 
+	if crit.FinalizedOnly {
+		return api.finalizedLogs(notifier, crit)
+	}
+
 	var (
 		rpcSub      = notifier.CreateSubscription()
 		matchedLogs = make(chan []*types.Log)
@@ -317,6 +322,10 @@ type FilterCriteria ethereum.FilterQuery
 //
 // In case "fromBlock" > "toBlock" an error is returned.
 func (api *FilterAPI) NewFilter(crit FilterCriteria) (rpc.ID, error) {
+	if crit.FinalizedOnly {
+		return "", errFinalizedOnly
+	}
+
 	logs := make(chan []*types.Log)
 
 	logsSub, err := api.events.SubscribeLogs(ethereum.FilterQuery(crit), logs)
@@ -356,6 +365,10 @@ func (api *FilterAPI) GetLogs(ctx context.Context, crit FilterCriteria) ([]*type
 		return nil, errExceedMaxTopics
 	}
 
+	if crit.FinalizedOnly {
+		return nil, errFinalizedOnly
+	}
+
 	borConfig := api.chainConfig.Bor
 
 	var filter *Filter
@@ -576,6 +589,8 @@ func (args *FilterCriteria) UnmarshalJSON(data []byte) error {
 		ToBlock   *rpc.BlockNumber `json:"toBlock"`
 		Addresses interface{}      `json:"address"`
 		Topics    []interface{}    `json:"topics"`
+
+		FinalizedOnly bool `json:"finalizedOnly"`
 	}
 
 	var raw input
@@ -600,6 +615,7 @@ func (args *FilterCriteria) UnmarshalJSON(data []byte) error {
 		}
 	}
 
+	args.FinalizedOnly = raw.FinalizedOnly
 	args.Addresses = []common.Address{}
 
 	if raw.Addresses != nil {
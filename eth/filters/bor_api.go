@@ -7,10 +7,17 @@ import (
 	ethereum "github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
 	"github.com/ethereum/go-ethereum/params"
 	"github.com/ethereum/go-ethereum/rpc"
 )
 
+// maxPendingFinalizedLogs is the maximum number of logs a finalized logs
+// subscription holds until finalized, the oldest ones being dropped past it
+const maxPendingFinalizedLogs = 100000
+
+var errFinalizedPending = errors.New("finalizedOnly cannot be used with pending logs")
+
 // SetChainConfig sets chain config
 func (api *FilterAPI) SetChainConfig(chainConfig *params.ChainConfig) {
 	api.chainConfig = chainConfig
@@ -85,3 +92,163 @@ func (api *FilterAPI) NewDeposits(ctx context.Context, crit ethereum.StateSyncFi
 
 	return rpcSub, nil
 }
+
+// FinalizedHeads send a notification each time a block is finalized by a milestone
+// or checkpoint, in the order of the blocks.
+func (api *FilterAPI) FinalizedHeads(ctx context.Context) (*rpc.Subscription, error) {
+	notifier, supported := rpc.NotifierFromContext(ctx)
+	if !supported {
+		return &rpc.Subscription{}, rpc.ErrNotificationsUnsupported
+	}
+
+	rpcSub := notifier.CreateSubscription()
+
+	go func() {
+		headers := make(chan *types.Header)
+		headersSub := api.events.SubscribeFinalizedHeads(headers)
+
+		for {
+			select {
+			case h := <-headers:
+				notifier.Notify(rpcSub.ID, h)
+			case <-rpcSub.Err():
+				headersSub.Unsubscribe()
+				return
+			case <-notifier.Closed():
+				headersSub.Unsubscribe()
+				return
+			}
+		}
+	}()
+
+	return rpcSub, nil
+}
+
+// finalizedLogs creates a logs subscription holding the matched logs until their
+// block is finalized. The logs of the blocks reorged out before are dropped.
+func (api *FilterAPI) finalizedLogs(notifier *rpc.Notifier, crit FilterCriteria) (*rpc.Subscription, error) {
+	if (crit.FromBlock != nil && crit.FromBlock.Int64() == rpc.PendingBlockNumber.Int64()) ||
+		(crit.ToBlock != nil && crit.ToBlock.Int64() == rpc.PendingBlockNumber.Int64()) {
+		return nil, errFinalizedPending
+	}
+
+	var (
+		rpcSub      = notifier.CreateSubscription()
+		matchedLogs = make(chan []*types.Log)
+		headers     = make(chan *types.Header)
+	)
+
+	logsSub, err := api.events.SubscribeLogs(ethereum.FilterQuery(crit), matchedLogs)
+	if err != nil {
+		return nil, err
+	}
+
+	headersSub := api.events.SubscribeFinalizedHeads(headers)
+
+	go func() {
+		defer logsSub.Unsubscribe()
+		defer headersSub.Unsubscribe()
+
+		var pending finalizedLogBuffer
+
+		for {
+			select {
+			case logs := <-matchedLogs:
+				pending.add(logs)
+			case header := <-headers:
+				ctx, cancel := context.WithTimeout(context.Background(), finalityTimeout)
+
+				finalized := pending.finalize(header, func(number uint64) common.Hash {
+					if h, _ := api.sys.backend.HeaderByNumber(ctx, rpc.BlockNumber(number)); h != nil {
+						return h.Hash()
+					}
+
+					return common.Hash{}
+				})
+
+				cancel()
+
+				for _, log := range finalized {
+					log := log
+					notifier.Notify(rpcSub.ID, &log)
+				}
+			case <-rpcSub.Err(): // client send an unsubscribe request
+				return
+			case <-notifier.Closed(): // connection dropped
+				return
+			}
+		}
+	}()
+
+	return rpcSub, nil
+}
+
+// finalizedLogBuffer holds the logs of a subscription until finalized.
+type finalizedLogBuffer struct {
+	logs []*types.Log
+}
+
+// add buffers new logs, discarding the buffered logs of the removed blocks.
+func (b *finalizedLogBuffer) add(logs []*types.Log) {
+	removed := make(map[common.Hash]struct{})
+
+	for _, log := range logs {
+		if log.Removed {
+			removed[log.BlockHash] = struct{}{}
+		} else {
+			b.logs = append(b.logs, log)
+		}
+	}
+
+	if len(removed) > 0 {
+		kept := b.logs[:0]
+
+		for _, log := range b.logs {
+			if _, ok := removed[log.BlockHash]; !ok {
+				kept = append(kept, log)
+			}
+		}
+
+		b.logs = kept
+	}
+
+	if dropped := len(b.logs) - maxPendingFinalizedLogs; dropped > 0 {
+		log.Warn("Dropping logs waiting for finality", "count", dropped)
+
+		b.logs = append([]*types.Log(nil), b.logs[dropped:]...)
+	}
+}
+
+// finalize returns the buffered logs of the canonical blocks up to the given
+// finalized one, and discards the logs of the other blocks up to it. The hash
+// of the canonical blocks below the finalized one is resolved by canonical.
+func (b *finalizedLogBuffer) finalize(header *types.Header, canonical func(uint64) common.Hash) []*types.Log {
+	var (
+		number = header.Number.Uint64()
+		hashes = map[uint64]common.Hash{number: header.Hash()}
+
+		finalized []*types.Log
+		kept      = b.logs[:0]
+	)
+
+	for _, log := range b.logs {
+		if log.BlockNumber > number {
+			kept = append(kept, log)
+			continue
+		}
+
+		hash, ok := hashes[log.BlockNumber]
+		if !ok {
+			hash = canonical(log.BlockNumber)
+			hashes[log.BlockNumber] = hash
+		}
+
+		if hash == log.BlockHash {
+			finalized = append(finalized, log)
+		}
+	}
+
+	b.logs = kept
+
+	return finalized
+}
@@ -1,13 +1,24 @@
 package filters
 
 import (
+	"context"
 	"time"
 
 	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
 	"github.com/ethereum/go-ethereum/rpc"
 )
 
+const (
+	// maxFinalizedHeads is the maximum number of headers sent when the finality
+	// advances, only the latest ones being sent past it
+	maxFinalizedHeads = 1024
+
+	// finalityTimeout bounds the resolution of the finalized headers
+	finalityTimeout = 5 * time.Second
+)
+
 func (es *EventSystem) handleStateSyncEvent(filters filterIndex, ev core.StateSyncEvent) {
 	for _, f := range filters[StateSyncSubscription] {
 		f.stateSyncData <- ev.Data
@@ -30,3 +41,112 @@ func (es *EventSystem) SubscribeNewDeposits(data chan *types.StateSyncData) *Sub
 
 	return es.subscribe(sub)
 }
+
+// SubscribeFinalizedHeads creates a subscription that writes the headers of the
+// blocks finalized by a milestone or checkpoint, in order.
+func (es *EventSystem) SubscribeFinalizedHeads(headers chan *types.Header) *Subscription {
+	sub := &subscription{
+		id:        rpc.NewID(),
+		typ:       FinalizedHeadsSubscription,
+		created:   time.Now(),
+		logs:      make(chan []*types.Log),
+		txs:       make(chan []*types.Transaction),
+		headers:   headers,
+		installed: make(chan struct{}),
+		err:       make(chan error),
+	}
+
+	return es.subscribe(sub)
+}
+
+// notifyFinality notifies the finality loop of a chain event, without waiting
+// for it to be done with the previous one.
+func (es *EventSystem) notifyFinality() {
+	select {
+	case es.finalityCh <- struct{}{}:
+	default:
+	}
+}
+
+// handleFinalizedHeads sends the headers of the newly finalized blocks to the
+// finalized heads subscriptions.
+func (es *EventSystem) handleFinalizedHeads(filters filterIndex, headers []*types.Header) {
+	for _, header := range headers {
+		for _, f := range filters[FinalizedHeadsSubscription] {
+			f.headers <- header
+		}
+	}
+}
+
+// finalityLoop resolves the headers of the blocks finalized since the last
+// chain event, handing them to the event loop. The finality is only checked on
+// chain events, which are frequent enough to notice the new milestones in time,
+// and outside the event loop not to hold the other subscriptions meanwhile.
+func (es *EventSystem) finalityLoop() {
+	var lastFinalized uint64 // Number of the last block sent as finalized
+
+	for {
+		select {
+		case <-es.finalityCh:
+			if !es.finalityWatched.Load() {
+				// Start over from the finalized head once subscribed again
+				lastFinalized = 0
+				continue
+			}
+
+			headers := es.finalizedHeaders(&lastFinalized)
+			if len(headers) == 0 {
+				continue
+			}
+
+			select {
+			case es.finalizedCh <- headers:
+			case <-es.finalityQuit:
+				return
+			}
+
+		case <-es.finalityQuit:
+			return
+		}
+	}
+}
+
+// finalizedHeaders returns the headers of the blocks finalized after the given
+// last finalized one, updating it.
+func (es *EventSystem) finalizedHeaders(lastFinalized *uint64) []*types.Header {
+	ctx, cancel := context.WithTimeout(context.Background(), finalityTimeout)
+	defer cancel()
+
+	finalized, err := es.backend.HeaderByNumber(ctx, rpc.FinalizedBlockNumber)
+	if err != nil || finalized == nil {
+		return nil
+	}
+
+	number := finalized.Number.Uint64()
+	if number <= *lastFinalized {
+		return nil
+	}
+
+	from := *lastFinalized + 1
+	if *lastFinalized == 0 {
+		from = number
+	}
+
+	if number-from >= maxFinalizedHeads {
+		log.Debug("Skipping finalized headers", "from", from, "to", number-maxFinalizedHeads)
+
+		from = number - maxFinalizedHeads + 1
+	}
+
+	*lastFinalized = number
+
+	headers := make([]*types.Header, 0, number-from+1)
+
+	for n := from; n < number; n++ {
+		if header, err := es.backend.HeaderByNumber(ctx, rpc.BlockNumber(n)); err == nil && header != nil {
+			headers = append(headers, header)
+		}
+	}
+
+	return append(headers, finalized)
+}
@@ -0,0 +1,108 @@
+package filters
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/ethash"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+func TestFinalizedHeadsSubscription(t *testing.T) {
+	t.Parallel()
+
+	var (
+		db           = rawdb.NewMemoryDatabase()
+		backend, sys = newTestFilterSystem(t, db, Config{})
+		api          = NewFilterAPI(sys, false, true)
+		genesis      = &core.Genesis{
+			Config:  params.TestChainConfig,
+			BaseFee: big.NewInt(params.InitialBaseFee),
+		}
+		_, chain, _ = core.GenerateChainWithGenesis(genesis, ethash.NewFaker(), 10, func(i int, gen *core.BlockGen) {})
+	)
+
+	for _, block := range chain {
+		rawdb.WriteHeader(db, block.Header())
+		rawdb.WriteCanonicalHash(db, block.Hash(), block.NumberU64())
+	}
+
+	headers := make(chan *types.Header)
+	sub := api.events.SubscribeFinalizedHeads(headers)
+
+	defer sub.Unsubscribe()
+
+	finalize := func(number int) {
+		rawdb.WriteFinalizedBlockHash(db, chain[number-1].Hash())
+		backend.chainFeed.Send(core.ChainEvent{Hash: chain[number-1].Hash(), Block: chain[number-1]})
+	}
+
+	expect := func(numbers ...uint64) {
+		t.Helper()
+
+		for _, number := range numbers {
+			select {
+			case header := <-headers:
+				require.Equal(t, number, header.Number.Uint64())
+			case <-time.After(time.Second):
+				t.Fatalf("finalized header %d not received", number)
+			}
+		}
+	}
+
+	// The first finalized block only is sent, then all the blocks finalized
+	finalize(3)
+	expect(3)
+
+	finalize(3)
+	finalize(6)
+	expect(4, 5, 6)
+}
+
+func TestFinalizedLogBuffer(t *testing.T) {
+	t.Parallel()
+
+	var (
+		first     = &types.Header{Number: big.NewInt(1)}
+		last      = &types.Header{Number: big.NewInt(3)}
+		canonical = map[uint64]common.Hash{1: first.Hash(), 2: common.HexToHash("0x2"), 3: last.Hash()}
+		reorged   = common.HexToHash("0x2b")
+		buffer    finalizedLogBuffer
+	)
+
+	buffer.add([]*types.Log{
+		{BlockNumber: 1, BlockHash: canonical[1], Index: 0},
+		{BlockNumber: 2, BlockHash: reorged, Index: 1},
+	})
+
+	// The reorged block is removed and replaced by the canonical one
+	buffer.add([]*types.Log{{BlockNumber: 2, BlockHash: reorged, Removed: true}})
+	buffer.add([]*types.Log{
+		{BlockNumber: 2, BlockHash: canonical[2], Index: 2},
+		{BlockNumber: 3, BlockHash: canonical[3], Index: 3},
+	})
+
+	resolve := func(number uint64) common.Hash {
+		return canonical[number]
+	}
+
+	finalized := buffer.finalize(first, resolve)
+	require.Len(t, finalized, 1)
+	require.Equal(t, uint(0), finalized[0].Index)
+
+	// A log missed by the reorg notification is dropped once finalized
+	buffer.add([]*types.Log{{BlockNumber: 3, BlockHash: common.HexToHash("0x3b"), Index: 4}})
+
+	finalized = buffer.finalize(last, resolve)
+	require.Len(t, finalized, 2)
+	require.Equal(t, uint(2), finalized[0].Index)
+	require.Equal(t, uint(3), finalized[1].Index)
+	require.Empty(t, buffer.logs)
+}
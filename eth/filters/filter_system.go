@@ -179,6 +179,8 @@ const (
 	BlocksSubscription
 	// StateSyncSubscription to listen main chain state
 	StateSyncSubscription
+	// FinalizedHeadsSubscription queries headers for blocks that are finalized
+	FinalizedHeadsSubscription
 	// LastIndexSubscription keeps track of the last index
 	LastIndexSubscription
 )
@@ -238,6 +240,11 @@ type EventSystem struct {
 	// Bor related subscription and channels
 	stateSyncSub event.Subscription       // Subscription for new state event
 	stateSyncCh  chan core.StateSyncEvent // Channel to receive deposit state change event
+
+	finalityCh      chan struct{}        // Channel to notify the finality loop of the chain events
+	finalizedCh     chan []*types.Header // Channel to receive the headers of the newly finalized blocks
+	finalityWatched atomic.Bool          // Whether there are finalized heads subscriptions
+	finalityQuit    chan struct{}        // Channel closed when the event loop stops
 }
 
 // NewEventSystem creates a new manager that listens for event on the given mux,
@@ -259,6 +266,9 @@ func NewEventSystem(sys *FilterSystem, lightMode bool) *EventSystem {
 		pendingLogsCh: make(chan []*types.Log, logsChanSize),
 		chainCh:       make(chan core.ChainEvent, chainEvChanSize),
 		stateSyncCh:   make(chan core.StateSyncEvent, stateEvChanSize),
+		finalityCh:    make(chan struct{}, 1),
+		finalizedCh:   make(chan []*types.Header),
+		finalityQuit:  make(chan struct{}),
 	}
 
 	// Subscribe events
@@ -275,6 +285,7 @@ func NewEventSystem(sys *FilterSystem, lightMode bool) *EventSystem {
 	}
 
 	go m.eventLoop()
+	go m.finalityLoop()
 
 	return m
 }
@@ -604,6 +615,7 @@ func (es *EventSystem) eventLoop() {
 		es.pendingLogsSub.Unsubscribe()
 		es.chainSub.Unsubscribe()
 		es.stateSyncSub.Unsubscribe()
+		close(es.finalityQuit)
 	}()
 
 	index := make(filterIndex)
@@ -623,8 +635,11 @@ func (es *EventSystem) eventLoop() {
 			es.handlePendingLogs(index, ev)
 		case ev := <-es.chainCh:
 			es.handleChainEvent(index, ev)
+			es.notifyFinality()
 		case ev := <-es.stateSyncCh:
 			es.handleStateSyncEvent(index, ev)
+		case headers := <-es.finalizedCh:
+			es.handleFinalizedHeads(index, headers)
 
 		case f := <-es.install:
 			if f.typ == MinedAndPendingLogsSubscription {
@@ -635,6 +650,7 @@ func (es *EventSystem) eventLoop() {
 				index[f.typ][f.id] = f
 			}
 
+			es.finalityWatched.Store(len(index[FinalizedHeadsSubscription]) > 0)
 			close(f.installed)
 
 		case f := <-es.uninstall:
@@ -646,6 +662,7 @@ func (es *EventSystem) eventLoop() {
 				delete(index[f.typ], f.id)
 			}
 
+			es.finalityWatched.Store(len(index[FinalizedHeadsSubscription]) > 0)
 			close(f.err)
 
 		// System stopped
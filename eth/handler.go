@@ -34,6 +34,7 @@ import (
 	"github.com/ethereum/go-ethereum/core/txpool"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/eth/downloader"
+	"github.com/ethereum/go-ethereum/eth/downloader/whitelist"
 	"github.com/ethereum/go-ethereum/eth/fetcher"
 	"github.com/ethereum/go-ethereum/eth/protocols/eth"
 	"github.com/ethereum/go-ethereum/eth/protocols/snap"
@@ -96,6 +97,7 @@ type handlerConfig struct {
 	EventMux            *event.TypeMux      // Legacy event mux, deprecate for `feed`
 	txArrivalWait       time.Duration       // Maximum duration to wait for an announced tx before requesting it
 	checker             ethereum.ChainValidator
+	finalityHistory     *whitelist.History     // History of the milestones and checkpoints processed
 	RequiredBlocks      map[uint64]common.Hash // Hard coded map of required block hashes for sync challenges
 	EthAPI              *ethapi.BlockChainAPI  // EthAPI to interact
 	enableBlockTracking bool                   // Whether to log information collected while tracking block lifecycle
@@ -121,6 +123,8 @@ type handler struct {
 
 	ethAPI *ethapi.BlockChainAPI // EthAPI to interact
 
+	finalityHistory *whitelist.History // History of the milestones and checkpoints processed
+
 	eventMux      *event.TypeMux
 	txsCh         chan core.NewTxsEvent
 	txsSub        event.Subscription
@@ -157,6 +161,7 @@ func newHandler(config *handlerConfig) (*handler, error) {
 		peers:               newPeerSet(),
 		merger:              config.Merger,
 		ethAPI:              config.EthAPI,
+		finalityHistory:     config.finalityHistory,
 		requiredBlocks:      config.RequiredBlocks,
 		enableBlockTracking: config.enableBlockTracking,
 		quitSync:            make(chan struct{}),
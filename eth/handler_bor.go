@@ -3,10 +3,13 @@ package eth
 import (
 	"context"
 	"errors"
+	"time"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/consensus/bor"
 	"github.com/ethereum/go-ethereum/consensus/bor/heimdall"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/eth/downloader/whitelist"
 	"github.com/ethereum/go-ethereum/log"
 )
 
@@ -39,10 +42,15 @@ func (h *ethHandler) fetchWhitelistCheckpoint(ctx context.Context, bor *bor.Bor,
 
 	log.Info("Got new checkpoint from heimdall", "start", checkpoint.StartBlock.Uint64(), "end", checkpoint.EndBlock.Uint64(), "rootHash", checkpoint.RootHash.String())
 
+	record := h.newFinalityRecord(checkpoint.StartBlock.Uint64(), checkpoint.EndBlock.Uint64(), checkpoint.RootHash, checkpoint.Proposer, checkpoint.Timestamp)
+
 	// Verify if the checkpoint fetched can be added to the local whitelist entry or not
 	// If verified, it returns the hash of the end block of the checkpoint. If not,
 	// it will return appropriate error.
 	hash, err := verifier.verify(ctx, eth, h, checkpoint.StartBlock.Uint64(), checkpoint.EndBlock.Uint64(), checkpoint.RootHash.String()[2:], true)
+
+	h.recordFinality(record, true, err)
+
 	if err != nil {
 		log.Warn("Failed to whitelist checkpoint", "err", err)
 		return blockNum, blockHash, err
@@ -79,10 +87,15 @@ func (h *ethHandler) fetchWhitelistMilestone(ctx context.Context, bor *bor.Bor,
 
 	log.Info("Got new milestone from heimdall", "start", milestone.StartBlock.Uint64(), "end", milestone.EndBlock.Uint64(), "hash", milestone.Hash.String())
 
+	record := h.newFinalityRecord(milestone.StartBlock.Uint64(), milestone.EndBlock.Uint64(), milestone.Hash, milestone.Proposer, milestone.Timestamp)
+
 	// Verify if the milestone fetched can be added to the local whitelist entry or not
 	// If verified, it returns the hash of the end block of the milestone. If not,
 	// it will return appropriate error.
 	_, err = verifier.verify(ctx, eth, h, milestone.StartBlock.Uint64(), milestone.EndBlock.Uint64(), milestone.Hash.String()[2:], false)
+
+	h.recordFinality(record, false, err)
+
 	if err != nil {
 		h.downloader.UnlockSprint(milestone.EndBlock.Uint64())
 		return num, hash, err
@@ -91,6 +104,57 @@ func (h *ethHandler) fetchWhitelistMilestone(ctx context.Context, bor *bor.Bor,
 	return num, hash, nil
 }
 
+// newFinalityRecord starts the record of the verification of a milestone or
+// checkpoint, if the history is kept.
+func (h *ethHandler) newFinalityRecord(start uint64, end uint64, hash common.Hash, proposer common.Address, timestamp uint64) *rawdb.FinalityRecord {
+	if h.finalityHistory == nil {
+		return nil
+	}
+
+	return &rawdb.FinalityRecord{
+		StartBlock: start,
+		EndBlock:   end,
+		Hash:       hash,
+		Proposer:   proposer,
+		Timestamp:  timestamp,
+		Head:       h.chain.CurrentBlock().Number.Uint64(),
+		FirstSeen:  time.Now(),
+	}
+}
+
+// recordFinality completes the record of a verification with its outcome and
+// adds it to the history.
+func (h *ethHandler) recordFinality(record *rawdb.FinalityRecord, isCheckpoint bool, err error) {
+	if record == nil {
+		return
+	}
+
+	record.LastSeen = time.Now()
+	record.VerifyDuration = record.LastSeen.Sub(record.FirstSeen)
+
+	switch {
+	case err == nil:
+		record.Outcome = whitelist.OutcomeMatched
+	case errors.Is(err, errHashMismatch):
+		record.Outcome = whitelist.OutcomeRewound
+		record.RewindTo = h.chain.CurrentBlock().Number.Uint64()
+	case errors.Is(err, errMissingBlocks):
+		record.Outcome = whitelist.OutcomeFuture
+	default:
+		record.Outcome = whitelist.OutcomeRejected
+	}
+
+	if err != nil {
+		record.Error = err.Error()
+	}
+
+	if isCheckpoint {
+		h.finalityHistory.RecordCheckpoint(record)
+	} else {
+		h.finalityHistory.RecordMilestone(record)
+	}
+}
+
 func (h *ethHandler) fetchNoAckMilestone(ctx context.Context, bor *bor.Bor) (string, error) {
 	var (
 		milestoneID string
@@ -152,7 +152,7 @@ func (eth *Ethereum) hashState(ctx context.Context, block *types.Block, reexec u
 		}
 
 		// nolint : contextcheck
-		_, _, _, err := eth.blockchain.Processor().Process(current, statedb, vm.Config{}, nil)
+		_, _, _, err := eth.blockchain.ReplayProcessor(current).Process(current, statedb, vm.Config{}, nil)
 
 		if err != nil {
 			return nil, nil, fmt.Errorf("processing block %d failed: %v", current.NumberU64(), err)
@@ -741,8 +741,8 @@ func (api *API) StandardTraceBadBlockToFile(ctx context.Context, hash common.Has
 // traceBlock configures a new tracer according to the provided configuration, and
 // executes all the transactions contained within. The return value will be one item
 // per transaction, dependent on the requested tracer.
-// We always run parallel execution
-// One thread runs along and executes txs without tracing enabled to generate their prestate.
+// The transactions are traced with Block-STM when their dependencies are known.
+// Otherwise one thread runs along and executes txs without tracing enabled to generate their prestate.
 // Worker threads take the tasks and the prestate and trace them.
 func (api *API) traceBlock(ctx context.Context, block *types.Block, config *TraceConfig) ([]*txTraceResult, error) {
 	if config == nil {
@@ -787,6 +787,20 @@ func (api *API) traceBlock(ctx context.Context, block *types.Block, config *Trac
 
 	defer release()
 
+	// Trace the transactions concurrently with Block-STM when their dependencies
+	// are known, falling back to the serial replay of the state otherwise
+	if !ioflag {
+		if txDependency := core.ReadTxDependency(api.backend.ChainDb(), block); txDependency != nil {
+			results, err := api.traceBlockParallel(ctx, block, statedb, txDependency, config)
+			if !errors.Is(err, errParallelTraceFallback) {
+				parallelTraceMeter.Mark(1)
+				return results, err
+			}
+
+			parallelTraceFallbackMeter.Mark(1)
+		}
+	}
+
 	// create and add empty mvHashMap in statedb as StateAtBlock does not have mvHashmap in it.
 	if ioflag {
 		statedb.AddEmptyMVHashMap()
@@ -1300,22 +1314,13 @@ func (api *API) traceTx(ctx context.Context, message *core.Message, txctx *Conte
 	}
 
 	var (
-		tracer    Tracer
-		err       error
 		timeout   = defaultTraceTimeout
 		txContext = core.NewEVMTxContext(message)
 	)
 
-	if config == nil {
-		config = &TraceConfig{}
-	}
-	// Default tracer is the struct logger
-	tracer = logger.NewStructLogger(config.Config)
-	if config.Tracer != nil {
-		tracer, err = DefaultDirectory.New(*config.Tracer, txctx, config.TracerConfig)
-		if err != nil {
-			return nil, err
-		}
+	tracer, err := newTracer(txctx, config)
+	if err != nil {
+		return nil, err
 	}
 
 	vmenv := vm.NewEVM(vmctx, txContext, statedb, api.backend.ChainConfig(), vm.Config{Tracer: tracer, NoBaseFee: true})
@@ -1364,6 +1369,16 @@ func (api *API) traceTx(ctx context.Context, message *core.Message, txctx *Conte
 	return tracer.GetResult()
 }
 
+// newTracer creates the tracer of the given configuration, defaulting to the
+// struct logger.
+func newTracer(txctx *Context, config *TraceConfig) (Tracer, error) {
+	if config.Tracer != nil {
+		return DefaultDirectory.New(*config.Tracer, txctx, config.TracerConfig)
+	}
+
+	return logger.NewStructLogger(config.Config), nil
+}
+
 // APIs return the collection of RPC services the tracer package offers.
 func APIs(backend Backend) []rpc.API {
 	// Append all the local APIs and return
@@ -0,0 +1,151 @@
+package tracers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime"
+	"time"
+
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
+)
+
+// errParallelTraceFallback is returned when a block can't be traced in parallel,
+// its transactions being traced serially instead.
+var errParallelTraceFallback = errors.New("block not traceable in parallel")
+
+var (
+	parallelTraceMeter         = metrics.NewRegisteredMeter("tracers/block/parallel", nil)
+	parallelTraceFallbackMeter = metrics.NewRegisteredMeter("tracers/block/parallel/fallback", nil)
+)
+
+// traceBlockParallel traces the transactions of a block concurrently with
+// core.ExecuteMessages, following the given dependencies between them, each
+// execution running with a fresh tracer on the versioned state. The state sync
+// transaction, if any and enabled, is traced last on the final state. It
+// returns errParallelTraceFallback if the block has to be traced serially.
+func (api *API) traceBlockParallel(ctx context.Context, block *types.Block, statedb *state.StateDB, txDependency [][]uint64, config *TraceConfig) ([]*txTraceResult, error) {
+	var (
+		txs, stateSyncPresent, stateSyncHash = api.getAllBlockTransactions(ctx, block)
+		chainConfig                          = api.backend.ChainConfig()
+		blockCtx                             = core.NewEVMBlockContext(block.Header(), api.chainContext(ctx), nil)
+		signer                               = types.MakeSigner(chainConfig, block.Number(), block.Time())
+		deps                                 = core.GetDeps(txDependency)
+		finalStateDB                         = statedb.Copy()
+		msgs                                 = make([]core.ParallelMessage, 0, len(block.Transactions()))
+	)
+
+	// Validate the tracer and timeout once, rather than failing each execution
+	if _, err := newTracer(&Context{BlockHash: block.Hash(), BlockNumber: block.Number()}, config); err != nil {
+		return nil, err
+	}
+
+	timeout := defaultTraceTimeout
+	if config.Timeout != nil {
+		var err error
+		if timeout, err = time.ParseDuration(*config.Timeout); err != nil {
+			return nil, err
+		}
+	}
+
+	for i, tx := range block.Transactions() {
+		msg, err := core.TransactionToMessage(tx, signer, block.BaseFee())
+		if err != nil {
+			return nil, fmt.Errorf("could not trace tx %d [%v]: %w", i, tx.Hash().Hex(), err)
+		}
+
+		txctx := &Context{
+			BlockHash:   block.Hash(),
+			BlockNumber: block.Number(),
+			TxIndex:     i,
+			TxHash:      tx.Hash(),
+		}
+
+		msgs = append(msgs, core.ParallelMessage{
+			Msg:          msg,
+			TxHash:       tx.Hash(),
+			Dependencies: deps[i],
+			NewTracer: func(evm *vm.EVM) (vm.EVMLogger, func(), error) {
+				tracer, err := newTracer(txctx, config)
+				if err != nil {
+					return nil, nil, err
+				}
+
+				deadlineCtx, cancel := context.WithTimeout(ctx, timeout)
+
+				go func() {
+					<-deadlineCtx.Done()
+
+					if errors.Is(deadlineCtx.Err(), context.DeadlineExceeded) {
+						tracer.Stop(errors.New("execution timeout"))
+						// Stop evm execution. Note cancellation is not necessarily immediate.
+						evm.Cancel()
+					}
+				}()
+
+				return tracer, cancel, nil
+			},
+		})
+	}
+
+	result, err := core.ExecuteMessages(ctx, chainConfig, blockCtx, finalStateDB, msgs, runtime.NumCPU(), vm.Config{NoBaseFee: true})
+	if err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, ctxErr
+		}
+
+		log.Debug("Parallel tracing failed", "number", block.Number(), "hash", block.Hash(), "err", err)
+
+		return nil, errParallelTraceFallback
+	}
+
+	// The fees paid by the transactions couldn't be delayed
+	if !result.Parallel {
+		return nil, errParallelTraceFallback
+	}
+
+	results := make([]*txTraceResult, 0, len(txs))
+
+	for i, res := range result.Results {
+		txHash := msgs[i].TxHash
+
+		if res.Err != nil {
+			results = append(results, &txTraceResult{TxHash: txHash, Error: fmt.Errorf("tracing failed: %w", res.Err).Error()})
+			continue
+		}
+
+		trace, err := res.Tracer.(Tracer).GetResult()
+		if err != nil {
+			results = append(results, &txTraceResult{TxHash: txHash, Error: err.Error()})
+			continue
+		}
+
+		results = append(results, &txTraceResult{TxHash: txHash, Result: trace})
+	}
+
+	if stateSyncPresent && *config.BorTraceEnabled {
+		msg, _ := core.TransactionToMessage(txs[len(txs)-1], signer, block.BaseFee())
+		txctx := &Context{
+			BlockHash:   block.Hash(),
+			BlockNumber: block.Number(),
+			TxIndex:     len(txs) - 1,
+			TxHash:      stateSyncHash,
+		}
+
+		config.BorTx = newBoolPtr(true)
+
+		res, err := api.traceTx(ctx, msg, txctx, blockCtx, finalStateDB, config)
+		if err != nil {
+			results = append(results, &txTraceResult{TxHash: stateSyncHash, Error: err.Error()})
+		} else {
+			results = append(results, &txTraceResult{TxHash: stateSyncHash, Result: res})
+		}
+	}
+
+	return results, nil
+}
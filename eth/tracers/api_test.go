@@ -1052,3 +1052,86 @@ func TestTraceChain(t *testing.T) {
 		}
 	}
 }
+
+func TestTraceBlockParallel(t *testing.T) {
+	t.Parallel()
+
+	// Initialize test accounts and a counter contract
+	accounts := newAccounts(4)
+	counter := common.HexToAddress("0x00000000000000000000000000000000deadbeef")
+	genesis := &core.Genesis{Alloc: core.GenesisAlloc{
+		accounts[0].addr: {Balance: big.NewInt(params.Ether)},
+		accounts[1].addr: {Balance: big.NewInt(params.Ether)},
+		accounts[2].addr: {Balance: big.NewInt(params.Ether)},
+		accounts[3].addr: {Balance: big.NewInt(params.Ether)},
+		// PUSH1 1, SLOAD, PUSH1 1, ADD, PUSH1 1, SSTORE, STOP
+		counter: {Balance: common.Big0, Code: common.FromHex("0x600154600101600155")},
+	}}
+	genBlocks := 2
+	signer := types.HomesteadSigner{}
+	backend := newTestBackend(t, genBlocks, genesis, func(i int, b *core.BlockGen) {
+		// account[0] and account[2] increment the counter, the latter after
+		// receiving a transfer from account[0]. Its dependencies are left out
+		// for its re-execution to be checked.
+		for _, tx := range []struct {
+			from int
+			to   common.Address
+		}{
+			{0, counter},
+			{1, accounts[3].addr},
+			{0, accounts[2].addr},
+			{2, counter},
+		} {
+			nonce := b.TxNonce(accounts[tx.from].addr)
+			signed, _ := types.SignTx(types.NewTransaction(nonce, tx.to, big.NewInt(1000), 100000, b.BaseFee(), nil), signer, accounts[tx.from].key)
+			b.AddTx(signed)
+		}
+	})
+
+	defer backend.chain.Stop()
+	api := NewAPI(backend)
+
+	for _, config := range []*TraceConfig{nil, {Config: &logger.Config{EnableMemory: true, EnableReturnData: true}}} {
+		for number := 1; number <= genBlocks; number++ {
+			block := backend.chain.GetBlockByNumber(uint64(number))
+			rawdb.DeleteTxDependency(backend.chaindb, block.Hash(), block.NumberU64())
+
+			serial, err := api.TraceBlockByNumber(context.Background(), rpc.BlockNumber(number), config)
+			if err != nil {
+				t.Fatalf("block %d: failed to trace serially: %v", number, err)
+			}
+
+			rawdb.WriteTxDependency(backend.chaindb, block.Hash(), block.NumberU64(), [][]uint64{{}, {}, {0}, {}})
+
+			parallel, err := api.TraceBlockByNumber(context.Background(), rpc.BlockNumber(number), config)
+			if err != nil {
+				t.Fatalf("block %d: failed to trace in parallel: %v", number, err)
+			}
+
+			have, _ := json.Marshal(parallel)
+			want, _ := json.Marshal(serial)
+
+			if string(have) != string(want) {
+				t.Fatalf("block %d: result mismatch, have\n%v\n, want\n%v\n", number, string(have), string(want))
+			}
+
+			// Check the block was traced in parallel rather than falling back
+			parent := backend.chain.GetBlockByNumber(uint64(number - 1))
+			statedb, release, err := backend.StateAtBlock(context.Background(), parent, defaultTraceReexec, nil, true, false)
+			if err != nil {
+				t.Fatalf("block %d: failed to get state: %v", number, err)
+			}
+
+			traceConfig := config
+			if traceConfig == nil {
+				traceConfig = &TraceConfig{BorTraceEnabled: defaultBorTraceEnabled}
+			}
+
+			if _, err := api.traceBlockParallel(context.Background(), block, statedb, core.ReadTxDependency(backend.chaindb, block), traceConfig); err != nil {
+				t.Fatalf("block %d: failed to trace in parallel: %v", number, err)
+			}
+
+			release()
+		}
+	}
+}
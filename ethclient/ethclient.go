@@ -504,6 +504,10 @@ func toFilterArg(q ethereum.FilterQuery) (interface{}, error) {
 		arg["toBlock"] = toBlockNumArg(q.ToBlock)
 	}
 
+	if q.FinalizedOnly {
+		arg["finalizedOnly"] = true
+	}
+
 	return arg, nil
 }
 
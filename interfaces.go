@@ -171,6 +171,10 @@ type FilterQuery struct {
 	// {{A}, {B}}         matches topic A in first position AND B in second position
 	// {{A, B}, {C, D}}   matches topic (A OR B) in first position AND (C OR D) in second position
 	Topics [][]common.Hash
+
+	// FinalizedOnly delays the logs of a subscription until their block is
+	// finalized, never delivering removed logs. It is not supported by queries.
+	FinalizedOnly bool
 }
 
 // LogFilterer provides access to contract log events using a one-off query or continuous
@@ -103,6 +103,11 @@ func Commands() map[string]MarkDownCommandFactory {
 				Meta2: meta2,
 			}, nil
 		},
+		"debug parallel-stats": func() (MarkDownCommand, error) {
+			return &DebugParallelStatsCommand{
+				Meta2: meta2,
+			}, nil
+		},
 		"chain": func() (MarkDownCommand, error) {
 			return &ChainCommand{
 				UI: ui,
@@ -118,6 +123,16 @@ func Commands() map[string]MarkDownCommandFactory {
 				Meta2: meta2,
 			}, nil
 		},
+		"heimdall": func() (MarkDownCommand, error) {
+			return &HeimdallCommand{
+				UI: ui,
+			}, nil
+		},
+		"heimdall export": func() (MarkDownCommand, error) {
+			return &HeimdallExportCommand{
+				UI: ui,
+			}, nil
+		},
 		"account": func() (MarkDownCommand, error) {
 			return &Account{
 				UI: ui,
@@ -200,6 +215,16 @@ func Commands() map[string]MarkDownCommandFactory {
 				Meta: meta,
 			}, nil
 		},
+		"snapshot bor-export": func() (MarkDownCommand, error) {
+			return &SnapshotBorExportCommand{
+				Meta: meta,
+			}, nil
+		},
+		"snapshot bor-import": func() (MarkDownCommand, error) {
+			return &SnapshotBorImportCommand{
+				Meta: meta,
+			}, nil
+		},
 	}
 }
 
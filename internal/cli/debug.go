@@ -50,6 +50,7 @@ func (d *DebugCommand) MarkDown() string {
 		"The ```bor debug``` command takes a debug dump of the running client.",
 		"- [```bor debug pprof```](./debug_pprof.md): Dumps bor pprof traces.",
 		"- [```bor debug block <number>```](./debug_block.md): Dumps bor block traces.",
+		"- [```bor debug parallel-stats [number]```](./debug_parallel-stats.md): Reports the parallel execution of a block.",
 	}
 	items = append(items, examples...)
 
@@ -68,7 +69,11 @@ func (c *DebugCommand) Help() string {
 
 	Get the block traces:
 
-		$ bor debug block <number>`
+		$ bor debug block <number>
+
+	Report the parallel execution of a block:
+
+		$ bor debug parallel-stats [number]`
 }
 
 // Synopsis implements the cli.Command interface
@@ -0,0 +1,110 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/internal/cli/flagset"
+	"github.com/ethereum/go-ethereum/internal/cli/server/proto"
+)
+
+// DebugParallelStatsCommand is the command to report the parallel execution of a block
+type DebugParallelStatsCommand struct {
+	*Meta2
+}
+
+// MarkDown implements cli.MarkDown interface
+func (c *DebugParallelStatsCommand) MarkDown() string {
+	items := []string{
+		"# Debug parallel-stats",
+		"The ```bor debug parallel-stats [number]``` command executes a block with Block-STM and reports its re-executions, longest chain of dependent transactions, use of the speculative processes and estimated speedup against the serial execution. It defaults to the latest block.",
+		c.Flags().MarkDown(),
+	}
+
+	return strings.Join(items, "\n\n")
+}
+
+// Help implements the cli.Command interface
+func (c *DebugParallelStatsCommand) Help() string {
+	return `Usage: bor debug parallel-stats [number]
+
+  This command reports the parallel execution of a block` + c.Flags().Help()
+}
+
+// Flags: address
+func (c *DebugParallelStatsCommand) Flags() *flagset.Flagset {
+	return c.NewFlagSet("parallel-stats")
+}
+
+// Synopsis implements the cli.Command interface
+func (c *DebugParallelStatsCommand) Synopsis() string {
+	return "Report the parallel execution of a block"
+}
+
+// Run implements the cli.Command interface
+func (c *DebugParallelStatsCommand) Run(args []string) int {
+	flags := c.Flags()
+
+	req := &proto.DebugParallelStatsRequest{Number: -1}
+
+	// parse the block number (if available)
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		num, err := strconv.ParseInt(args[0], 10, 64)
+		if err != nil {
+			c.UI.Error(fmt.Sprintf("Invalid block number %q", args[0]))
+			return 1
+		}
+
+		req.Number = num
+		args = args[1:]
+	}
+
+	if err := flags.Parse(args); err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+
+	borClt, err := c.BorConn()
+	if err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+
+	resp, err := borClt.DebugParallelStats(context.Background(), req)
+	if err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+
+	c.UI.Output(formatParallelStats(resp))
+
+	return 0
+}
+
+func formatParallelStats(stats *proto.DebugParallelStatsResponse) string {
+	path := make([]string, 0, len(stats.LongestPath))
+	for _, tx := range stats.LongestPath {
+		path = append(path, strconv.FormatUint(tx, 10))
+	}
+
+	return formatKV([]string{
+		fmt.Sprintf("Block|%d", stats.Number),
+		fmt.Sprintf("Hash|%s", stats.Hash),
+		fmt.Sprintf("Transactions|%d", stats.Transactions),
+		fmt.Sprintf("Dependencies known|%v", stats.Metadata),
+		fmt.Sprintf("Executions|%d", stats.Executions),
+		fmt.Sprintf("Re-executions|%d", stats.Reexecutions),
+		fmt.Sprintf("Aborted incarnations|%d", stats.Aborts),
+		fmt.Sprintf("Validation failures|%d", stats.ValidationFailures),
+		fmt.Sprintf("Longest path|(%d) %s", len(path), strings.Join(path, "->")),
+		fmt.Sprintf("Longest path time|%v", time.Duration(stats.LongestPathTime)),
+		fmt.Sprintf("Serial time|%v", time.Duration(stats.SerialTime)),
+		fmt.Sprintf("Execution time|%v", time.Duration(stats.ExecutionTime)),
+		fmt.Sprintf("Speculative processes|%d", stats.SpeculativeProcesses),
+		fmt.Sprintf("Utilisation|%.1f%%", stats.Utilisation*100),
+		fmt.Sprintf("Estimated speedup|%.2fx", stats.EstimatedSpeedup),
+	})
+}
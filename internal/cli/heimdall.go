@@ -0,0 +1,44 @@
+package cli
+
+import (
+	"strings"
+
+	"github.com/mitchellh/cli"
+)
+
+// HeimdallCommand is the command to group the heimdall commands
+type HeimdallCommand struct {
+	UI cli.Ui
+}
+
+// MarkDown implements cli.MarkDown interface
+func (c *HeimdallCommand) MarkDown() string {
+	items := []string{
+		"# Heimdall",
+		"The ```heimdall``` command groups actions on the Heimdall data used by the client:",
+		"- [```heimdall export```](./heimdall_export.md): Export the Heimdall data for a range of blocks to an archive.",
+	}
+
+	return strings.Join(items, "\n\n")
+}
+
+// Help implements the cli.Command interface
+func (c *HeimdallCommand) Help() string {
+	return `Usage: bor heimdall <subcommand>
+
+  This command groups actions on the Heimdall data used by the client.
+
+  Export the Heimdall data for a range of blocks:
+
+    $ bor heimdall export --from <number> --to <number> <file>`
+}
+
+// Synopsis implements the cli.Command interface
+func (c *HeimdallCommand) Synopsis() string {
+	return "Heimdall related commands"
+}
+
+// Run implements the cli.Command interface
+func (c *HeimdallCommand) Run(args []string) int {
+	return cli.RunResultHelp
+}
@@ -0,0 +1,139 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	"github.com/ethereum/go-ethereum/consensus/bor/heimdall"
+	"github.com/ethereum/go-ethereum/consensus/bor/heimdallarchive"
+	"github.com/ethereum/go-ethereum/internal/cli/flagset"
+
+	"github.com/mitchellh/cli"
+)
+
+// HeimdallExportCommand is the command to export the heimdall data for a range of blocks
+type HeimdallExportCommand struct {
+	UI cli.Ui
+
+	url       string
+	fromBlock uint64
+	toBlock   uint64
+	fromEvent uint64
+	toTime    uint64
+}
+
+// MarkDown implements cli.MarkDown interface
+func (c *HeimdallExportCommand) MarkDown() string {
+	items := []string{
+		"# Heimdall export",
+		"The ```heimdall export <file>``` command exports the spans, checkpoints, state-sync events and latest milestone needed to process a range of blocks to an archive. The archive can be served to the client with ```--bor.heimdallarchive``` to process these blocks without Heimdall.",
+		"## Arguments",
+		"- ```file```: Path of the archive to write.",
+		c.Flags().MarkDown(),
+	}
+
+	return strings.Join(items, "\n\n")
+}
+
+// Help implements the cli.Command interface
+func (c *HeimdallExportCommand) Help() string {
+	return `Usage: bor heimdall export --from <number> --to <number> --event-from <id> <file>
+
+  This command exports the Heimdall data needed to process a range of blocks` + c.Flags().Help()
+}
+
+// Synopsis implements the cli.Command interface
+func (c *HeimdallExportCommand) Synopsis() string {
+	return "Export the Heimdall data for a range of blocks"
+}
+
+func (c *HeimdallExportCommand) Flags() *flagset.Flagset {
+	flags := flagset.NewFlagSet("heimdall export")
+
+	flags.StringFlag(&flagset.StringFlag{
+		Name:    "heimdall",
+		Usage:   "URL of the Heimdall service to export from",
+		Value:   &c.url,
+		Default: "http://localhost:1317",
+	})
+	flags.Uint64Flag(&flagset.Uint64Flag{
+		Name:  "from",
+		Usage: "First block of the range",
+		Value: &c.fromBlock,
+	})
+	flags.Uint64Flag(&flagset.Uint64Flag{
+		Name:  "to",
+		Usage: "Last block of the range",
+		Value: &c.toBlock,
+	})
+	flags.Uint64Flag(&flagset.Uint64Flag{
+		Name:  "event-from",
+		Usage: "Id of the first state-sync event to export (required), the lastStateId of the StateReceiver contract at the block before the first block plus one",
+		Value: &c.fromEvent,
+	})
+	flags.Uint64Flag(&flagset.Uint64Flag{
+		Name:  "event-to",
+		Usage: "Record time (unix seconds) up to which state-sync events are exported, defaults to the timestamp of the checkpoint including the last block",
+		Value: &c.toTime,
+	})
+
+	return flags
+}
+
+// Run implements the cli.Command interface
+func (c *HeimdallExportCommand) Run(args []string) int {
+	flags := c.Flags()
+	if err := flags.Parse(args); err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+
+	args = flags.Args()
+	if len(args) != 1 {
+		c.UI.Error("No archive file provided")
+		return 1
+	}
+
+	if c.fromEvent == 0 {
+		c.UI.Error("No first state-sync event id provided (--event-from)")
+		return 1
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	signalCh := make(chan os.Signal, 1)
+	signal.Notify(signalCh, os.Interrupt, syscall.SIGTERM)
+
+	go func() {
+		<-signalCh
+		cancel()
+	}()
+
+	client := heimdall.NewHeimdallClient(c.url)
+	defer client.Close()
+
+	archive, err := heimdallarchive.Export(ctx, client, heimdallarchive.ExportConfig{
+		FromBlock:   c.fromBlock,
+		ToBlock:     c.toBlock,
+		FromEventID: c.fromEvent,
+		ToTime:      int64(c.toTime),
+	})
+	if err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+
+	if err := archive.Write(args[0]); err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+
+	c.UI.Output(fmt.Sprintf("Exported %d spans, %d checkpoints and %d state-sync events to %s", len(archive.Spans), len(archive.Checkpoints), len(archive.Events), args[0]))
+
+	return 0
+}
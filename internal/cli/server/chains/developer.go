@@ -1,10 +1,12 @@
 package chains
 
 import (
+	"bytes"
 	"math/big"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/vm"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/params"
 )
@@ -45,3 +47,85 @@ func GetDeveloperChain(period uint64, gasLimitt uint64, faucet common.Address) *
 		Bootnodes: []string{},
 	}
 }
+
+// borDeveloperValidator is the initial validator compiled in the validator set
+// contract of the bor developer preallocation, replaced by the developer one.
+var borDeveloperValidator = common.HexToAddress("0x71562b71999873DB5b286dF957af199Ec94617F7")
+
+// GetBorDeveloperChain returns the developer mode configs of a bor chain, run
+// along with the mock heimdall. The given validator is the only validator of
+// the first span, and the faucet of the chain.
+func GetBorDeveloperChain(period uint64, gasLimit uint64, validator common.Address) *Chain {
+	// Bor can't produce blocks on demand
+	if period == 0 {
+		period = 1
+	}
+
+	alloc := readPrealloc("allocs/bor_developer.json")
+
+	// The first span validators are hardcoded in the validator set contract
+	validatorSet := common.HexToAddress("0x0000000000000000000000000000000000001000")
+	account := alloc[validatorSet]
+	account.Code = bytes.ReplaceAll(account.Code, append([]byte{byte(vm.PUSH20)}, borDeveloperValidator[:]...), append([]byte{byte(vm.PUSH20)}, validator[:]...))
+	alloc[validatorSet] = account
+
+	for i := byte(1); i <= 9; i++ {
+		alloc[common.BytesToAddress([]byte{i})] = core.GenesisAccount{Balance: big.NewInt(1)} // Precompiles
+	}
+
+	alloc[validator] = core.GenesisAccount{Balance: new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 256), big.NewInt(9))}
+
+	return &Chain{
+		Hash:      common.Hash{},
+		NetworkId: 1337,
+		Genesis: &core.Genesis{
+			Config: &params.ChainConfig{
+				ChainID:             big.NewInt(1337),
+				HomesteadBlock:      big.NewInt(0),
+				EIP150Block:         big.NewInt(0),
+				EIP155Block:         big.NewInt(0),
+				EIP158Block:         big.NewInt(0),
+				ByzantiumBlock:      big.NewInt(0),
+				ConstantinopleBlock: big.NewInt(0),
+				PetersburgBlock:     big.NewInt(0),
+				IstanbulBlock:       big.NewInt(0),
+				MuirGlacierBlock:    big.NewInt(0),
+				BerlinBlock:         big.NewInt(0),
+				LondonBlock:         big.NewInt(0),
+				ShanghaiBlock:       big.NewInt(0),
+				CancunBlock:         big.NewInt(0),
+				Bor: &params.BorConfig{
+					JaipurBlock: big.NewInt(0),
+					DelhiBlock:  big.NewInt(0),
+					IndoreBlock: big.NewInt(0),
+					StateSyncConfirmationDelay: map[string]uint64{
+						"0": 128,
+					},
+					Period: map[string]uint64{
+						"0": period,
+					},
+					ProducerDelay: map[string]uint64{
+						"0": 2 * period,
+					},
+					Sprint: map[string]uint64{
+						"0": 16,
+					},
+					BackupMultiplier: map[string]uint64{
+						"0": period,
+					},
+					ValidatorContract:     validatorSet.Hex(),
+					StateReceiverContract: "0x0000000000000000000000000000000000001001",
+					BurntContract: map[string]string{
+						"0": "0x000000000000000000000000000000000000dead",
+					},
+				},
+			},
+			ExtraData:  make([]byte, 32+crypto.SignatureLength),
+			GasLimit:   gasLimit,
+			BaseFee:    big.NewInt(params.InitialBaseFee),
+			Difficulty: big.NewInt(1),
+			Alloc:      alloc,
+		},
+		Bootnodes: []string{},
+	}
+}
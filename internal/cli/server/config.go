@@ -2,6 +2,7 @@ package server
 
 import (
 	"crypto/ecdsa"
+	"errors"
 	"fmt"
 	"math"
 	"math/big"
@@ -251,15 +252,39 @@ type P2PDiscovery struct {
 }
 
 type HeimdallConfig struct {
-	// URL is the url of the heimdall server
+	// URL is the url of the heimdall server, comma separated for several servers
 	URL string `hcl:"url,optional" toml:"url,optional"`
 
 	// Without is used to disable remote heimdall during testing
 	Without bool `hcl:"bor.without,optional" toml:"bor.without,optional"`
 
-	// GRPCAddress is the address of the heimdall grpc server
+	// GRPCAddress is the address of the heimdall grpc server, comma separated for several servers
 	GRPCAddress string `hcl:"grpc-address,optional" toml:"grpc-address,optional"`
 
+	// Quorum is the number of heimdall servers that must agree on spans and milestones
+	Quorum int `hcl:"quorum,optional" toml:"quorum,optional"`
+
+	// Archive is the path of a heimdall archive to serve heimdall data from, without connecting to heimdall
+	Archive string `hcl:"archive,optional" toml:"archive,optional"`
+
+	// NoCache disables caching the immutable heimdall responses in the chain database
+	NoCache bool `hcl:"no-cache,optional" toml:"no-cache,optional"`
+
+	// CacheSpans is the number of the latest spans kept in the heimdall cache, all of them if 0
+	CacheSpans uint64 `hcl:"cache-spans,optional" toml:"cache-spans,optional"`
+
+	// Mock runs an in-process mock heimdall, used as the heimdall of the node
+	Mock bool `hcl:"mock,optional" toml:"mock,optional"`
+
+	// MockAddress is the address the mock heimdall listens on
+	MockAddress string `hcl:"mock-address,optional" toml:"mock-address,optional"`
+
+	// MockValidators are the validators of the spans of the mock heimdall, the etherbase if empty
+	MockValidators []string `hcl:"mock-validators,optional" toml:"mock-validators,optional"`
+
+	// MockSpanLength is the number of blocks of the spans of the mock heimdall
+	MockSpanLength uint64 `hcl:"mock-span-length,optional" toml:"mock-span-length,optional"`
+
 	// RunHeimdall is used to run heimdall as a child process
 	RunHeimdall bool `hcl:"bor.runheimdall,optional" toml:"bor.runheimdall,optional"`
 
@@ -330,8 +355,33 @@ type SealerConfig struct {
 
 	CommitInterruptFlag bool `hcl:"commitinterrupt,optional" toml:"commitinterrupt,optional"`
 
+	// ConflictAwareSelection prefers the transactions not conflicting with the ones already in the block
+	ConflictAwareSelection bool `hcl:"conflictaware,optional" toml:"conflictaware,optional"`
+
+	// ConflictFeeTolerance is the maximum percentage of the tip given up to prefer a non-conflicting transaction
+	ConflictFeeTolerance uint64 `hcl:"conflicttolerance,optional" toml:"conflicttolerance,optional"`
+
 	// maximum MEV workers
-	MaxMergedBundles uint64 `hcl:"maxmergedbundles,optional" toml:"maxmergedbundles,optional`
+	MaxMergedBundles uint64 `hcl:"maxmergedbundles,optional" toml:"maxmergedbundles,optional"`
+
+	// BundleGasShare is the maximum percentage of the block gas limit used by bundles
+	BundleGasShare uint64 `hcl:"bundlegasshare,optional" toml:"bundlegasshare,optional"`
+
+	// MempoolGasReserve is the block gas reserved for mempool transactions
+	MempoolGasReserve uint64 `hcl:"mempoolgasreserve,optional" toml:"mempoolgasreserve,optional"`
+
+	// MaxBundleGas is the maximum gas used by a single bundle
+	MaxBundleGas uint64 `hcl:"maxbundlegas,optional" toml:"maxbundlegas,optional"`
+
+	// BuilderKey is the path of the key collecting the block fees in builder mode
+	BuilderKey string `hcl:"builderkey,optional" toml:"builderkey,optional"`
+
+	// ValidatorPayment is the address paid at the end of every block in builder mode
+	ValidatorPayment string `hcl:"validatorpayment,optional" toml:"validatorpayment,optional"`
+
+	// PaymentMargin is the part of the block profit kept by the builder
+	PaymentMargin    *big.Int `hcl:"-,optional" toml:"-"`
+	PaymentMarginRaw string   `hcl:"paymentmargin,optional" toml:"paymentmargin,optional"`
 }
 
 type JsonRPCConfig struct {
@@ -600,6 +650,15 @@ type ParallelEVMConfig struct {
 	Enable bool `hcl:"enable,optional" toml:"enable,optional"`
 
 	SpeculativeProcesses int `hcl:"procs,optional" toml:"procs,optional"`
+
+	// Adaptive adjusts the speculative processes to every block, executing serially when parallel would lose
+	Adaptive bool `hcl:"adaptive,optional" toml:"adaptive,optional"`
+
+	// ShadowInterval is every how many blocks the serial and parallel processors are compared
+	ShadowInterval uint64 `hcl:"shadowinterval,optional" toml:"shadowinterval,optional"`
+
+	// ShadowDir is the directory of the debug bundles of the blocks the processors mismatch on
+	ShadowDir string `hcl:"shadowdir,optional" toml:"shadowdir,optional"`
 }
 
 func DefaultConfig() *Config {
@@ -644,9 +703,13 @@ func DefaultConfig() *Config {
 			},
 		},
 		Heimdall: &HeimdallConfig{
-			URL:         "http://localhost:1317",
-			Without:     false,
-			GRPCAddress: "",
+			URL:            "http://localhost:1317",
+			Without:        false,
+			GRPCAddress:    "",
+			CacheSpans:     0,
+			MockAddress:    "127.0.0.1:1317",
+			MockValidators: []string{},
+			MockSpanLength: 6400,
 		},
 		SyncMode:    "full",
 		GcMode:      "full",
@@ -675,6 +738,7 @@ func DefaultConfig() *Config {
 			Recommit:            700 * time.Millisecond,
 			CommitInterruptFlag: true,
 			MaxMergedBundles:    3,
+			PaymentMargin:       big.NewInt(0),
 		},
 		Gpo: &GpoConfig{
 			Blocks:           20,
@@ -811,6 +875,7 @@ func (c *Config) fillBigInt() error {
 		{"gpo.maxprice", &c.Gpo.MaxPrice, &c.Gpo.MaxPriceRaw},
 		{"gpo.ignoreprice", &c.Gpo.IgnorePrice, &c.Gpo.IgnorePriceRaw},
 		{"miner.gasprice", &c.Sealer.GasPrice, &c.Sealer.GasPriceRaw},
+		{"miner.paymentmargin", &c.Sealer.PaymentMargin, &c.Sealer.PaymentMarginRaw},
 	}
 
 	for _, x := range tds {
@@ -933,6 +998,23 @@ func (c *Config) buildEth(stack *node.Node, accountManager *accounts.Manager) (*
 	n.HeimdallURL = c.Heimdall.URL
 	n.WithoutHeimdall = c.Heimdall.Without
 	n.HeimdallgRPCAddress = c.Heimdall.GRPCAddress
+	n.HeimdallQuorum = c.Heimdall.Quorum
+	n.HeimdallArchive = c.Heimdall.Archive
+	n.HeimdallNoCache = c.Heimdall.NoCache
+	n.HeimdallCacheSpans = c.Heimdall.CacheSpans
+
+	// The gRPC endpoints replace the default HTTP one, which is only kept
+	// alongside them when set to another URL
+	if n.HeimdallgRPCAddress != "" && n.HeimdallURL == DefaultConfig().Heimdall.URL {
+		n.HeimdallURL = ""
+	}
+
+	if c.Heimdall.Mock {
+		n.HeimdallURL = "http://" + c.Heimdall.MockAddress
+		n.HeimdallgRPCAddress = ""
+		n.WithoutHeimdall = false
+	}
+
 	n.RunHeimdall = c.Heimdall.RunHeimdall
 	n.RunHeimdallArgs = c.Heimdall.RunHeimdallArgs
 	n.UseHeimdallApp = c.Heimdall.UseHeimdallApp
@@ -977,6 +1059,19 @@ func (c *Config) buildEth(stack *node.Node, accountManager *accounts.Manager) (*
 		n.Miner.ExtraData = []byte(c.Sealer.ExtraData)
 		n.Miner.CommitInterruptFlag = c.Sealer.CommitInterruptFlag
 		n.Miner.MaxMergedBundles = c.Sealer.MaxMergedBundles
+		n.Miner.BundleGasShare = c.Sealer.BundleGasShare
+		n.Miner.MempoolGasReserve = c.Sealer.MempoolGasReserve
+		n.Miner.MaxBundleGas = c.Sealer.MaxBundleGas
+		n.Miner.ConflictAwareSelection = c.Sealer.ConflictAwareSelection
+		n.Miner.ConflictFeeTolerance = c.Sealer.ConflictFeeTolerance
+
+		if c.Sealer.BundleGasShare > 100 {
+			return nil, fmt.Errorf("bundle gas share is not a percentage: %d", c.Sealer.BundleGasShare)
+		}
+
+		if c.Sealer.ConflictFeeTolerance > 100 {
+			return nil, fmt.Errorf("conflict fee tolerance is not a percentage: %d", c.Sealer.ConflictFeeTolerance)
+		}
 
 		if etherbase := c.Sealer.Etherbase; etherbase != "" {
 			if !common.IsHexAddress(etherbase) {
@@ -985,6 +1080,22 @@ func (c *Config) buildEth(stack *node.Node, accountManager *accounts.Manager) (*
 
 			n.Miner.Etherbase = common.HexToAddress(etherbase)
 		}
+
+		if c.Sealer.BuilderKey != "" {
+			key, err := crypto.LoadECDSA(c.Sealer.BuilderKey)
+			if err != nil {
+				return nil, fmt.Errorf("failed to load builder key: %v", err)
+			}
+
+			validator := c.Sealer.ValidatorPayment
+			if !common.IsHexAddress(validator) {
+				return nil, fmt.Errorf("validator payment is not an address: %s", validator)
+			}
+
+			n.Miner.BuilderKey = key
+			n.Miner.ValidatorPayment = common.HexToAddress(validator)
+			n.Miner.PaymentMargin = c.Sealer.PaymentMargin
+		}
 	}
 
 	// unlock accounts
@@ -1046,8 +1157,13 @@ func (c *Config) buildEth(stack *node.Node, accountManager *accounts.Manager) (*
 		c.Sealer.Etherbase = developer.Address.Hex()
 		n.Miner.Etherbase = developer.Address
 
-		// get developer mode chain config
-		c.chain = chains.GetDeveloperChain(c.Developer.Period, c.Developer.GasLimit, developer.Address)
+		// get developer mode chain config, a bor chain validated by the
+		// developer account when run along with the mock heimdall
+		if c.Heimdall.Mock {
+			c.chain = chains.GetBorDeveloperChain(c.Developer.Period, c.Developer.GasLimit, developer.Address)
+		} else {
+			c.chain = chains.GetDeveloperChain(c.Developer.Period, c.Developer.GasLimit, developer.Address)
+		}
 
 		// update the parameters
 		n.NetworkId = c.chain.NetworkId
@@ -1065,6 +1181,12 @@ func (c *Config) buildEth(stack *node.Node, accountManager *accounts.Manager) (*
 		}
 	}
 
+	// The bor and clique engines credit the fees to the block signer, they
+	// reject the blocks whose fees are collected by the builder key
+	if n.Miner.BuilderKey != nil && n.Genesis != nil && (n.Genesis.Config.Bor != nil || n.Genesis.Config.Clique != nil) {
+		return nil, errors.New("builder mode is not supported by the bor and clique consensus engines")
+	}
+
 	// discovery (this params should be in node.Config)
 	{
 		n.EthDiscoveryURLs = c.P2P.Discovery.DNS
@@ -1201,6 +1323,9 @@ func (c *Config) buildEth(stack *node.Node, accountManager *accounts.Manager) (*
 
 	n.ParallelEVM.Enable = c.ParallelEVM.Enable
 	n.ParallelEVM.SpeculativeProcesses = c.ParallelEVM.SpeculativeProcesses
+	n.ParallelEVM.Adaptive = c.ParallelEVM.Adaptive
+	n.ParallelEVM.ShadowInterval = c.ParallelEVM.ShadowInterval
+	n.ParallelEVM.ShadowDir = c.ParallelEVM.ShadowDir
 	n.RPCReturnDataLimit = c.RPCReturnDataLimit
 
 	if c.Ancient != "" {
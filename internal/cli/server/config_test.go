@@ -1,10 +1,13 @@
 package server
 
 import (
+	"path/filepath"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
+
+	"github.com/ethereum/go-ethereum/crypto"
 )
 
 func TestConfigDefault(t *testing.T) {
@@ -19,6 +22,41 @@ func TestConfigDefault(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+func TestConfigBuilderMode(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	assert.NoError(t, err)
+
+	path := filepath.Join(t.TempDir(), "builder.key")
+	assert.NoError(t, crypto.SaveECDSA(path, key))
+
+	// The bor engine of the default chain credits the fees to the signer
+	config := DefaultConfig()
+	config.Sealer.BuilderKey = path
+	config.Sealer.ValidatorPayment = "0x1000000000000000000000000000000000000001"
+	assert.NoError(t, config.loadChain())
+
+	_, err = config.buildEth(nil, nil)
+	assert.ErrorContains(t, err, "builder mode is not supported")
+}
+
+func TestConfigHeimdallEndpoints(t *testing.T) {
+	config := DefaultConfig()
+	config.Heimdall.GRPCAddress = "localhost:3132"
+	assert.NoError(t, config.loadChain())
+
+	// The gRPC address replaces the default HTTP URL
+	eth, err := config.buildEth(nil, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "", eth.HeimdallURL)
+
+	// An HTTP URL set explicitly is kept alongside it
+	config.Heimdall.URL = "http://heimdall:1317"
+
+	eth, err = config.buildEth(nil, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "http://heimdall:1317", eth.HeimdallURL)
+}
+
 func TestConfigMerge(t *testing.T) {
 	c0 := &Config{
 		Chain:    "0",
@@ -163,7 +163,7 @@ func (c *Command) Flags(config *Config) *flagset.Flagset {
 	// heimdall
 	f.StringFlag(&flagset.StringFlag{
 		Name:    "bor.heimdall",
-		Usage:   "URL of Heimdall service",
+		Usage:   "Comma separated URLs of Heimdall services, used as fallback when gRPC is configured if set to other than the default",
 		Value:   &c.cliConfig.Heimdall.URL,
 		Default: c.cliConfig.Heimdall.URL,
 	})
@@ -181,10 +181,58 @@ func (c *Command) Flags(config *Config) *flagset.Flagset {
 	})
 	f.StringFlag(&flagset.StringFlag{
 		Name:    "bor.heimdallgRPC",
-		Usage:   "Address of Heimdall gRPC service",
+		Usage:   "Comma separated addresses of Heimdall gRPC services",
 		Value:   &c.cliConfig.Heimdall.GRPCAddress,
 		Default: c.cliConfig.Heimdall.GRPCAddress,
 	})
+	f.IntFlag(&flagset.IntFlag{
+		Name:    "bor.heimdallquorum",
+		Usage:   "Number of Heimdall services that must agree on spans and milestones, at most the number of services (0 or 1 to disable)",
+		Value:   &c.cliConfig.Heimdall.Quorum,
+		Default: c.cliConfig.Heimdall.Quorum,
+	})
+	f.StringFlag(&flagset.StringFlag{
+		Name:    "bor.heimdallarchive",
+		Usage:   "Path of a Heimdall archive (exported with bor heimdall export) to serve Heimdall data from, without connecting to Heimdall",
+		Value:   &c.cliConfig.Heimdall.Archive,
+		Default: c.cliConfig.Heimdall.Archive,
+	})
+	f.BoolFlag(&flagset.BoolFlag{
+		Name:    "bor.heimdallnocache",
+		Usage:   "Don't cache the spans, checkpoints and state-sync events fetched from Heimdall in the chain database",
+		Value:   &c.cliConfig.Heimdall.NoCache,
+		Default: c.cliConfig.Heimdall.NoCache,
+	})
+	f.Uint64Flag(&flagset.Uint64Flag{
+		Name:    "bor.heimdallcachespans",
+		Usage:   "Number of the latest spans kept in the Heimdall cache (0 = all)",
+		Value:   &c.cliConfig.Heimdall.CacheSpans,
+		Default: c.cliConfig.Heimdall.CacheSpans,
+	})
+	f.BoolFlag(&flagset.BoolFlag{
+		Name:    "bor.heimdallmock",
+		Usage:   "Run an in-process mock Heimdall for bor devnets, used as the Heimdall of the node (with --dev, the developer chain is a bor chain validated by the developer account)",
+		Value:   &c.cliConfig.Heimdall.Mock,
+		Default: c.cliConfig.Heimdall.Mock,
+	})
+	f.StringFlag(&flagset.StringFlag{
+		Name:    "bor.heimdallmock.addr",
+		Usage:   "Address the mock Heimdall listens on",
+		Value:   &c.cliConfig.Heimdall.MockAddress,
+		Default: c.cliConfig.Heimdall.MockAddress,
+	})
+	f.SliceStringFlag(&flagset.SliceStringFlag{
+		Name:    "bor.heimdallmock.validators",
+		Usage:   "Comma separated validators of the spans of the mock Heimdall, the etherbase if empty",
+		Value:   &c.cliConfig.Heimdall.MockValidators,
+		Default: c.cliConfig.Heimdall.MockValidators,
+	})
+	f.Uint64Flag(&flagset.Uint64Flag{
+		Name:    "bor.heimdallmock.spanlength",
+		Usage:   "Number of blocks of the spans of the mock Heimdall",
+		Value:   &c.cliConfig.Heimdall.MockSpanLength,
+		Default: c.cliConfig.Heimdall.MockSpanLength,
+	})
 	f.BoolFlag(&flagset.BoolFlag{
 		Name:    "bor.runheimdall",
 		Usage:   "Run Heimdall service as a child process",
@@ -333,6 +381,20 @@ func (c *Command) Flags(config *Config) *flagset.Flagset {
 		Default: c.cliConfig.Sealer.CommitInterruptFlag,
 		Group:   "Sealer",
 	})
+	f.BoolFlag(&flagset.BoolFlag{
+		Name:    "miner.conflictaware",
+		Usage:   "Prefer transactions not conflicting with the ones already in the block, for the block to execute with more parallelism",
+		Value:   &c.cliConfig.Sealer.ConflictAwareSelection,
+		Default: c.cliConfig.Sealer.ConflictAwareSelection,
+		Group:   "Sealer",
+	})
+	f.Uint64Flag(&flagset.Uint64Flag{
+		Name:    "miner.conflicttolerance",
+		Usage:   "Maximum percentage of the tip given up to prefer a transaction not conflicting with the block",
+		Value:   &c.cliConfig.Sealer.ConflictFeeTolerance,
+		Default: c.cliConfig.Sealer.ConflictFeeTolerance,
+		Group:   "Sealer",
+	})
 	f.Uint64Flag(&flagset.Uint64Flag{
 		Name:    "miner.maxmergedbundles",
 		Usage:   "flashbots - The maximum amount of bundles to merge. The miner will run this many workers in parallel to calculate if the full block is more profitable with these additional bundles.",
@@ -340,6 +402,48 @@ func (c *Command) Flags(config *Config) *flagset.Flagset {
 		Default: c.cliConfig.Sealer.MaxMergedBundles,
 		Group:   "Sealer",
 	})
+	f.Uint64Flag(&flagset.Uint64Flag{
+		Name:    "miner.bundlegasshare",
+		Usage:   "flashbots - Maximum percentage of the block gas limit used by bundles (0 = no limit)",
+		Value:   &c.cliConfig.Sealer.BundleGasShare,
+		Default: c.cliConfig.Sealer.BundleGasShare,
+		Group:   "Sealer",
+	})
+	f.Uint64Flag(&flagset.Uint64Flag{
+		Name:    "miner.mempoolgasreserve",
+		Usage:   "flashbots - Block gas reserved for mempool transactions, never used by bundles",
+		Value:   &c.cliConfig.Sealer.MempoolGasReserve,
+		Default: c.cliConfig.Sealer.MempoolGasReserve,
+		Group:   "Sealer",
+	})
+	f.Uint64Flag(&flagset.Uint64Flag{
+		Name:    "miner.maxbundlegas",
+		Usage:   "flashbots - Maximum gas used by a single bundle (0 = no limit)",
+		Value:   &c.cliConfig.Sealer.MaxBundleGas,
+		Default: c.cliConfig.Sealer.MaxBundleGas,
+		Group:   "Sealer",
+	})
+	f.StringFlag(&flagset.StringFlag{
+		Name:    "miner.builderkey",
+		Usage:   "Path of the builder key collecting the block fees, enables paying the validator with a transaction at the end of the block (not supported by bor and clique chains)",
+		Value:   &c.cliConfig.Sealer.BuilderKey,
+		Default: c.cliConfig.Sealer.BuilderKey,
+		Group:   "Sealer",
+	})
+	f.StringFlag(&flagset.StringFlag{
+		Name:    "miner.validatorpayment",
+		Usage:   "Address receiving the builder payment at the end of the block",
+		Value:   &c.cliConfig.Sealer.ValidatorPayment,
+		Default: c.cliConfig.Sealer.ValidatorPayment,
+		Group:   "Sealer",
+	})
+	f.BigIntFlag(&flagset.BigIntFlag{
+		Name:    "miner.paymentmargin",
+		Usage:   "Part of the block profit (in wei) kept by the builder",
+		Value:   c.cliConfig.Sealer.PaymentMargin,
+		Default: c.cliConfig.Sealer.PaymentMargin,
+		Group:   "Sealer",
+	})
 
 	// ethstats
 	f.StringFlag(&flagset.StringFlag{
@@ -986,6 +1090,24 @@ func (c *Command) Flags(config *Config) *flagset.Flagset {
 		Value:   &c.cliConfig.ParallelEVM.SpeculativeProcesses,
 		Default: c.cliConfig.ParallelEVM.SpeculativeProcesses,
 	})
+	f.BoolFlag(&flagset.BoolFlag{
+		Name:    "parallelevm.adaptive",
+		Usage:   "Adjust the speculative processes to the dependencies of every block and the recent aborts, executing serially when Block STM would lose",
+		Value:   &c.cliConfig.ParallelEVM.Adaptive,
+		Default: c.cliConfig.ParallelEVM.Adaptive,
+	})
+	f.Uint64Flag(&flagset.Uint64Flag{
+		Name:    "parallelevm.shadowinterval",
+		Usage:   "Execute every n-th block with both the serial processor and Block STM, comparing their results (0 = disabled)",
+		Value:   &c.cliConfig.ParallelEVM.ShadowInterval,
+		Default: c.cliConfig.ParallelEVM.ShadowInterval,
+	})
+	f.StringFlag(&flagset.StringFlag{
+		Name:    "parallelevm.shadowdir",
+		Usage:   "Directory of the debug bundles of the blocks the serial processor and Block STM mismatch on (default = inside the datadir)",
+		Value:   &c.cliConfig.ParallelEVM.ShadowDir,
+		Default: c.cliConfig.ParallelEVM.ShadowDir,
+	})
 	f.Uint64Flag(&flagset.Uint64Flag{
 		Name:    "dev.gaslimit",
 		Usage:   "Initial block gas limit",
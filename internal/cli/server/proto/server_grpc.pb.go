@@ -31,6 +31,10 @@ type BorClient interface {
 	ChainWatch(ctx context.Context, in *ChainWatchRequest, opts ...grpc.CallOption) (Bor_ChainWatchClient, error)
 	DebugPprof(ctx context.Context, in *DebugPprofRequest, opts ...grpc.CallOption) (Bor_DebugPprofClient, error)
 	DebugBlock(ctx context.Context, in *DebugBlockRequest, opts ...grpc.CallOption) (Bor_DebugBlockClient, error)
+	DebugParallelStats(ctx context.Context, in *DebugParallelStatsRequest, opts ...grpc.CallOption) (*DebugParallelStatsResponse, error)
+	MinerGetConfig(ctx context.Context, in *MinerGetConfigRequest, opts ...grpc.CallOption) (*MinerGetConfigResponse, error)
+	MinerSetMaxMergedBundles(ctx context.Context, in *MinerSetMaxMergedBundlesRequest, opts ...grpc.CallOption) (*MinerSetMaxMergedBundlesResponse, error)
+	MinerSetBundles(ctx context.Context, in *MinerSetBundlesRequest, opts ...grpc.CallOption) (*MinerSetBundlesResponse, error)
 }
 
 type borClient struct {
@@ -215,6 +219,42 @@ func (x *borDebugBlockClient) Recv() (*DebugFileResponse, error) {
 	return m, nil
 }
 
+func (c *borClient) DebugParallelStats(ctx context.Context, in *DebugParallelStatsRequest, opts ...grpc.CallOption) (*DebugParallelStatsResponse, error) {
+	out := new(DebugParallelStatsResponse)
+	err := c.cc.Invoke(ctx, "/proto.Bor/DebugParallelStats", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *borClient) MinerGetConfig(ctx context.Context, in *MinerGetConfigRequest, opts ...grpc.CallOption) (*MinerGetConfigResponse, error) {
+	out := new(MinerGetConfigResponse)
+	err := c.cc.Invoke(ctx, "/proto.Bor/MinerGetConfig", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *borClient) MinerSetMaxMergedBundles(ctx context.Context, in *MinerSetMaxMergedBundlesRequest, opts ...grpc.CallOption) (*MinerSetMaxMergedBundlesResponse, error) {
+	out := new(MinerSetMaxMergedBundlesResponse)
+	err := c.cc.Invoke(ctx, "/proto.Bor/MinerSetMaxMergedBundles", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *borClient) MinerSetBundles(ctx context.Context, in *MinerSetBundlesRequest, opts ...grpc.CallOption) (*MinerSetBundlesResponse, error) {
+	out := new(MinerSetBundlesResponse)
+	err := c.cc.Invoke(ctx, "/proto.Bor/MinerSetBundles", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // BorServer is the server API for Bor service.
 // All implementations must embed UnimplementedBorServer
 // for forward compatibility
@@ -228,6 +268,10 @@ type BorServer interface {
 	ChainWatch(*ChainWatchRequest, Bor_ChainWatchServer) error
 	DebugPprof(*DebugPprofRequest, Bor_DebugPprofServer) error
 	DebugBlock(*DebugBlockRequest, Bor_DebugBlockServer) error
+	DebugParallelStats(context.Context, *DebugParallelStatsRequest) (*DebugParallelStatsResponse, error)
+	MinerGetConfig(context.Context, *MinerGetConfigRequest) (*MinerGetConfigResponse, error)
+	MinerSetMaxMergedBundles(context.Context, *MinerSetMaxMergedBundlesRequest) (*MinerSetMaxMergedBundlesResponse, error)
+	MinerSetBundles(context.Context, *MinerSetBundlesRequest) (*MinerSetBundlesResponse, error)
 	mustEmbedUnimplementedBorServer()
 }
 
@@ -262,6 +306,18 @@ func (UnimplementedBorServer) DebugPprof(*DebugPprofRequest, Bor_DebugPprofServe
 func (UnimplementedBorServer) DebugBlock(*DebugBlockRequest, Bor_DebugBlockServer) error {
 	return status.Errorf(codes.Unimplemented, "method DebugBlock not implemented")
 }
+func (UnimplementedBorServer) DebugParallelStats(context.Context, *DebugParallelStatsRequest) (*DebugParallelStatsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DebugParallelStats not implemented")
+}
+func (UnimplementedBorServer) MinerGetConfig(context.Context, *MinerGetConfigRequest) (*MinerGetConfigResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method MinerGetConfig not implemented")
+}
+func (UnimplementedBorServer) MinerSetMaxMergedBundles(context.Context, *MinerSetMaxMergedBundlesRequest) (*MinerSetMaxMergedBundlesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method MinerSetMaxMergedBundles not implemented")
+}
+func (UnimplementedBorServer) MinerSetBundles(context.Context, *MinerSetBundlesRequest) (*MinerSetBundlesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method MinerSetBundles not implemented")
+}
 func (UnimplementedBorServer) mustEmbedUnimplementedBorServer() {}
 
 // UnsafeBorServer may be embedded to opt out of forward compatibility for this service.
@@ -467,6 +523,78 @@ func (x *borDebugBlockServer) Send(m *DebugFileResponse) error {
 	return x.ServerStream.SendMsg(m)
 }
 
+func _Bor_DebugParallelStats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DebugParallelStatsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BorServer).DebugParallelStats(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.Bor/DebugParallelStats",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BorServer).DebugParallelStats(ctx, req.(*DebugParallelStatsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Bor_MinerGetConfig_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MinerGetConfigRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BorServer).MinerGetConfig(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.Bor/MinerGetConfig",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BorServer).MinerGetConfig(ctx, req.(*MinerGetConfigRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Bor_MinerSetMaxMergedBundles_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MinerSetMaxMergedBundlesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BorServer).MinerSetMaxMergedBundles(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.Bor/MinerSetMaxMergedBundles",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BorServer).MinerSetMaxMergedBundles(ctx, req.(*MinerSetMaxMergedBundlesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Bor_MinerSetBundles_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MinerSetBundlesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BorServer).MinerSetBundles(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.Bor/MinerSetBundles",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BorServer).MinerSetBundles(ctx, req.(*MinerSetBundlesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // Bor_ServiceDesc is the grpc.ServiceDesc for Bor service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -498,6 +626,22 @@ var Bor_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "Status",
 			Handler:    _Bor_Status_Handler,
 		},
+		{
+			MethodName: "DebugParallelStats",
+			Handler:    _Bor_DebugParallelStats_Handler,
+		},
+		{
+			MethodName: "MinerGetConfig",
+			Handler:    _Bor_MinerGetConfig_Handler,
+		},
+		{
+			MethodName: "MinerSetMaxMergedBundles",
+			Handler:    _Bor_MinerSetMaxMergedBundles_Handler,
+		},
+		{
+			MethodName: "MinerSetBundles",
+			Handler:    _Bor_MinerSetBundles_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
@@ -0,0 +1,217 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/bor"
+	"github.com/ethereum/go-ethereum/eth"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
+	"github.com/ethereum/go-ethereum/p2p"
+	"github.com/ethereum/go-ethereum/p2p/discover"
+)
+
+const (
+	// readinessInterval is the interval the next turn of the signer is predicted at
+	readinessInterval = 30 * time.Second
+
+	// readinessLead is how long before the turn of the signer the checks run
+	readinessLead = 5 * time.Minute
+
+	// readinessTimeout bounds the time spent on the checks
+	readinessTimeout = 10 * time.Second
+
+	// readinessMinPeers is the minimum number of peers to propagate the blocks
+	readinessMinPeers = 3
+
+	// readinessMaxDrift is the maximum drift of the local clock, blocks being
+	// rejected when announced too early
+	readinessMaxDrift = time.Second
+)
+
+var (
+	readinessWarningsGauge = metrics.NewRegisteredGauge("bor/readiness/warnings", nil)
+	readinessPeersWarn     = metrics.NewRegisteredCounter("bor/readiness/peers", nil)
+	readinessHeimdallWarn  = metrics.NewRegisteredCounter("bor/readiness/heimdall", nil)
+	readinessKeystoreWarn  = metrics.NewRegisteredCounter("bor/readiness/keystore", nil)
+	readinessClockWarn     = metrics.NewRegisteredCounter("bor/readiness/clock", nil)
+)
+
+// producerReadiness runs pre-flight checks ahead of the sprints produced by
+// the local signer, reporting the problems which would make it miss its turn.
+type producerReadiness struct {
+	bor      *bor.Bor
+	backend  *eth.Ethereum
+	server   *p2p.Server
+	accounts *accounts.Manager
+
+	warnings []string
+	lock     sync.RWMutex
+
+	quit chan struct{}
+	wg   sync.WaitGroup
+}
+
+func newProducerReadiness(bor *bor.Bor, backend *eth.Ethereum, server *p2p.Server, accounts *accounts.Manager) *producerReadiness {
+	return &producerReadiness{
+		bor:      bor,
+		backend:  backend,
+		server:   server,
+		accounts: accounts,
+		quit:     make(chan struct{}),
+	}
+}
+
+// Start implements node.Lifecycle, starting the scheduling of the checks.
+func (r *producerReadiness) Start() error {
+	r.wg.Add(1)
+
+	go r.loop()
+
+	return nil
+}
+
+// Stop implements node.Lifecycle, stopping the checks.
+func (r *producerReadiness) Stop() error {
+	close(r.quit)
+	r.wg.Wait()
+
+	return nil
+}
+
+// Warnings returns the problems found by the last checks.
+func (r *producerReadiness) Warnings() []string {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	return append([]string(nil), r.warnings...)
+}
+
+func (r *producerReadiness) loop() {
+	defer r.wg.Done()
+
+	ticker := time.NewTicker(readinessInterval)
+	defer ticker.Stop()
+
+	// The turn already checked, to run the checks once per sprint
+	var checked uint64
+
+	for {
+		select {
+		case <-ticker.C:
+			turn, ok := r.upcomingTurn()
+			if !ok || turn == checked {
+				continue
+			}
+
+			checked = turn
+
+			r.check(turn)
+		case <-r.quit:
+			return
+		}
+	}
+}
+
+// upcomingTurn returns the first block of the next sprint of the signer, if
+// expected to start within the lead time of the checks.
+func (r *producerReadiness) upcomingTurn() (uint64, bool) {
+	if !r.backend.Synced() {
+		return 0, false
+	}
+
+	signer, err := r.backend.Etherbase()
+	if err != nil {
+		return 0, false
+	}
+
+	chain := r.backend.BlockChain()
+	head := chain.CurrentHeader()
+
+	ctx, cancel := context.WithTimeout(context.Background(), readinessTimeout)
+	defer cancel()
+
+	turn, err := r.bor.NextProducerTurn(ctx, chain, head, signer)
+	if err != nil {
+		log.Debug("Failed to predict the producer turn", "signer", signer, "err", err)
+		return 0, false
+	}
+
+	// Blocks are expected every period from the head on
+	period := chain.Config().Bor.CalculatePeriod(head.Number.Uint64())
+	eta := time.Unix(int64(head.Time+(turn-head.Number.Uint64())*period), 0)
+
+	return turn, time.Until(eta) <= readinessLead
+}
+
+// check runs the checks ahead of the given turn, reporting the warnings.
+func (r *producerReadiness) check(turn uint64) {
+	var warnings []string
+
+	warn := func(counter metrics.Counter, format string, args ...interface{}) {
+		counter.Inc(1)
+
+		warnings = append(warnings, fmt.Sprintf(format, args...))
+	}
+
+	if peers := r.server.PeerCount(); peers < readinessMinPeers {
+		warn(readinessPeersWarn, "only %d peers connected", peers)
+	}
+
+	if r.bor.HeimdallClient != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), readinessTimeout)
+		_, err := r.bor.HeimdallClient.FetchCheckpointCount(ctx)
+
+		cancel()
+
+		if err != nil {
+			warn(readinessHeimdallWarn, "heimdall unreachable: %v", err)
+		}
+	}
+
+	if signer, err := r.backend.Etherbase(); err != nil {
+		warn(readinessKeystoreWarn, "no signer: %v", err)
+	} else if status := r.walletStatus(signer); status != "Unlocked" {
+		warn(readinessKeystoreWarn, "signer %s: %s", signer, status)
+	}
+
+	if drift, err := discover.ClockDrift(); err != nil {
+		log.Debug("Failed to measure the clock drift", "err", err)
+	} else if drift < -readinessMaxDrift || drift > readinessMaxDrift {
+		warn(readinessClockWarn, "system clock off by %v", drift)
+	}
+
+	for _, warning := range warnings {
+		log.Warn("Producer not ready for its sprint", "block", turn, "problem", warning)
+	}
+
+	if len(warnings) == 0 {
+		log.Info("Producer ready for its sprint", "block", turn)
+	}
+
+	readinessWarningsGauge.Update(int64(len(warnings)))
+
+	r.lock.Lock()
+	r.warnings = warnings
+	r.lock.Unlock()
+}
+
+// walletStatus returns the status of the wallet of the signer.
+func (r *producerReadiness) walletStatus(signer common.Address) string {
+	wallet, err := r.accounts.Find(accounts.Account{Address: signer})
+	if err != nil {
+		return err.Error()
+	}
+
+	status, err := wallet.Status()
+	if err != nil {
+		return err.Error()
+	}
+
+	return status
+}
@@ -2,6 +2,7 @@ package server
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"math/big"
@@ -25,8 +26,10 @@ import (
 	"github.com/ethereum/go-ethereum/accounts"
 	"github.com/ethereum/go-ethereum/accounts/keystore"
 	"github.com/ethereum/go-ethereum/cmd/utils"
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/consensus/beacon" //nolint:typecheck
 	"github.com/ethereum/go-ethereum/consensus/bor"    //nolint:typecheck
+	"github.com/ethereum/go-ethereum/consensus/bor/heimdallmock"
 	"github.com/ethereum/go-ethereum/consensus/clique"
 	"github.com/ethereum/go-ethereum/eth"
 	"github.com/ethereum/go-ethereum/eth/ethconfig"
@@ -56,6 +59,9 @@ type Server struct {
 
 	// tracerAPI to trace block executions
 	tracerAPI *tracers.API
+
+	// readiness checks the signer ahead of its sprints
+	readiness *producerReadiness
 }
 
 type serverOption func(srv *Server, config *Config) error
@@ -251,6 +257,13 @@ func NewServer(config *Config, opts ...serverOption) (*Server, error) {
 	// set the auth status in backend
 	srv.backend.SetAuthorized(authorized)
 
+	// mock heimdall (if enabled), started along with the node
+	if config.Heimdall.Mock {
+		if err := srv.setupHeimdallMock(stack, config.Heimdall); err != nil {
+			return nil, err
+		}
+	}
+
 	filterSystem := utils.RegisterFilterAPI(stack, srv.backend.APIBackend, ethCfg)
 
 	// debug tracing is enabled by default
@@ -278,6 +291,19 @@ func NewServer(config *Config, opts ...serverOption) (*Server, error) {
 		}
 	}
 
+	// producer readiness checks (if sealing a bor chain), started along with the node
+	if config.Sealer.Enabled {
+		if b, ok := srv.backend.Engine().(*bor.Bor); ok {
+			signerAccounts := stack.AccountManager()
+			if config.Accounts.DisableBorWallet {
+				signerAccounts = accountManager
+			}
+
+			srv.readiness = newProducerReadiness(b, srv.backend, stack.Server(), signerAccounts)
+			stack.RegisterLifecycle(srv.readiness)
+		}
+	}
+
 	if err := srv.setupMetrics(config.Telemetry, config.Identity); err != nil {
 		return nil, err
 	}
@@ -310,6 +336,48 @@ func (s *Server) Stop() {
 	}
 }
 
+// setupHeimdallMock registers a mock heimdall producing checkpoints and
+// milestones from the local chain.
+func (s *Server) setupHeimdallMock(stack *node.Node, config *HeimdallConfig) error {
+	chainConfig := s.backend.BlockChain().Config()
+	if chainConfig.Bor == nil {
+		return errors.New("mock heimdall needs a bor chain")
+	}
+
+	validators := make([]common.Address, 0, len(config.MockValidators))
+
+	for _, validator := range config.MockValidators {
+		if !common.IsHexAddress(validator) {
+			return fmt.Errorf("invalid mock heimdall validator %q", validator)
+		}
+
+		validators = append(validators, common.HexToAddress(validator))
+	}
+
+	if len(validators) == 0 {
+		etherbase, err := s.backend.Etherbase()
+		if err != nil {
+			return fmt.Errorf("mock heimdall needs validators or an etherbase: %v", err)
+		}
+
+		validators = append(validators, etherbase)
+	}
+
+	mock := heimdallmock.NewServer(heimdallmock.Config{
+		Address:    config.MockAddress,
+		ChainID:    chainConfig.ChainID.String(),
+		Validators: validators,
+		Sprint:     chainConfig.Bor.CalculateSprint(0),
+		SpanLength: config.MockSpanLength,
+	})
+	mock.SetBackend(s.backend.APIBackend)
+
+	stack.RegisterLifecycle(mock)
+	stack.RegisterAPIs(mock.APIs())
+
+	return nil
+}
+
 func (s *Server) setupMetrics(config *TelemetryConfig, serviceName string) error {
 	// Check the global metrics if they're matching with the provided config
 	if metrics.Enabled != config.Enabled || metrics.EnabledExpensive != config.Expensive {
@@ -1,10 +1,14 @@
 package server
 
 import (
+	"fmt"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
+
+	"github.com/ethereum/go-ethereum/common/network"
+	"github.com/ethereum/go-ethereum/consensus/bor"
 )
 
 func TestServer_DeveloperMode(t *testing.T) {
@@ -39,3 +43,40 @@ func TestServer_DeveloperMode(t *testing.T) {
 		}
 	}
 }
+
+func TestServer_DeveloperModeHeimdallMock(t *testing.T) {
+	t.Parallel()
+
+	config := DefaultConfig()
+
+	// enable developer mode along with the mock heimdall
+	config.Developer.Enabled = true
+	config.Developer.Period = 1
+	config.Heimdall.Mock = true
+
+	port, listener, err := network.FindAvailablePort()
+	assert.NoError(t, err)
+	assert.NoError(t, listener.Close())
+
+	config.Heimdall.MockAddress = fmt.Sprintf("127.0.0.1:%d", port)
+
+	server, err := CreateMockServer(config)
+	assert.NoError(t, err)
+
+	defer CloseMockServer(server)
+
+	// The developer chain is a bor chain validated by the developer account
+	assert.IsType(t, &bor.Bor{}, server.backend.Engine())
+	assert.NotNil(t, server.backend.BlockChain().Config().Bor)
+
+	assert.Eventually(t, func() bool {
+		return server.backend.BlockChain().CurrentBlock().Number.Uint64() >= 2
+	}, 20*time.Second, 100*time.Millisecond)
+
+	etherbase, err := server.backend.Etherbase()
+	assert.NoError(t, err)
+
+	author, err := server.backend.Engine().Author(server.backend.BlockChain().CurrentHeader())
+	assert.NoError(t, err)
+	assert.Equal(t, etherbase, author)
+}
@@ -14,12 +14,14 @@ import (
 
 	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/eth"
 	"github.com/ethereum/go-ethereum/eth/tracers"
 	"github.com/ethereum/go-ethereum/eth/tracers/logger"
 	"github.com/ethereum/go-ethereum/internal/cli/server/pprof"
 	"github.com/ethereum/go-ethereum/internal/cli/server/proto"
 	"github.com/ethereum/go-ethereum/p2p"
 	"github.com/ethereum/go-ethereum/p2p/enode"
+	"github.com/ethereum/go-ethereum/rpc"
 )
 
 const chunkSize = 1024 * 1024 * 1024
@@ -177,6 +179,52 @@ func (s *Server) ChainSetHead(ctx context.Context, req *proto.ChainSetHeadReques
 	return &proto.ChainSetHeadResponse{}, nil
 }
 
+func (s *Server) MinerGetConfig(ctx context.Context, req *proto.MinerGetConfigRequest) (*proto.MinerGetConfigResponse, error) {
+	if s.backend == nil {
+		return nil, ErrUnavailable
+	}
+
+	config := eth.NewMinerAPI(s.backend).GetConfig()
+
+	resp := &proto.MinerGetConfigResponse{
+		Etherbase:           config.Etherbase.String(),
+		ExtraData:           config.ExtraData,
+		GasFloor:            config.GasFloor,
+		GasCeil:             config.GasCeil,
+		Recommit:            config.Recommit.Milliseconds(),
+		MaxMergedBundles:    config.MaxMergedBundles,
+		DisableBundles:      config.DisableBundles,
+		CommitInterruptFlag: config.CommitInterruptFlag,
+		NewPayloadTimeout:   config.NewPayloadTimeout.Milliseconds(),
+	}
+
+	if config.GasPrice != nil {
+		resp.GasPrice = config.GasPrice.String()
+	}
+
+	return resp, nil
+}
+
+func (s *Server) MinerSetMaxMergedBundles(ctx context.Context, req *proto.MinerSetMaxMergedBundlesRequest) (*proto.MinerSetMaxMergedBundlesResponse, error) {
+	if s.backend == nil {
+		return nil, ErrUnavailable
+	}
+
+	s.backend.Miner().SetMaxMergedBundles(req.MaxMergedBundles)
+
+	return &proto.MinerSetMaxMergedBundlesResponse{}, nil
+}
+
+func (s *Server) MinerSetBundles(ctx context.Context, req *proto.MinerSetBundlesRequest) (*proto.MinerSetBundlesResponse, error) {
+	if s.backend == nil {
+		return nil, ErrUnavailable
+	}
+
+	s.backend.Miner().SetBundlesEnabled(req.Enabled)
+
+	return &proto.MinerSetBundlesResponse{}, nil
+}
+
 func (s *Server) Status(ctx context.Context, in *proto.StatusRequest) (*proto.StatusResponse, error) {
 	if s.backend == nil && !in.Wait {
 		return nil, ErrUnavailable
@@ -218,6 +266,10 @@ func (s *Server) Status(ctx context.Context, in *proto.StatusRequest) (*proto.St
 		Forks: gatherForks(s.config.chain.Genesis.Config, s.config.chain.Genesis.Config.Bor),
 	}
 
+	if s.readiness != nil {
+		resp.Warnings = s.readiness.Warnings()
+	}
+
 	return resp, nil
 }
 
@@ -256,6 +308,45 @@ func (s *Server) DebugBlock(req *proto.DebugBlockRequest, stream proto.Bor_Debug
 	return nil
 }
 
+func (s *Server) DebugParallelStats(ctx context.Context, req *proto.DebugParallelStatsRequest) (*proto.DebugParallelStatsResponse, error) {
+	if s.backend == nil {
+		return nil, ErrUnavailable
+	}
+
+	number := rpc.LatestBlockNumber
+	if req.Number >= 0 {
+		number = rpc.BlockNumber(req.Number)
+	}
+
+	stats, err := eth.NewDebugAPI(s.backend).GetParallelExecutionStats(ctx, rpc.BlockNumberOrHashWithNumber(number))
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &proto.DebugParallelStatsResponse{
+		Number:               stats.Number,
+		Hash:                 stats.Hash.String(),
+		Transactions:         uint64(stats.Transactions),
+		Metadata:             stats.Metadata,
+		Executions:           uint64(stats.Executions),
+		Reexecutions:         uint64(stats.Reexecutions),
+		Aborts:               uint64(stats.Aborts),
+		ValidationFailures:   uint64(stats.ValidationFailures),
+		LongestPathTime:      int64(stats.LongestPathTime),
+		SerialTime:           int64(stats.SerialTime),
+		ExecutionTime:        int64(stats.ExecutionTime),
+		SpeculativeProcesses: uint64(stats.SpeculativeProcesses),
+		Utilisation:          stats.Utilisation,
+		EstimatedSpeedup:     stats.EstimatedSpeedup,
+	}
+
+	for _, tx := range stats.LongestPath {
+		resp.LongestPath = append(resp.LongestPath, uint64(tx))
+	}
+
+	return resp, nil
+}
+
 var bigIntT = reflect.TypeOf(new(big.Int)).Kind()
 
 // gatherForks gathers all the fork numbers via reflection
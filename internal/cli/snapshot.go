@@ -26,6 +26,8 @@ func (a *SnapshotCommand) MarkDown() string {
 		"# snapshot",
 		"The ```snapshot``` command groups snapshot related actions:",
 		"- [```snapshot prune-state```](./snapshot_prune-state.md): Prune state databases at the given datadir location.",
+		"- [```snapshot bor-export```](./snapshot_bor-export.md): Export a stored Bor snapshot to a file.",
+		"- [```snapshot bor-import```](./snapshot_bor-import.md): Import a Bor snapshot from a file.",
 	}
 
 	return strings.Join(items, "\n\n")
@@ -39,7 +41,12 @@ func (c *SnapshotCommand) Help() string {
 
   Prune the state trie:
 
-    $ bor snapshot prune-state`
+    $ bor snapshot prune-state
+
+  Export the latest Bor snapshot and import it in another node:
+
+    $ bor snapshot bor-export --datadir <datadir> <file>
+    $ bor snapshot bor-import --datadir <datadir> <file>`
 }
 
 // Synopsis implements the cli.Command interface
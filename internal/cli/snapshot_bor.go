@@ -0,0 +1,228 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/consensus/bor"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/internal/cli/flagset"
+	"github.com/ethereum/go-ethereum/internal/cli/server"
+	"github.com/ethereum/go-ethereum/node"
+)
+
+// openChainDB opens the chain database of the given datadir, returning it along
+// with the node to close once done.
+func openChainDB(datadir string, ancient string, readonly bool) (*node.Node, ethdb.Database, error) {
+	if datadir == "" {
+		return nil, nil, fmt.Errorf("datadir is required")
+	}
+
+	stack, err := node.New(&node.Config{
+		DataDir: datadir,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	dbHandles, err := server.MakeDatabaseHandles(0)
+	if err != nil {
+		stack.Close()
+		return nil, nil, err
+	}
+
+	chaindb, err := stack.OpenDatabaseWithFreezer(chaindataPath, 0, dbHandles, ancient, "", readonly)
+	if err != nil {
+		stack.Close()
+		return nil, nil, err
+	}
+
+	return stack, chaindb, nil
+}
+
+// SnapshotBorExportCommand is the command to export a Bor snapshot
+type SnapshotBorExportCommand struct {
+	*Meta
+
+	datadirAncient string
+	number         uint64
+}
+
+// MarkDown implements cli.MarkDown interface
+func (c *SnapshotBorExportCommand) MarkDown() string {
+	items := []string{
+		"# Snapshot bor-export",
+		"The ```bor snapshot bor-export <file>``` command exports the Bor snapshot (validator set and recent signers) stored at a checkpoint block to a file. The file can be imported in another node with ```bor snapshot bor-import``` to seed its validator set without replaying the headers from genesis.",
+		"## Arguments",
+		"- ```file```: Path of the file to write.",
+		c.Flags().MarkDown(),
+	}
+
+	return strings.Join(items, "\n\n")
+}
+
+// Help implements the cli.Command interface
+func (c *SnapshotBorExportCommand) Help() string {
+	return `Usage: bor snapshot bor-export [--number <block>] <file>
+
+  This command exports a stored Bor snapshot to a file` + c.Flags().Help()
+}
+
+// Synopsis implements the cli.Command interface
+func (c *SnapshotBorExportCommand) Synopsis() string {
+	return "Export a Bor snapshot"
+}
+
+// Flags: datadir, datadir.ancient, number
+func (c *SnapshotBorExportCommand) Flags() *flagset.Flagset {
+	flags := c.NewFlagSet("bor-export")
+
+	flags.StringFlag(&flagset.StringFlag{
+		Name:    "datadir.ancient",
+		Value:   &c.datadirAncient,
+		Usage:   "Path of the ancient data directory to store information",
+		Default: "",
+	})
+
+	flags.Uint64Flag(&flagset.Uint64Flag{
+		Name:  "number",
+		Value: &c.number,
+		Usage: "Checkpoint block of the snapshot to export, defaults to the latest one stored",
+	})
+
+	return flags
+}
+
+// Run implements the cli.Command interface
+func (c *SnapshotBorExportCommand) Run(args []string) int {
+	flags := c.Flags()
+
+	if err := flags.Parse(args); err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+
+	args = flags.Args()
+	if len(args) != 1 {
+		c.UI.Error("No snapshot file provided")
+		return 1
+	}
+
+	stack, chaindb, err := openChainDB(c.dataDir, c.datadirAncient, true)
+	if err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+	defer stack.Close()
+	defer chaindb.Close()
+
+	number := c.number
+	if number == 0 {
+		if number, err = bor.LatestSnapshot(chaindb); err != nil {
+			c.UI.Error(err.Error())
+			return 1
+		}
+	}
+
+	blob, err := bor.ExportSnapshot(chaindb, number)
+	if err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+
+	if err := os.WriteFile(args[0], blob, 0600); err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+
+	c.UI.Output(fmt.Sprintf("Exported the snapshot of block %d to %s", number, args[0]))
+
+	return 0
+}
+
+// SnapshotBorImportCommand is the command to import a Bor snapshot
+type SnapshotBorImportCommand struct {
+	*Meta
+
+	datadirAncient string
+}
+
+// MarkDown implements cli.MarkDown interface
+func (c *SnapshotBorImportCommand) MarkDown() string {
+	items := []string{
+		"# Snapshot bor-import",
+		"The ```bor snapshot bor-import <file>``` command imports a Bor snapshot exported with ```bor snapshot bor-export```. The snapshot must be taken at a checkpoint block whose header the node already has, and its validator set must match the one announced by the preceding sprint end header. The node then resumes the validator set from the snapshot instead of replaying the headers.",
+		"## Arguments",
+		"- ```file```: Path of the file to read.",
+		c.Flags().MarkDown(),
+	}
+
+	return strings.Join(items, "\n\n")
+}
+
+// Help implements the cli.Command interface
+func (c *SnapshotBorImportCommand) Help() string {
+	return `Usage: bor snapshot bor-import <file>
+
+  This command imports a Bor snapshot from a file` + c.Flags().Help()
+}
+
+// Synopsis implements the cli.Command interface
+func (c *SnapshotBorImportCommand) Synopsis() string {
+	return "Import a Bor snapshot"
+}
+
+// Flags: datadir, datadir.ancient
+func (c *SnapshotBorImportCommand) Flags() *flagset.Flagset {
+	flags := c.NewFlagSet("bor-import")
+
+	flags.StringFlag(&flagset.StringFlag{
+		Name:    "datadir.ancient",
+		Value:   &c.datadirAncient,
+		Usage:   "Path of the ancient data directory to store information",
+		Default: "",
+	})
+
+	return flags
+}
+
+// Run implements the cli.Command interface
+func (c *SnapshotBorImportCommand) Run(args []string) int {
+	flags := c.Flags()
+
+	if err := flags.Parse(args); err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+
+	args = flags.Args()
+	if len(args) != 1 {
+		c.UI.Error("No snapshot file provided")
+		return 1
+	}
+
+	blob, err := os.ReadFile(args[0])
+	if err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+
+	stack, chaindb, err := openChainDB(c.dataDir, c.datadirAncient, false)
+	if err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+	defer stack.Close()
+	defer chaindb.Close()
+
+	snap, err := bor.ImportSnapshot(chaindb, blob)
+	if err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+
+	c.UI.Output(fmt.Sprintf("Imported the snapshot of block %d (%s) with %d validators", snap.Number, snap.Hash, len(snap.ValidatorSet.Validators)))
+
+	return 0
+}
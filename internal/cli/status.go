@@ -34,6 +34,7 @@ func (p *StatusCommand) MarkDown() string {
 	items := []string{
 		"# Status",
 		"The ```status``` command outputs the status of the client.",
+		"When sealing a Bor chain, it includes the warnings of the readiness checks run ahead of the sprints of the signer: peer count, Heimdall reachability, keystore unlock status and clock drift.",
 	}
 
 	return strings.Join(items, "\n\n")
@@ -111,5 +112,9 @@ func printStatus(status *proto.StatusResponse) string {
 		formatList(forks),
 	}
 
+	if len(status.Warnings) > 0 {
+		full = append(full, "\nWarnings", strings.Join(status.Warnings, "\n"))
+	}
+
 	return strings.Join(full, "\n")
 }
@@ -2781,10 +2781,12 @@ type SendBundleArgs struct {
 	MinTimestamp      *uint64         `json:"minTimestamp"`
 	MaxTimestamp      *uint64         `json:"maxTimestamp"`
 	RevertingTxHashes []common.Hash   `json:"revertingTxHashes"`
+	TargetTxHash      *common.Hash    `json:"targetTxHash"`
 }
 
 // SendBundle will add the signed transaction to the transaction pool.
-// The sender is responsible for signing the transaction and using the correct nonce and ensuring validity
+// The sender is responsible for signing the transaction and using the correct nonce and ensuring validity.
+// Bundles with a target transaction are placed right after it and dropped if it is not included.
 func (s *PrivateTxBundleAPI) SendBundle(ctx context.Context, args SendBundleArgs) error {
 	var txs types.Transactions
 	if len(args.Txs) == 0 {
@@ -2810,7 +2812,7 @@ func (s *PrivateTxBundleAPI) SendBundle(ctx context.Context, args SendBundleArgs
 		maxTimestamp = *args.MaxTimestamp
 	}
 
-	return s.b.SendBundle(ctx, txs, args.BlockNumber, minTimestamp, maxTimestamp, args.RevertingTxHashes)
+	return s.b.SendBundle(ctx, txs, args.BlockNumber, minTimestamp, maxTimestamp, args.RevertingTxHashes, args.TargetTxHash)
 }
 
 // CallBundleArgs represents the arguments for a call.
@@ -114,7 +114,7 @@ type Backend interface {
 	PurgeWhitelistedMilestone()
 
 	// MEV related APIs
-	SendBundle(ctx context.Context, txs types.Transactions, blockNumber rpc.BlockNumber, minTimestamp uint64, maxTimestamp uint64, revertingTxHashes []common.Hash) error
+	SendBundle(ctx context.Context, txs types.Transactions, blockNumber rpc.BlockNumber, minTimestamp uint64, maxTimestamp uint64, revertingTxHashes []common.Hash, targetTxHash *common.Hash) error
 }
 
 func GetAPIs(apiBackend Backend, chain *core.BlockChain) []rpc.API {
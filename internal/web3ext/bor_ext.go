@@ -71,6 +71,58 @@ web3._extend({
 			params: 2,
 			inputFormatter: [null]
 		}),
+		new web3._extend.Method({
+			name: 'getStateSyncEvents',
+			call: 'bor_getStateSyncEvents',
+			params: 1,
+			inputFormatter: [web3._extend.formatters.inputBlockNumberFormatter]
+		}),
+		new web3._extend.Method({
+			name: 'simulateStateSync',
+			call: 'bor_simulateStateSync',
+			params: 2,
+			inputFormatter: [null, web3._extend.formatters.inputBlockNumberFormatter]
+		}),
+		new web3._extend.Method({
+			name: 'getSpan',
+			call: 'bor_getSpan',
+			params: 1
+		}),
+		new web3._extend.Method({
+			name: 'getSpanByBlock',
+			call: 'bor_getSpanByBlock',
+			params: 1,
+			inputFormatter: [web3._extend.formatters.inputBlockNumberFormatter]
+		}),
+		new web3._extend.Method({
+			name: 'getValidatorSetDiff',
+			call: 'bor_getValidatorSetDiff',
+			params: 2,
+			inputFormatter: [web3._extend.formatters.inputBlockNumberFormatter, web3._extend.formatters.inputBlockNumberFormatter]
+		}),
+		new web3._extend.Method({
+			name: 'getValidatorStats',
+			call: 'bor_getValidatorStats',
+			params: 2,
+			inputFormatter: [web3._extend.formatters.inputBlockNumberFormatter, web3._extend.formatters.inputBlockNumberFormatter]
+		}),
+		new web3._extend.Method({
+			name: 'getProducerStats',
+			call: 'bor_getProducerStats',
+			params: 0
+		}),
+		new web3._extend.Method({
+			name: 'getMilestoneHistory',
+			call: 'bor_getMilestoneHistory',
+			params: 1,
+			inputFormatter: [null]
+		}),
+		new web3._extend.Method({
+			name: 'getCheckpointHistory',
+			call: 'bor_getCheckpointHistory',
+			params: 1,
+			inputFormatter: [null]
+		}),
 	]
 });
 `
@@ -535,6 +535,17 @@ web3._extend({
 			call: 'debug_purgeWhitelistedMilestone',
 			params: 0,
 		}),
+		new web3._extend.Method({
+			name: 'getParallelExecutionStats',
+			call: 'debug_getParallelExecutionStats',
+			params: 1,
+			inputFormatter: [web3._extend.formatters.inputBlockNumberFormatter],
+		}),
+		new web3._extend.Method({
+			name: 'getReorgs',
+			call: 'debug_getReorgs',
+			params: 1,
+		}),
 		new web3._extend.Method({
 			name: 'getTraceStack',
 			call: 'debug_getTraceStack',
@@ -758,6 +769,24 @@ web3._extend({
 			name: 'getHashrate',
 			call: 'miner_getHashrate'
 		}),
+		new web3._extend.Method({
+			name: 'setMaxMergedBundles',
+			call: 'miner_setMaxMergedBundles',
+			params: 1,
+			inputFormatter: [web3._extend.utils.fromDecimal]
+		}),
+		new web3._extend.Method({
+			name: 'enableBundles',
+			call: 'miner_enableBundles'
+		}),
+		new web3._extend.Method({
+			name: 'disableBundles',
+			call: 'miner_disableBundles'
+		}),
+		new web3._extend.Method({
+			name: 'getConfig',
+			call: 'miner_getConfig'
+		}),
 	],
 	properties: []
 });
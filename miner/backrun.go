@@ -0,0 +1,184 @@
+package miner
+
+import (
+	"context"
+	"errors"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/blockstm"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
+)
+
+var (
+	errBackrunReverted = errors.New("backrun transaction reverted")
+
+	// metrics to track the bundles placed after their target transaction
+	backrunIncludedCounter = metrics.NewRegisteredCounter("worker/bundles/backrun/included", nil)
+	backrunFailedMeter     = metrics.NewRegisteredMeter("worker/bundles/backrun/failed", nil)
+	backrunDroppedMeter    = metrics.NewRegisteredMeter("worker/bundles/backrun/dropped", nil)
+)
+
+// depsRecorder tracks the block-stm dependencies of a committed transaction.
+type depsRecorder func(index int, readList []blockstm.ReadDescriptor, fullWriteList []blockstm.WriteDescriptor, readMap map[blockstm.Key]blockstm.ReadDescriptor)
+
+// splitBackruns separates the top-of-block bundles from the bundles backrunning
+// a pending transaction, which are grouped by the hash of their target.
+func splitBackruns(bundles []types.MevBundle) ([]types.MevBundle, map[common.Hash][]types.MevBundle) {
+	var (
+		top      = make([]types.MevBundle, 0, len(bundles))
+		backruns = make(map[common.Hash][]types.MevBundle)
+	)
+
+	for _, bundle := range bundles {
+		if bundle.TargetTxHash == nil {
+			top = append(top, bundle)
+			continue
+		}
+
+		backruns[*bundle.TargetTxHash] = append(backruns[*bundle.TargetTxHash], bundle)
+	}
+
+	return top, backruns
+}
+
+// dropBackruns discards the backrun bundles whose target was not included in
+// the block.
+func (w *worker) dropBackruns(env *environment) {
+	dropped := 0
+	for _, bundles := range env.backruns {
+		dropped += len(bundles)
+	}
+
+	if dropped > 0 {
+		backrunDroppedMeter.Mark(int64(dropped))
+		log.Debug("Dropped backrun bundles without included target", "number", env.header.Number, "count", dropped)
+	}
+
+	env.backruns = nil
+}
+
+// commitBackruns commits the bundles backrunning the given transaction right
+// after it. Every bundle is applied atomically: a bundle failing to apply is
+// reverted and the next one is tried.
+func (w *worker) commitBackruns(env *environment, target common.Hash, interruptCtx context.Context, track depsRecorder) []*types.Log {
+	bundles := env.backruns[target]
+	delete(env.backruns, target)
+
+	var coalescedLogs []*types.Log
+
+	for _, bundle := range bundles {
+		logs, err := w.commitBackrun(env, bundle, interruptCtx, track)
+		if err != nil {
+			backrunFailedMeter.Mark(1)
+			log.Debug("Failed to commit backrun bundle", "target", target, "err", err)
+
+			continue
+		}
+
+		coalescedLogs = append(coalescedLogs, logs...)
+		backrunIncludedCounter.Inc(1)
+	}
+
+	return coalescedLogs
+}
+
+// commitBackrun applies a single backrun bundle, reverting the environment if
+// any of its transactions fails.
+func (w *worker) commitBackrun(env *environment, bundle types.MevBundle, interruptCtx context.Context, track depsRecorder) ([]*types.Log, error) {
+	type txDeps struct {
+		readList      []blockstm.ReadDescriptor
+		fullWriteList []blockstm.WriteDescriptor
+		readMap       map[blockstm.Key]blockstm.ReadDescriptor
+	}
+
+	var (
+		backup   = env.state.Copy() // The state is finalised after every transaction
+		tcount   = env.tcount
+		txCount  = len(env.txs)
+		gasUsed  = env.header.GasUsed
+		profit   = new(big.Int).Set(env.profit)
+		blockGas = env.gasPool
+
+		ethSentToCoinbase = new(big.Int)
+		coalescedLogs     []*types.Log
+		deps              []txDeps
+	)
+
+	// Apply the bundle against its share of the block gas
+	gasLimit := w.bundleGasLimit(env)
+	if w.config.MaxBundleGas > 0 && w.config.MaxBundleGas < gasLimit {
+		gasLimit = w.config.MaxBundleGas
+	}
+
+	env.gasPool = new(core.GasPool).AddGas(gasLimit)
+
+	revert := func() {
+		env.state.StopPrefetcher()
+		env.state = backup
+		env.gasPool = blockGas
+		env.tcount = tcount
+		env.txs = env.txs[:txCount]
+		env.receipts = env.receipts[:txCount]
+		env.header.GasUsed = gasUsed
+		env.profit = profit
+
+		if track != nil {
+			env.state.AddEmptyMVHashMap()
+		}
+	}
+
+	for _, tx := range bundle.Txs {
+		env.state.SetTxContext(tx.Hash(), env.tcount)
+		coinbaseBalanceBefore := env.state.GetBalance(env.coinbase)
+
+		logs, err := w.commitTransaction(env, tx, interruptCtx)
+		if err != nil {
+			revert()
+			return nil, err
+		}
+
+		receipt := env.receipts[len(env.receipts)-1]
+		if receipt.Status == types.ReceiptStatusFailed && !containsHash(bundle.RevertingTxHashes, receipt.TxHash) {
+			revert()
+			return nil, errBackrunReverted
+		}
+
+		gasPrice, err := tx.EffectiveGasTip(env.header.BaseFee)
+		if err != nil {
+			revert()
+			return nil, err
+		}
+
+		gasFees := new(big.Int).Mul(new(big.Int).SetUint64(receipt.GasUsed), gasPrice)
+		coinbaseDelta := new(big.Int).Sub(env.state.GetBalance(env.coinbase), coinbaseBalanceBefore)
+		ethSentToCoinbase.Add(ethSentToCoinbase, coinbaseDelta.Sub(coinbaseDelta, gasFees))
+
+		coalescedLogs = append(coalescedLogs, logs...)
+		env.tcount++
+
+		if track != nil {
+			deps = append(deps, txDeps{env.state.MVReadList(), env.state.MVFullWriteList(), env.state.MVReadMap()})
+			env.state.ClearReadMap()
+			env.state.ClearWriteMap()
+		}
+	}
+
+	used := gasLimit - env.gasPool.Gas()
+
+	env.gasPool = blockGas
+	_ = env.gasPool.SubGas(used)
+	env.bundleGasUsed += used
+	env.profit.Add(env.profit, ethSentToCoinbase)
+
+	for i, dep := range deps {
+		track(tcount+i, dep.readList, dep.fullWriteList, dep.readMap)
+	}
+
+	bundleGasUsedHistogram.Update(int64(used))
+
+	return coalescedLogs, nil
+}
@@ -0,0 +1,91 @@
+package miner
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/ethash"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/event"
+)
+
+func TestBackrunPlacement(t *testing.T) {
+	t.Parallel()
+
+	var (
+		db     = rawdb.NewMemoryDatabase()
+		engine = ethash.NewFaker()
+	)
+
+	defer engine.Close()
+
+	b := newTestWorkerBackend(t, ethashChainConfig, engine, db)
+
+	w := newWorker(testConfig, ethashChainConfig, engine, b, new(event.TypeMux), nil, false, &flashbotsData{isFlashbots: true, maxMergedBundles: 1})
+	defer w.close()
+
+	target := b.newRandomTxWithNonce(false, 0)
+	if errs := b.txPool.Add([]*types.Transaction{target}, true, true); errs[0] != nil {
+		t.Fatalf("failed to add transaction: %v", errs[0])
+	}
+
+	var (
+		targetHash = target.Hash()
+		missing    = common.Hash{0x01}
+		backrun    = b.newRandomTxWithNonce(false, 1)
+	)
+
+	bundles, backruns := splitBackruns([]types.MevBundle{
+		{Txs: types.Transactions{b.newRandomTxWithNonce(false, 0)}},
+		{Txs: types.Transactions{b.newRandomTxWithNonce(false, 5)}, TargetTxHash: &targetHash},
+		{Txs: types.Transactions{b.newRandomTxWithNonce(false, 1), b.newRandomTxWithNonce(false, 7)}, TargetTxHash: &targetHash},
+		{Txs: types.Transactions{backrun}, TargetTxHash: &targetHash},
+		{Txs: types.Transactions{b.newRandomTxWithNonce(false, 2)}, TargetTxHash: &missing},
+	})
+
+	if len(bundles) != 1 {
+		t.Fatalf("top-of-block bundles mismatch: have %d, want 1", len(bundles))
+	}
+
+	if len(backruns[targetHash]) != 3 || len(backruns[missing]) != 1 {
+		t.Fatalf("backrun bundles mismatch: have %d and %d, want 3 and 1", len(backruns[targetHash]), len(backruns[missing]))
+	}
+
+	env, err := w.prepareWork(&generateParams{timestamp: uint64(time.Now().Unix()), coinbase: testUserAddress})
+	if err != nil {
+		t.Fatalf("failed to prepare work: %v", err)
+	}
+	defer env.discard()
+
+	env.backruns = backruns
+
+	locals, _ := splitPending(b.txPool)
+
+	if err := w.commitTransactions(env, newTransactionsByPriceAndNonce(env.signer, locals, env.header.BaseFee), nil, context.Background()); err != nil {
+		t.Fatalf("failed to commit transactions: %v", err)
+	}
+
+	// The failing backruns are reverted, the partially applied one included, and
+	// the valid one placed after its target
+	if len(env.txs) != 2 || len(env.receipts) != 2 || env.tcount != 2 {
+		t.Fatalf("transaction count mismatch: have %d txs, %d receipts, tcount %d, want 2", len(env.txs), len(env.receipts), env.tcount)
+	}
+
+	if env.txs[0].Hash() != targetHash || env.txs[1].Hash() != backrun.Hash() {
+		t.Fatalf("transaction order mismatch: have %x, %x", env.txs[0].Hash(), env.txs[1].Hash())
+	}
+
+	// The bundle whose target was not included is dropped
+	if len(env.backruns) != 1 {
+		t.Fatalf("pending backruns mismatch: have %d, want 1", len(env.backruns))
+	}
+
+	w.dropBackruns(env)
+
+	if env.backruns != nil {
+		t.Fatalf("backruns not dropped")
+	}
+}
@@ -0,0 +1,159 @@
+package miner
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/blockstm"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/metrics"
+)
+
+var (
+	// metrics to track the transactions postponed for conflicting with the block
+	conflictPostponedMeter = metrics.NewRegisteredMeter("worker/conflicts/postponed", nil)
+	conflictResumedMeter   = metrics.NewRegisteredMeter("worker/conflicts/resumed", nil)
+
+	// metrics to track the parallelism of the produced blocks
+	txDependencyLongestPathHistogram = metrics.NewRegisteredHistogram("worker/txDependency/longestPath", nil, metrics.NewExpDecaySample(1028, 0.015))
+)
+
+// conflictSelection postpones the transactions touching accounts written by
+// the transactions already selected for the block, packing the independent
+// transactions first for the block to execute with more parallelism.
+//
+// The accounts a transaction touches are only known once executed, so the
+// sender, the recipient and the access list of the transaction are used as
+// a prediction. A transaction is postponed once at most, and only while the
+// next transactions pay a tip within the fee tolerance of its own.
+type conflictSelection struct {
+	written   map[common.Address]struct{} // Accounts written by the selected transactions
+	feeKeys   map[blockstm.Key]struct{}   // Balances receiving the fees, not conflicting as paid on settlement
+	tolerance uint64                      // Maximum percentage of the tip given up to postpone a transaction
+
+	postponed []*txWithMinerFee        // Postponed transactions, not in the set anymore
+	seen      map[common.Hash]struct{} // Transactions postponed once, not to postpone again
+}
+
+// newConflictSelection creates a selection for the environment, considering
+// the writes of the transactions it already holds.
+func newConflictSelection(env *environment, burntContract common.Address, tolerance uint64) *conflictSelection {
+	s := &conflictSelection{
+		written: make(map[common.Address]struct{}),
+		feeKeys: map[blockstm.Key]struct{}{
+			blockstm.NewSubpathKey(env.coinbase, state.BalancePath):  {},
+			blockstm.NewSubpathKey(burntContract, state.BalancePath): {},
+		},
+		tolerance: tolerance,
+		seen:      make(map[common.Hash]struct{}),
+	}
+
+	if s.tolerance > 100 {
+		s.tolerance = 100
+	}
+
+	for _, writes := range env.depsMVFullWriteList {
+		s.record(writes)
+	}
+
+	return s
+}
+
+// record adds the writes of a transaction selected for the block.
+func (s *conflictSelection) record(writes []blockstm.WriteDescriptor) {
+	for _, write := range writes {
+		if _, ok := s.feeKeys[write.Path]; ok {
+			continue
+		}
+
+		s.written[write.Path.GetAddress()] = struct{}{}
+	}
+}
+
+// conflicts reports whether the transaction is expected to touch the accounts
+// written by the selected transactions.
+func (s *conflictSelection) conflicts(tx *types.Transaction, from common.Address) bool {
+	if _, ok := s.written[from]; ok {
+		return true
+	}
+
+	if to := tx.To(); to != nil {
+		if _, ok := s.written[*to]; ok {
+			return true
+		}
+	}
+
+	for _, tuple := range tx.AccessList() {
+		if _, ok := s.written[tuple.Address]; ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+// postpone takes the best transaction out of the set if it conflicts with the
+// selected ones and was not postponed before, reporting whether it did.
+func (s *conflictSelection) postpone(txs *transactionsByPriceAndNonce, tx *types.Transaction, from common.Address) bool {
+	if _, ok := s.seen[tx.Hash()]; ok || !s.conflicts(tx, from) {
+		return false
+	}
+
+	s.seen[tx.Hash()] = struct{}{}
+	s.postponed = append(s.postponed, txs.Postpone())
+
+	conflictPostponedMeter.Mark(1)
+
+	return true
+}
+
+// resume puts back in the set the postponed transactions whose tip the best
+// transaction of the set doesn't match within the fee tolerance, all of them
+// if the set is empty.
+func (s *conflictSelection) resume(txs *transactionsByPriceAndNonce) {
+	if len(s.postponed) == 0 {
+		return
+	}
+
+	best := txs.PeekFees()
+
+	kept := s.postponed[:0]
+
+	for _, head := range s.postponed {
+		if best != nil && !s.exceedsTolerance(head.fees, best) {
+			kept = append(kept, head)
+			continue
+		}
+
+		txs.Resume(head)
+		conflictResumedMeter.Mark(1)
+	}
+
+	s.postponed = kept
+}
+
+// exceedsTolerance reports whether selecting a transaction paying the given
+// tip rather than the postponed one gives up more than the fee tolerance.
+func (s *conflictSelection) exceedsTolerance(postponed, tip *big.Int) bool {
+	floor := new(big.Int).Mul(postponed, big.NewInt(int64(100-s.tolerance)))
+
+	return new(big.Int).Mul(tip, big.NewInt(100)).Cmp(floor) < 0
+}
+
+// longestDependencyPath returns the number of transactions in the longest
+// chain of dependent transactions of the given block metadata.
+func longestDependencyPath(deps [][]uint64) int {
+	if len(deps) == 0 {
+		return 0
+	}
+
+	stats := make(map[int]blockstm.ExecutionStat, len(deps))
+	for i := range deps {
+		stats[i] = blockstm.ExecutionStat{TxIdx: i, End: 1}
+	}
+
+	path, _ := blockstm.TxDependencyDAG(deps).LongestPath(stats)
+
+	return len(path)
+}
@@ -0,0 +1,152 @@
+package miner
+
+import (
+	"crypto/ecdsa"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/blockstm"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/txpool"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// Tests that the conflicting transactions are postponed behind the independent
+// ones only as long as the fee tolerance allows.
+func TestConflictSelection(t *testing.T) {
+	t.Parallel()
+
+	var (
+		signer = types.LatestSignerForChainID(common.Big1)
+		keyA   = newTestKey(t)
+		keyB   = newTestKey(t)
+		keyC   = newTestKey(t)
+
+		contract = common.HexToAddress("0xc0")
+		other    = common.HexToAddress("0xc1")
+		start    = time.Now()
+	)
+
+	newTx := func(index int, key *keyPair, nonce uint64, to common.Address, tip int64) *txpool.LazyTransaction {
+		tx, err := types.SignTx(types.NewTx(&types.DynamicFeeTx{
+			Nonce:     nonce,
+			To:        &to,
+			Gas:       21000,
+			GasFeeCap: big.NewInt(100),
+			GasTipCap: big.NewInt(tip),
+		}), signer, key.priv)
+		if err != nil {
+			t.Fatalf("failed to sign tx: %v", err)
+		}
+
+		return &txpool.LazyTransaction{
+			Hash:      tx.Hash(),
+			Tx:        tx,
+			Time:      start.Add(time.Duration(index) * time.Second),
+			GasFeeCap: tx.GasFeeCap(),
+			GasTipCap: tx.GasTipCap(),
+			Gas:       tx.Gas(),
+		}
+	}
+
+	var (
+		a0 = newTx(0, keyA, 0, contract, 30)
+		a1 = newTx(1, keyA, 1, other, 30)
+		b0 = newTx(2, keyB, 0, contract, 30)
+		c0 = newTx(3, keyC, 0, other, 29)
+	)
+
+	tests := []struct {
+		tolerance uint64
+		want      []*txpool.LazyTransaction
+	}{
+		// The independent transaction pays less than the conflicting ones
+		{tolerance: 0, want: []*txpool.LazyTransaction{a0, a1, b0, c0}},
+		// The independent transaction pays a tip within the tolerance
+		{tolerance: 10, want: []*txpool.LazyTransaction{a0, c0, a1, b0}},
+	}
+
+	for _, tt := range tests {
+		txs := newTransactionsByPriceAndNonce(signer, map[common.Address][]*txpool.LazyTransaction{
+			keyA.addr: {a0, a1},
+			keyB.addr: {b0},
+			keyC.addr: {c0},
+		}, big.NewInt(0))
+
+		selection := newConflictSelection(&environment{}, common.Address{}, tt.tolerance)
+
+		var have []*txpool.LazyTransaction
+
+		for {
+			selection.resume(txs)
+
+			ltx := txs.Peek()
+			if ltx == nil {
+				break
+			}
+
+			from, _ := types.Sender(signer, ltx.Tx)
+			if selection.postpone(txs, ltx.Tx, from) {
+				continue
+			}
+
+			// The transaction writes the balances of its sender and recipient
+			selection.record([]blockstm.WriteDescriptor{
+				{Path: blockstm.NewSubpathKey(from, state.BalancePath)},
+				{Path: blockstm.NewSubpathKey(*ltx.Tx.To(), state.BalancePath)},
+			})
+
+			have = append(have, ltx)
+			txs.Shift()
+		}
+
+		if len(have) != len(tt.want) {
+			t.Fatalf("tolerance %d: selected %d transactions, want %d", tt.tolerance, len(have), len(tt.want))
+		}
+
+		for i := range have {
+			if have[i].Hash != tt.want[i].Hash {
+				t.Errorf("tolerance %d: transaction %d mismatch: have %x, want %x", tt.tolerance, i, have[i].Hash, tt.want[i].Hash)
+			}
+		}
+	}
+}
+
+func TestLongestDependencyPath(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		deps [][]uint64
+		want int
+	}{
+		{deps: nil, want: 0},
+		{deps: [][]uint64{{}, {}, {}}, want: 1},
+		{deps: [][]uint64{{}, {0}, {1}, {0}}, want: 3},
+		{deps: [][]uint64{{}, {}, {0, 1}, {2}}, want: 3},
+	}
+
+	for i, tt := range tests {
+		if have := longestDependencyPath(tt.deps); have != tt.want {
+			t.Errorf("test %d: longest path mismatch: have %d, want %d", i, have, tt.want)
+		}
+	}
+}
+
+type keyPair struct {
+	priv *ecdsa.PrivateKey
+	addr common.Address
+}
+
+func newTestKey(t *testing.T) *keyPair {
+	t.Helper()
+
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+
+	return &keyPair{priv: key, addr: crypto.PubkeyToAddress(key.PublicKey)}
+}
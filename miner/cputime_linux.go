@@ -0,0 +1,23 @@
+//go:build linux
+
+package miner
+
+import (
+	"time"
+
+	"golang.org/x/sys/unix"
+
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// threadCPUTime retrieves the CPU time consumed by the calling OS thread, the
+// goroutine having to be locked to it for the measure to be meaningful.
+func threadCPUTime() time.Duration {
+	var usage unix.Rusage
+	if err := unix.Getrusage(unix.RUSAGE_THREAD, &usage); err != nil {
+		log.Warn("Failed to retrieve thread CPU time", "err", err)
+		return 0
+	}
+
+	return time.Duration(usage.Utime.Nano() + usage.Stime.Nano())
+}
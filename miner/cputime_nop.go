@@ -0,0 +1,10 @@
+//go:build !linux
+
+package miner
+
+import "time"
+
+// threadCPUTime is not supported on this platform, it always returns 0.
+func threadCPUTime() time.Duration {
+	return 0
+}
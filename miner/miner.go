@@ -18,6 +18,7 @@
 package miner
 
 import (
+	"crypto/ecdsa"
 	"fmt"
 	"math/big"
 	"sync"
@@ -53,8 +54,19 @@ type Config struct {
 	GasPrice            *big.Int       // Minimum gas price for mining a transaction
 	Recommit            time.Duration  // The time interval for miner to re-create mining work.
 	MaxMergedBundles    uint64         // Maximum number of flashbots workers
+	DisableBundles      bool           // Disable building blocks with bundles
+	BundleGasShare      uint64         // Maximum percentage of the block gas limit used by bundles (0 = no limit)
+	MempoolGasReserve   uint64         // Block gas reserved for mempool transactions
+	MaxBundleGas        uint64         // Maximum gas used by a single bundle (0 = no limit)
 	CommitInterruptFlag bool           // Interrupt commit when time is up ( default = true)
 
+	ConflictAwareSelection bool   // Prefer transactions not conflicting with the ones already selected for the block
+	ConflictFeeTolerance   uint64 // Maximum percentage of the tip given up to select a non-conflicting transaction
+
+	BuilderKey       *ecdsa.PrivateKey `toml:"-" json:"-"` // Key collecting the block fees in builder mode, nil to disable builder mode
+	ValidatorPayment common.Address    `toml:",omitempty"` // Recipient of the payment appended to blocks in builder mode
+	PaymentMargin    *big.Int          `toml:",omitempty"` // Part of the block profit kept by the builder in builder mode
+
 	NewPayloadTimeout time.Duration // The maximum time allowance for creating a new payload
 }
 
@@ -258,6 +270,21 @@ func (miner *Miner) SetGasCeil(ceil uint64) {
 	miner.worker.setGasCeil(ceil)
 }
 
+// SetMaxMergedBundles sets the maximum number of bundles merged into a block.
+func (miner *Miner) SetMaxMergedBundles(n uint64) {
+	miner.worker.setMaxMergedBundles(n)
+}
+
+// SetBundlesEnabled enables or disables building blocks with bundles.
+func (miner *Miner) SetBundlesEnabled(enabled bool) {
+	miner.worker.setBundlesEnabled(enabled)
+}
+
+// Config returns a copy of the live miner configuration.
+func (miner *Miner) Config() Config {
+	return miner.worker.currentConfig()
+}
+
 // SubscribePendingLogs starts delivering logs from pending transactions
 // to the given channel.
 func (miner *Miner) SubscribePendingLogs(ch chan<- []*types.Log) event.Subscription {
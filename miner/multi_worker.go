@@ -1,40 +1,87 @@
 package miner
 
 import (
+	"fmt"
+	"math/big"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/consensus"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/txpool"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/event"
 	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
 	"github.com/ethereum/go-ethereum/params"
 )
 
+var (
+	// metrics gauge to track the number of flashbots workers currently alive
+	activeFlashbotsWorkersGauge = metrics.NewRegisteredGauge("worker/flashbots/active", nil)
+)
+
 type multiWorker struct {
-	workers       []*worker
+	mu            sync.RWMutex // The lock used to protect the worker set and the fields below
+	workers       []*worker    // Regular worker followed by the flashbots worker merging i bundles at index i
 	regularWorker *worker
+	recommit      time.Duration // Recommit interval set by the user, applied to newly spawned workers
+
+	maxMergedBundles atomic.Uint64 // Live maximum number of bundles merged into a block
+	bundlesDisabled  atomic.Bool   // Whether building blocks with bundles is disabled
+
+	// Parameters used to spawn flashbots workers on demand
+	config       *Config
+	chainConfig  *params.ChainConfig
+	engine       consensus.Engine
+	eth          Backend
+	mux          *event.TypeMux
+	isLocalBlock func(header *types.Header) bool
+	queue        chan *task
+	pending      *pendingSnapshot
+
+	chainHeadCh  chan core.ChainHeadEvent
+	chainHeadSub event.Subscription
+	updateCh     chan struct{}
+	exitCh       chan struct{}
+	wg           sync.WaitGroup
 }
 
 func (w *multiWorker) stop() {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
 	for _, worker := range w.workers {
 		worker.stop()
 	}
 }
 
 func (w *multiWorker) start() {
-	for _, worker := range w.workers {
-		worker.start()
-	}
+	w.regularWorker.start()
+	w.requestUpdate()
 }
 
 func (w *multiWorker) close() {
+	close(w.exitCh)
+	w.wg.Wait()
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
 	for _, worker := range w.workers {
 		worker.close()
 	}
+
+	w.workers = w.workers[:1]
+	activeFlashbotsWorkersGauge.Update(0)
 }
 
 func (w *multiWorker) IsRunning() bool {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
 	for _, worker := range w.workers {
 		if worker.IsRunning() {
 			return true
@@ -50,24 +97,39 @@ func (w *multiWorker) pendingBlockAndReceipts() (*types.Block, types.Receipts) {
 }
 
 func (w *multiWorker) setGasCeil(ceil uint64) {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
 	for _, worker := range w.workers {
 		worker.setGasCeil(ceil)
 	}
 }
 
 func (w *multiWorker) setExtra(extra []byte) {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
 	for _, worker := range w.workers {
 		worker.setExtra(extra)
 	}
 }
 
 func (w *multiWorker) setRecommitInterval(interval time.Duration) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.recommit = interval
+	w.pending.setMaxAge(interval)
+
 	for _, worker := range w.workers {
 		worker.setRecommitInterval(interval)
 	}
 }
 
 func (w *multiWorker) setEtherbase(addr common.Address) {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
 	for _, worker := range w.workers {
 		worker.setEtherbase(addr)
 	}
@@ -78,34 +140,307 @@ func (w *multiWorker) etherbase() common.Address {
 	return w.regularWorker.etherbase()
 }
 
+// setMaxMergedBundles updates the maximum number of bundles merged into a
+// block, which also bounds the number of flashbots workers.
+func (w *multiWorker) setMaxMergedBundles(n uint64) {
+	w.maxMergedBundles.Store(n)
+	w.requestUpdate()
+}
+
+// setBundlesEnabled starts or stops building blocks with bundles.
+func (w *multiWorker) setBundlesEnabled(enabled bool) {
+	w.bundlesDisabled.Store(!enabled)
+	w.requestUpdate()
+}
+
+// currentConfig returns a copy of the live configuration of the workers.
+func (w *multiWorker) currentConfig() Config {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	w.regularWorker.mu.RLock()
+	config := *w.config
+	config.Etherbase = w.regularWorker.coinbase
+	config.ExtraData = common.CopyBytes(w.regularWorker.extra)
+	w.regularWorker.mu.RUnlock()
+
+	config.MaxMergedBundles = w.maxMergedBundles.Load()
+	config.DisableBundles = w.bundlesDisabled.Load()
+
+	if config.GasPrice != nil {
+		config.GasPrice = new(big.Int).Set(config.GasPrice)
+	}
+
+	if config.PaymentMargin != nil {
+		config.PaymentMargin = new(big.Int).Set(config.PaymentMargin)
+	}
+
+	if w.recommit != 0 {
+		config.Recommit = w.recommit
+	}
+
+	return config
+}
+
+// flashbotsWorkers returns the number of flashbots workers currently alive.
+func (w *multiWorker) flashbotsWorkers() int {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	return len(w.workers) - 1
+}
+
+// requestUpdate asks the governor to re-evaluate the number of flashbots workers.
+func (w *multiWorker) requestUpdate() {
+	select {
+	case w.updateCh <- struct{}{}:
+	default:
+	}
+}
+
+// governLoop is a standalone goroutine which starts and stops flashbots workers
+// based on the number of bundles targeting the next block. A worker merging i
+// bundles is only useful when at least i bundles are available, so the loop
+// keeps exactly min(bundles, MaxMergedBundles) flashbots workers alive.
+func (w *multiWorker) governLoop() {
+	defer w.wg.Done()
+	defer w.chainHeadSub.Unsubscribe()
+
+	ticker := time.NewTicker(minRecommitInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case head := <-w.chainHeadCh:
+			w.updateWorkers(head.Block.Header())
+
+		case <-ticker.C:
+			w.updateWorkers(w.eth.BlockChain().CurrentBlock())
+
+		case <-w.updateCh:
+			w.updateWorkers(w.eth.BlockChain().CurrentBlock())
+
+		case <-w.exitCh:
+			return
+		case <-w.chainHeadSub.Err():
+			return
+		}
+	}
+}
+
+// updateWorkers resizes the flashbots worker set for the block built on top of
+// parent. The workers alive are left untouched unless their target number changes.
+func (w *multiWorker) updateWorkers(parent *types.Header) {
+	target := w.targetWorkers(parent)
+	if target == w.flashbotsWorkers() {
+		return
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	current := len(w.workers) - 1
+	if target == current {
+		return
+	}
+
+	for i := current + 1; i <= target; i++ {
+		w.workers = append(w.workers, w.newFlashbotsWorker(uint64(i)))
+	}
+
+	for i := current; i > target; i-- {
+		w.workers[i].close()
+	}
+
+	w.workers = w.workers[:target+1]
+
+	activeFlashbotsWorkersGauge.Update(int64(target))
+	log.Debug("Updated flashbots workers", "from", current, "to", target)
+}
+
+// targetWorkers returns the number of flashbots workers needed for the block
+// built on top of parent.
+func (w *multiWorker) targetWorkers(parent *types.Header) int {
+	if !w.regularWorker.IsRunning() || w.bundlesDisabled.Load() || parent == nil {
+		return 0
+	}
+
+	timestamp := uint64(time.Now().Unix())
+	if parent.Time >= timestamp {
+		timestamp = parent.Time + 1
+	}
+
+	bundles := w.eth.TxPool().CountMevBundles(new(big.Int).Add(parent.Number, common.Big1), timestamp)
+
+	return int(min(uint64(bundles), w.maxMergedBundles.Load()))
+}
+
+// newFlashbotsWorker creates and starts a flashbots worker merging the given
+// number of bundles, carrying over the live settings of the regular worker.
+// The caller must hold the lock.
+func (w *multiWorker) newFlashbotsWorker(maxMergedBundles uint64) *worker {
+	worker := newWorker(w.config, w.chainConfig, w.engine, w.eth, w.mux, w.isLocalBlock, false, &flashbotsData{
+		isFlashbots:      true,
+		queue:            w.queue,
+		maxMergedBundles: maxMergedBundles,
+		pending:          w.pending,
+	})
+
+	w.regularWorker.mu.RLock()
+	coinbase, extra := w.regularWorker.coinbase, w.regularWorker.extra
+	w.regularWorker.mu.RUnlock()
+
+	worker.setEtherbase(coinbase)
+	worker.setExtra(extra)
+
+	if w.recommit != 0 {
+		worker.setRecommitInterval(w.recommit)
+	}
+
+	if w.regularWorker.IsRunning() {
+		worker.start()
+	}
+
+	return worker
+}
+
 func newMultiWorker(config *Config, chainConfig *params.ChainConfig, engine consensus.Engine, eth Backend, mux *event.TypeMux, isLocalBlock func(header *types.Header) bool, init bool) *multiWorker {
 	queue := make(chan *task)
+	pending := newPendingSnapshot(config.Recommit)
 
 	regularWorker := newWorker(config, chainConfig, engine, eth, mux, isLocalBlock, init, &flashbotsData{
 		isFlashbots: false,
 		queue:       queue,
+		pending:     pending,
 	})
 
-	workers := []*worker{regularWorker}
-
-	for i := uint64(1); i <= config.MaxMergedBundles; i++ {
-		workers = append(workers,
-			newWorker(config, chainConfig, engine, eth, mux, isLocalBlock, init, &flashbotsData{
-				isFlashbots:      true,
-				queue:            queue,
-				maxMergedBundles: i,
-			}))
-	}
-
-	log.Info("creating multi worker", "config.MaxMergedBundles", config.MaxMergedBundles, "worker", len(workers))
-	return &multiWorker{
+	w := &multiWorker{
 		regularWorker: regularWorker,
-		workers:       workers,
+		workers:       []*worker{regularWorker},
+		config:        config,
+		chainConfig:   chainConfig,
+		engine:        engine,
+		eth:           eth,
+		mux:           mux,
+		isLocalBlock:  isLocalBlock,
+		queue:         queue,
+		pending:       pending,
+		chainHeadCh:   make(chan core.ChainHeadEvent, chainHeadChanSize),
+		updateCh:      make(chan struct{}, 1),
+		exitCh:        make(chan struct{}),
 	}
+	w.maxMergedBundles.Store(config.MaxMergedBundles)
+	w.bundlesDisabled.Store(config.DisableBundles)
+
+	w.chainHeadSub = eth.BlockChain().SubscribeChainHeadEvent(w.chainHeadCh)
+
+	w.wg.Add(1)
+	go w.governLoop()
+
+	log.Info("creating multi worker", "config.MaxMergedBundles", config.MaxMergedBundles)
+
+	return w
 }
 
 type flashbotsData struct {
 	isFlashbots      bool
 	queue            chan *task
 	maxMergedBundles uint64
+	pending          *pendingSnapshot // Pending transactions shared between workers, nil if not shared
+}
+
+// name returns a label identifying the worker in logs and metrics.
+func (f *flashbotsData) name() string {
+	if !f.isFlashbots {
+		return "regular"
+	}
+
+	return fmt.Sprintf("flashbots%d", f.maxMergedBundles)
+}
+
+// pendingSnapshot shares one view of the pending transactions between the
+// workers of a multiWorker, so that a recommit round walks the txpool only
+// once instead of once per worker.
+type pendingSnapshot struct {
+	mu     sync.Mutex
+	maxAge time.Duration // Maximum age of a snapshot before it is taken again
+
+	parent  common.Hash // Parent block the snapshot was taken for
+	taken   time.Time
+	locals  map[common.Address][]*txpool.LazyTransaction
+	remotes map[common.Address][]*txpool.LazyTransaction
+}
+
+func newPendingSnapshot(maxAge time.Duration) *pendingSnapshot {
+	if maxAge < minRecommitInterval {
+		maxAge = minRecommitInterval
+	}
+
+	return &pendingSnapshot{maxAge: maxAge}
+}
+
+// setMaxAge updates the maximum age of a snapshot, usually to the recommit interval.
+func (s *pendingSnapshot) setMaxAge(maxAge time.Duration) {
+	if maxAge < minRecommitInterval {
+		maxAge = minRecommitInterval
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.maxAge = maxAge
+}
+
+// get returns the local and remote pending transactions for a block built on
+// top of parent, taking a new snapshot from the pool if the cached one is stale.
+// The returned maps are copies owned by the caller, the transaction lists in
+// them must not be modified.
+func (s *pendingSnapshot) get(pool *txpool.TxPool, parent common.Hash) (map[common.Address][]*txpool.LazyTransaction, map[common.Address][]*txpool.LazyTransaction) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.parent != parent || time.Since(s.taken) >= s.maxAge {
+		s.locals, s.remotes = splitPending(pool)
+		s.parent, s.taken = parent, time.Now()
+	} else {
+		pendingSnapshotHitCounter.Inc(1)
+	}
+
+	return copyPending(s.locals), copyPending(s.remotes)
+}
+
+// splitPending retrieves the pending transactions from the pool and splits
+// them into locals and remotes.
+func splitPending(pool *txpool.TxPool) (map[common.Address][]*txpool.LazyTransaction, map[common.Address][]*txpool.LazyTransaction) {
+	localTxs, remoteTxs := make(map[common.Address][]*txpool.LazyTransaction), pool.Pending(true)
+
+	for _, account := range pool.Locals() {
+		if txs := remoteTxs[account]; len(txs) > 0 {
+			delete(remoteTxs, account)
+
+			localTxs[account] = txs
+		}
+	}
+
+	return localTxs, remoteTxs
+}
+
+// countPending returns the number of the given pending transactions.
+func countPending(pending map[common.Address][]*txpool.LazyTransaction) int {
+	var n int
+	for _, txs := range pending {
+		n += len(txs)
+	}
+
+	return n
+}
+
+// copyPending makes a shallow copy of the given pending transactions.
+func copyPending(pending map[common.Address][]*txpool.LazyTransaction) map[common.Address][]*txpool.LazyTransaction {
+	cpy := make(map[common.Address][]*txpool.LazyTransaction, len(pending))
+	for addr, txs := range pending {
+		cpy[addr] = txs
+	}
+
+	return cpy
 }
@@ -0,0 +1,159 @@
+package miner
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/clique"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/event"
+	"github.com/ethereum/go-ethereum/params"
+	"gotest.tools/assert"
+)
+
+func TestPendingSnapshotSharing(t *testing.T) {
+	t.Parallel()
+
+	var (
+		db     = rawdb.NewMemoryDatabase()
+		engine = clique.New(cliqueChainConfig.Clique, db)
+	)
+
+	defer engine.Close()
+
+	b := newTestWorkerBackend(t, cliqueChainConfig, engine, db)
+	b.txPool.Add(pendingTxs, true, true)
+
+	snapshot := newPendingSnapshot(time.Hour)
+	parent := b.chain.CurrentBlock().Hash()
+
+	locals, remotes := snapshot.get(b.txPool, parent)
+	if len(locals)+len(remotes) != 1 {
+		t.Fatalf("pending accounts mismatch: have %d, want 1", len(locals)+len(remotes))
+	}
+
+	// Mutating the returned maps must not affect the shared snapshot
+	delete(locals, testBankAddress)
+	delete(remotes, testBankAddress)
+
+	b.txPool.Add(newTxs, true, true)
+
+	locals, remotes = snapshot.get(b.txPool, parent)
+	if have := len(locals[testBankAddress]) + len(remotes[testBankAddress]); have != 1 {
+		t.Fatalf("cached pending txs mismatch: have %d, want 1", have)
+	}
+
+	// A new parent invalidates the snapshot
+	locals, remotes = snapshot.get(b.txPool, common.Hash{0x01})
+	if have := len(locals[testBankAddress]) + len(remotes[testBankAddress]); have != 2 {
+		t.Fatalf("refreshed pending txs mismatch: have %d, want 2", have)
+	}
+}
+
+func TestMultiWorkerBundleGovernance(t *testing.T) {
+	t.Parallel()
+
+	var (
+		db     = rawdb.NewMemoryDatabase()
+		engine = clique.New(cliqueChainConfig.Clique, db)
+		config = *testConfig
+	)
+
+	defer engine.Close()
+
+	config.MaxMergedBundles = 2
+	config.Etherbase = testBankAddress
+
+	b := newTestWorkerBackend(t, cliqueChainConfig, engine, db)
+
+	w := newMultiWorker(&config, cliqueChainConfig, engine, b, new(event.TypeMux), nil, false)
+	defer w.close()
+
+	head := b.chain.CurrentBlock()
+
+	// Without mining no flashbots worker is needed
+	w.updateWorkers(head)
+
+	if have := w.flashbotsWorkers(); have != 0 {
+		t.Fatalf("flashbots workers mismatch: have %d, want 0", have)
+	}
+
+	w.regularWorker.running.Store(true)
+
+	signer := types.LatestSigner(params.TestChainConfig)
+	for i := 0; i < 3; i++ {
+		tx := types.MustSignNewTx(testBankKey, signer, &types.LegacyTx{
+			Nonce:    uint64(i),
+			To:       &testUserAddress,
+			Gas:      params.TxGas,
+			GasPrice: b.chain.CurrentBlock().BaseFee,
+		})
+		if err := b.txPool.AddMevBundle(types.Transactions{tx}, common.Big1, 0, 0, nil, nil); err != nil {
+			t.Fatalf("failed to add bundle: %v", err)
+		}
+	}
+
+	// The number of workers is capped by MaxMergedBundles
+	w.updateWorkers(head)
+
+	if have := w.flashbotsWorkers(); have != 2 {
+		t.Fatalf("flashbots workers mismatch: have %d, want 2", have)
+	}
+
+	// The workers alive are kept while their number doesn't change
+	workers := append([]*worker{}, w.workers...)
+
+	w.updateWorkers(head)
+
+	for i := range workers {
+		if w.workers[i] != workers[i] {
+			t.Fatalf("flashbots worker %d respawned", i)
+		}
+	}
+
+	// Bundles targeting other blocks do not spawn workers
+	w.updateWorkers(&types.Header{Number: common.Big1, Time: head.Time + 1})
+
+	if have := w.flashbotsWorkers(); have != 0 {
+		t.Fatalf("flashbots workers mismatch: have %d, want 0", have)
+	}
+}
+
+func TestMultiWorkerRuntimeConfig(t *testing.T) {
+	t.Parallel()
+
+	var (
+		db     = rawdb.NewMemoryDatabase()
+		engine = clique.New(cliqueChainConfig.Clique, db)
+		config = *testConfig
+	)
+
+	defer engine.Close()
+
+	config.MaxMergedBundles = 1
+
+	b := newTestWorkerBackend(t, cliqueChainConfig, engine, db)
+
+	w := newMultiWorker(&config, cliqueChainConfig, engine, b, new(event.TypeMux), nil, false)
+	defer w.close()
+
+	w.setEtherbase(testUserAddress)
+	w.setExtra([]byte{0x01})
+	w.setMaxMergedBundles(4)
+	w.setBundlesEnabled(false)
+
+	live := w.currentConfig()
+
+	assert.Equal(t, testUserAddress, live.Etherbase)
+	assert.DeepEqual(t, []byte{0x01}, []byte(live.ExtraData))
+	assert.Equal(t, uint64(4), live.MaxMergedBundles)
+	assert.Equal(t, true, live.DisableBundles)
+
+	// Disabled bundles never spawn flashbots workers
+	w.regularWorker.running.Store(true)
+	w.updateWorkers(b.chain.CurrentBlock())
+
+	assert.Equal(t, 0, w.flashbotsWorkers())
+}
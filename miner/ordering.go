@@ -149,3 +149,23 @@ func (t *transactionsByPriceAndNonce) GetTxs() int {
 func (t *transactionsByPriceAndNonce) Pop() {
 	heap.Pop(&t.heads)
 }
+
+// PeekFees returns the miner fees of the next transaction by price.
+func (t *transactionsByPriceAndNonce) PeekFees() *big.Int {
+	if len(t.heads) == 0 {
+		return nil
+	}
+	return t.heads[0].fees
+}
+
+// Postpone removes the best transaction, keeping the next ones from the same
+// account for it to be resumed later on.
+func (t *transactionsByPriceAndNonce) Postpone() *txWithMinerFee {
+	return heap.Pop(&t.heads).(*txWithMinerFee)
+}
+
+// Resume puts back a postponed transaction, followed by the next ones from the
+// same account.
+func (t *transactionsByPriceAndNonce) Resume(head *txWithMinerFee) {
+	heap.Push(&t.heads, head)
+}
@@ -0,0 +1,127 @@
+package miner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus"
+	"github.com/ethereum/go-ethereum/consensus/bor"
+	"github.com/ethereum/go-ethereum/consensus/clique"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+var (
+	errPaymentToContract = errors.New("validator payment address is a contract")
+	errNoBuilderProfit   = errors.New("builder profit does not cover the validator payment")
+
+	// metrics to track the validator payments appended by the builder
+	validatorPaymentCounter   = metrics.NewRegisteredCounter("worker/builder/payments", nil)
+	validatorPaymentSkipMeter = metrics.NewRegisteredMeter("worker/builder/skipped", nil)
+)
+
+// builderAddress returns the address of the builder key, or the zero address
+// if builder mode is disabled.
+func (w *worker) builderAddress() common.Address {
+	if w.config.BuilderKey == nil {
+		return common.Address{}
+	}
+
+	return crypto.PubkeyToAddress(w.config.BuilderKey.PublicKey)
+}
+
+// isBuilder reports whether blocks built for the given coinbase collect the
+// fees with the builder key and pay the validator at the end of the block.
+func (w *worker) isBuilder(coinbase common.Address) bool {
+	return w.config.BuilderKey != nil && coinbase == w.builderAddress()
+}
+
+// SupportsBuilderMode reports whether the consensus engine credits the block
+// fees to the header coinbase. Engines crediting the block signer would reject
+// any block whose fees were collected by a separate builder key.
+func SupportsBuilderMode(engine consensus.Engine) bool {
+	switch engine.(type) {
+	case *bor.Bor, *clique.Clique:
+		return false
+	default:
+		return true
+	}
+}
+
+// commitValidatorPayment appends a transaction from the builder to the validator
+// paying the profit the builder made in this block minus the configured margin.
+// The block value reported for the environment is the value of that payment.
+func (w *worker) commitValidatorPayment(env *environment) error {
+	// Release the gas reserved for the payment in makeEnv
+	env.gasPool.AddGas(params.TxGas)
+
+	env.profit = new(big.Int)
+
+	validator := w.config.ValidatorPayment
+	if env.state.GetCodeSize(validator) > 0 {
+		return errPaymentToContract
+	}
+
+	gasPrice := new(big.Int)
+	if env.header.BaseFee != nil {
+		gasPrice.Set(env.header.BaseFee)
+	}
+
+	value := new(big.Int).Sub(env.state.GetBalance(env.coinbase), env.builderBalance)
+	value.Sub(value, new(big.Int).Mul(gasPrice, new(big.Int).SetUint64(params.TxGas)))
+
+	if w.config.PaymentMargin != nil {
+		value.Sub(value, w.config.PaymentMargin)
+	}
+
+	if value.Sign() <= 0 {
+		validatorPaymentSkipMeter.Mark(1)
+		return errNoBuilderProfit
+	}
+
+	var txdata types.TxData
+	if env.header.BaseFee != nil {
+		txdata = &types.DynamicFeeTx{
+			ChainID:   w.chainConfig.ChainID,
+			Nonce:     env.state.GetNonce(env.coinbase),
+			GasTipCap: new(big.Int),
+			GasFeeCap: gasPrice,
+			Gas:       params.TxGas,
+			To:        &validator,
+			Value:     value,
+		}
+	} else {
+		txdata = &types.LegacyTx{
+			Nonce:    env.state.GetNonce(env.coinbase),
+			GasPrice: gasPrice,
+			Gas:      params.TxGas,
+			To:       &validator,
+			Value:    value,
+		}
+	}
+
+	tx, err := types.SignNewTx(w.config.BuilderKey, env.signer, txdata)
+	if err != nil {
+		return fmt.Errorf("failed to sign validator payment: %w", err)
+	}
+
+	env.state.SetTxContext(tx.Hash(), env.tcount)
+
+	if _, err := w.commitTransaction(env, tx, context.Background()); err != nil {
+		return fmt.Errorf("failed to apply validator payment: %w", err)
+	}
+
+	env.tcount++
+	env.profit = value
+
+	validatorPaymentCounter.Inc(1)
+	log.Debug("Committed validator payment", "number", env.header.Number, "validator", validator, "value", value)
+
+	return nil
+}
@@ -0,0 +1,160 @@
+package miner
+
+import (
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/ethash"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/event"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+func TestValidatorPayment(t *testing.T) {
+	t.Parallel()
+
+	var (
+		db        = rawdb.NewMemoryDatabase()
+		engine    = ethash.NewFaker()
+		config    = *testConfig
+		validator = common.HexToAddress("0x1000000000000000000000000000000000000001")
+		margin    = big.NewInt(params.GWei)
+	)
+
+	defer engine.Close()
+
+	builderKey, _ := crypto.GenerateKey()
+	builder := crypto.PubkeyToAddress(builderKey.PublicKey)
+
+	config.BuilderKey = builderKey
+	config.ValidatorPayment = validator
+	config.PaymentMargin = margin
+
+	b := newTestWorkerBackend(t, ethashChainConfig, engine, db)
+
+	w := newWorker(&config, ethashChainConfig, engine, b, new(event.TypeMux), nil, false, &flashbotsData{})
+	defer w.close()
+
+	if errs := b.txPool.Add([]*types.Transaction{b.newRandomTx(false)}, true, true); errs[0] != nil {
+		t.Fatalf("failed to add transaction: %v", errs[0])
+	}
+
+	env, err := w.prepareWork(&generateParams{timestamp: uint64(time.Now().Unix()), coinbase: builder})
+	if err != nil {
+		t.Fatalf("failed to prepare work: %v", err)
+	}
+	defer env.discard()
+
+	if have, want := env.gasPool.Gas(), env.header.GasLimit-params.TxGas; have != want {
+		t.Fatalf("reserved gas mismatch: have %d, want %d", have, want)
+	}
+
+	if err := w.fillTransactions(context.Background(), nil, env, context.Background()); err != nil {
+		t.Fatalf("failed to fill transactions: %v", err)
+	}
+
+	fees := new(big.Int).Set(env.profit)
+
+	if err := w.commitValidatorPayment(env); err != nil {
+		t.Fatalf("failed to commit validator payment: %v", err)
+	}
+
+	if len(env.txs) != 2 {
+		t.Fatalf("transaction count mismatch: have %d, want 2", len(env.txs))
+	}
+
+	payment := env.txs[1]
+	if *payment.To() != validator {
+		t.Fatalf("payment recipient mismatch: have %x, want %x", payment.To(), validator)
+	}
+
+	want := new(big.Int).Sub(fees, margin)
+	want.Sub(want, new(big.Int).Mul(env.header.BaseFee, new(big.Int).SetUint64(params.TxGas)))
+
+	if payment.Value().Cmp(want) != 0 {
+		t.Fatalf("payment value mismatch: have %v, want %v", payment.Value(), want)
+	}
+
+	if env.profit.Cmp(want) != 0 {
+		t.Fatalf("block value mismatch: have %v, want %v", env.profit, want)
+	}
+
+	if balance := env.state.GetBalance(validator); balance.Cmp(want) != 0 {
+		t.Fatalf("validator balance mismatch: have %v, want %v", balance, want)
+	}
+}
+
+// Tests that a builder only submits blocks paying the validator for sealing,
+// the empty block not being pre-sealed.
+func TestBuilderSealsPaidBlocks(t *testing.T) {
+	t.Parallel()
+
+	var (
+		db        = rawdb.NewMemoryDatabase()
+		engine    = ethash.NewFaker()
+		config    = *testConfig
+		validator = common.HexToAddress("0x1000000000000000000000000000000000000001")
+	)
+
+	defer engine.Close()
+
+	builderKey, _ := crypto.GenerateKey()
+
+	config.BuilderKey = builderKey
+	config.ValidatorPayment = validator
+	config.CommitInterruptFlag = false
+
+	b := newTestWorkerBackend(t, ethashChainConfig, engine, db)
+
+	w := newWorker(&config, ethashChainConfig, engine, b, new(event.TypeMux), nil, false, &flashbotsData{})
+	defer w.close()
+
+	w.setEtherbase(testBankAddress)
+	w.noempty.Store(false)
+
+	if errs := b.txPool.Add([]*types.Transaction{b.newRandomTx(false)}, true, true); errs[0] != nil {
+		t.Fatalf("failed to add transaction: %v", errs[0])
+	}
+
+	var (
+		tasks  = make(chan *task, 16)
+		unpaid = make(chan *task, 16)
+	)
+
+	w.newTaskHook = func(task *task) {
+		txs := task.block.Transactions()
+		if len(txs) == 0 || *txs[len(txs)-1].To() != validator {
+			unpaid <- task
+			return
+		}
+
+		tasks <- task
+	}
+	w.skipSealHook = func(task *task) bool { return true }
+	w.fullTaskHook = func() {
+		time.Sleep(100 * time.Millisecond)
+	}
+
+	w.start()
+
+	select {
+	case <-tasks:
+	case task := <-unpaid:
+		t.Fatalf("unpaid block %d submitted with %d transactions", task.block.NumberU64(), len(task.block.Transactions()))
+	case <-time.After(3 * time.Second):
+		t.Fatal("new task timeout")
+	}
+
+	w.stop()
+
+	select {
+	case task := <-unpaid:
+		t.Fatalf("unpaid block %d submitted with %d transactions", task.block.NumberU64(), len(task.block.Transactions()))
+	default:
+	}
+}
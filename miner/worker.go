@@ -91,11 +91,20 @@ var (
 	errBlockInterruptedByNewHead  = errors.New("new head arrived while building block")
 	errBlockInterruptedByRecommit = errors.New("recommit interrupt while building block")
 	errBlockInterruptedByTimeout  = errors.New("timeout while building block")
+	errBundleGasExceeded          = errors.New("bundle exceeds the maximum bundle gas")
 
 	// metrics gauge to track total and empty blocks sealed by a miner
 	sealedBlocksCounter      = metrics.NewRegisteredCounter("worker/sealedBlocks", nil)
 	sealedEmptyBlocksCounter = metrics.NewRegisteredCounter("worker/sealedEmptyBlocks", nil)
 	txCommitInterruptCounter = metrics.NewRegisteredCounter("worker/txCommitInterrupt", nil)
+
+	// metrics counter to track how often a worker reused the shared pending snapshot
+	pendingSnapshotHitCounter = metrics.NewRegisteredCounter("worker/pendingSnapshot/hit", nil)
+
+	// metrics to track the block gas used by bundles
+	bundleGasUsedHistogram  = metrics.NewRegisteredHistogram("worker/bundles/gasUsed", nil, metrics.NewExpDecaySample(1028, 0.015))
+	bundleGasShareHistogram = metrics.NewRegisteredHistogram("worker/bundles/gasShare", nil, metrics.NewExpDecaySample(1028, 0.015))
+	bundleGasRejectedMeter  = metrics.NewRegisteredMeter("worker/bundles/rejected", nil)
 )
 
 // environment is the worker's current environment and holds all
@@ -108,6 +117,11 @@ type environment struct {
 	coinbase common.Address
 	profit   *big.Int
 
+	builderBalance *big.Int // Balance of the builder before the block, nil if not in builder mode
+
+	bundleGasUsed uint64                            // Block gas used by the committed bundles
+	backruns      map[common.Hash][]types.MevBundle // Bundles placed right after the pending transaction they target
+
 	header   *types.Header
 	txs      []*types.Transaction
 	receipts []*types.Receipt
@@ -125,6 +139,7 @@ func (env *environment) copy() *environment {
 		state:               env.state.Copy(),
 		tcount:              env.tcount,
 		coinbase:            env.coinbase,
+		bundleGasUsed:       env.bundleGasUsed,
 		header:              types.CopyHeader(env.header),
 		receipts:            copyReceipts(env.receipts),
 		depsMVFullWriteList: env.depsMVFullWriteList,
@@ -135,6 +150,10 @@ func (env *environment) copy() *environment {
 		cpy.profit = new(big.Int).Set(env.profit)
 	}
 
+	if env.builderBalance != nil {
+		cpy.builderBalance = new(big.Int).Set(env.builderBalance)
+	}
+
 	if env.gasPool != nil {
 		gasPool := *env.gasPool
 		cpy.gasPool = &gasPool
@@ -922,6 +941,12 @@ func (w *worker) makeEnv(parent *types.Header, header *types.Header, coinbase co
 	env.tcount = 0
 	env.gasPool = new(core.GasPool).AddGas(header.GasLimit)
 
+	// Reserve the gas of the validator payment appended at the end of the block
+	if w.isBuilder(coinbase) && header.GasLimit >= params.TxGas {
+		env.builderBalance = new(big.Int).Set(state.GetBalance(coinbase))
+		env.gasPool.SubGas(params.TxGas)
+	}
+
 	env.depsMVFullWriteList = [][]blockstm.WriteDescriptor{}
 	env.mvReadMapList = []map[blockstm.Key]blockstm.ReadDescriptor{}
 
@@ -974,7 +999,9 @@ func (w *worker) commitTransaction(env *environment, tx *types.Transaction, inte
 	return receipt.Logs, nil
 }
 
-func (w *worker) commitBundle(env *environment, txs types.Transactions, interrupt *atomic.Int32, interruptCtx context.Context) error {
+// commitBundle applies the merged bundles to the environment, one after the
+// other. They are all reverted if one of them uses more gas than allowed.
+func (w *worker) commitBundle(env *environment, bundles []types.Transactions, interrupt *atomic.Int32, interruptCtx context.Context) error {
 	var (
 		errCouldNotApplyTransaction = errors.New("could not apply transaction")
 		errBundleInterrupted        = errors.New("interrupt while applying bundles")
@@ -985,94 +1012,148 @@ func (w *worker) commitBundle(env *environment, txs types.Transactions, interrup
 		env.gasPool = new(core.GasPool).AddGas(gasLimit)
 	}
 
+	// Apply the bundles against their own share of the block gas, charging the
+	// gas they used to the block gas pool afterwards.
+	blockGasPool := env.gasPool
+	env.gasPool = new(core.GasPool).AddGas(w.bundleGasLimit(env))
+	bundleGas := env.gasPool.Gas()
+
+	// The state is finalised after every transaction, so it is backed up to
+	// revert the bundles exceeding the maximum gas per bundle
+	var backup *state.StateDB
+	if w.config.MaxBundleGas > 0 {
+		backup = env.state.Copy()
+	}
+
+	var (
+		tcount  = env.tcount
+		txCount = len(env.txs)
+		gasUsed = env.header.GasUsed
+		profit  = new(big.Int).Set(env.profit)
+	)
+
+	revert := func() {
+		env.state.StopPrefetcher()
+		env.state = backup
+		env.gasPool = new(core.GasPool).AddGas(bundleGas)
+		env.tcount = tcount
+		env.txs = env.txs[:txCount]
+		env.receipts = env.receipts[:txCount]
+		env.header.GasUsed = gasUsed
+		env.profit = profit
+	}
+
+	defer func() {
+		used := bundleGas - env.gasPool.Gas()
+		env.gasPool = blockGasPool
+		_ = env.gasPool.SubGas(used)
+		env.bundleGasUsed += used
+
+		if used > 0 {
+			bundleGasUsedHistogram.Update(int64(used))
+			bundleGasShareHistogram.Update(int64(used * 100 / gasLimit))
+		}
+	}()
+
 	var coalescedLogs []*types.Log
 
 mainloop:
-	for _, tx := range txs {
-		if interruptCtx != nil {
-			// case of interrupting by timeout
-			select {
-			case <-interruptCtx.Done():
-				txCommitInterruptCounter.Inc(1)
-				log.Warn("Tx Level Interrupt")
-				break mainloop
-			default:
-			}
-		}
+	for _, txs := range bundles {
+		bundleStart := env.gasPool.Gas()
 
-		// In the following three cases, we will interrupt the execution of the transaction.
-		// (1) new head block event arrival, the interrupt signal is 1
-		// (2) worker start or restart, the interrupt signal is 1
-		// (3) worker recreate the sealing block with any newly arrived transactions, the interrupt signal is 2.
-		// For the first two cases, the semi-finished work will be discarded.
-		// For the third case, the semi-finished work will be submitted to the consensus engine.
-		if interrupt != nil && interrupt.Load() != commitInterruptNone {
-			// Notify resubmit loop to increase resubmitting interval due to too frequent commits.
-			if interrupt.Load() == commitInterruptResubmit {
-				ratio := float64(gasLimit-env.gasPool.Gas()) / float64(gasLimit)
-				if ratio < 0.1 {
-					ratio = 0.1
-				}
-				w.resubmitAdjustCh <- &intervalAdjust{
-					ratio: ratio,
-					inc:   true,
+		for _, tx := range txs {
+			if interruptCtx != nil {
+				// case of interrupting by timeout
+				select {
+				case <-interruptCtx.Done():
+					txCommitInterruptCounter.Inc(1)
+					log.Warn("Tx Level Interrupt")
+					break mainloop
+				default:
 				}
 			}
-			return errBundleInterrupted
-		}
-		// If we don't have enough gas for any further transactions then we're done
-		if env.gasPool.Gas() < params.TxGas {
-			log.Trace("Not enough gas for further transactions", "have", env.gasPool, "want", params.TxGas)
-			break
-		}
 
-		// Error may be ignored here. The error has already been checked
-		// during transaction acceptance is the transaction pool.
-		//
-		// We use the eip155 signer regardless of the current hf.
-		from, _ := types.Sender(env.signer, tx)
-		// Check whether the tx is replay protected. If we're not in the EIP155 hf
-		// phase, start ignoring the sender until we do.
-		if tx.Protected() && !w.chainConfig.IsEIP155(env.header.Number) {
-			log.Trace("Ignoring reply protected transaction", "hash", tx.Hash(), "eip155", w.chainConfig.EIP155Block)
-			return errCouldNotApplyTransaction
-		}
-		// Start executing the transaction
-		env.state.SetTxContext(tx.Hash(), env.tcount)
-
-		logs, err := w.commitTransaction(env, tx, interruptCtx)
-		switch {
-		case errors.Is(err, core.ErrGasLimitReached):
-			// Pop the current out-of-gas transaction without shifting in the next from the account
-			log.Trace("Gas limit exceeded for current block", "sender", from)
-			return errCouldNotApplyTransaction
-
-		case errors.Is(err, core.ErrNonceTooLow):
-			// New head notification data race between the transaction pool and miner, shift
-			log.Trace("Skipping transaction with low nonce", "sender", from, "nonce", tx.Nonce())
-			return errCouldNotApplyTransaction
+			// In the following three cases, we will interrupt the execution of the transaction.
+			// (1) new head block event arrival, the interrupt signal is 1
+			// (2) worker start or restart, the interrupt signal is 1
+			// (3) worker recreate the sealing block with any newly arrived transactions, the interrupt signal is 2.
+			// For the first two cases, the semi-finished work will be discarded.
+			// For the third case, the semi-finished work will be submitted to the consensus engine.
+			if interrupt != nil && interrupt.Load() != commitInterruptNone {
+				// Notify resubmit loop to increase resubmitting interval due to too frequent commits.
+				if interrupt.Load() == commitInterruptResubmit {
+					ratio := float64(gasLimit-env.gasPool.Gas()) / float64(gasLimit)
+					if ratio < 0.1 {
+						ratio = 0.1
+					}
+					w.resubmitAdjustCh <- &intervalAdjust{
+						ratio: ratio,
+						inc:   true,
+					}
+				}
+				return errBundleInterrupted
+			}
+			// If we don't have enough gas for any further transactions then we're done
+			if env.gasPool.Gas() < params.TxGas {
+				log.Trace("Not enough gas for further transactions", "have", env.gasPool, "want", params.TxGas)
+				break mainloop
+			}
 
-		case errors.Is(err, core.ErrNonceTooHigh):
-			// Reorg notification data race between the transaction pool and miner, skip account =
-			log.Trace("Skipping account with hight nonce", "sender", from, "nonce", tx.Nonce())
-			return errCouldNotApplyTransaction
+			// Error may be ignored here. The error has already been checked
+			// during transaction acceptance is the transaction pool.
+			//
+			// We use the eip155 signer regardless of the current hf.
+			from, _ := types.Sender(env.signer, tx)
+			// Check whether the tx is replay protected. If we're not in the EIP155 hf
+			// phase, start ignoring the sender until we do.
+			if tx.Protected() && !w.chainConfig.IsEIP155(env.header.Number) {
+				log.Trace("Ignoring reply protected transaction", "hash", tx.Hash(), "eip155", w.chainConfig.EIP155Block)
+				return errCouldNotApplyTransaction
+			}
+			// Start executing the transaction
+			env.state.SetTxContext(tx.Hash(), env.tcount)
+
+			logs, err := w.commitTransaction(env, tx, interruptCtx)
+			switch {
+			case errors.Is(err, core.ErrGasLimitReached):
+				// Pop the current out-of-gas transaction without shifting in the next from the account
+				log.Trace("Gas limit exceeded for current block", "sender", from)
+				return errCouldNotApplyTransaction
+
+			case errors.Is(err, core.ErrNonceTooLow):
+				// New head notification data race between the transaction pool and miner, shift
+				log.Trace("Skipping transaction with low nonce", "sender", from, "nonce", tx.Nonce())
+				return errCouldNotApplyTransaction
+
+			case errors.Is(err, core.ErrNonceTooHigh):
+				// Reorg notification data race between the transaction pool and miner, skip account =
+				log.Trace("Skipping account with hight nonce", "sender", from, "nonce", tx.Nonce())
+				return errCouldNotApplyTransaction
+
+			case errors.Is(err, nil):
+				// Everything ok, collect the logs and shift in the next transaction from the same account
+				coalescedLogs = append(coalescedLogs, logs...)
+				env.tcount++
+				continue
 
-		case errors.Is(err, nil):
-			// Everything ok, collect the logs and shift in the next transaction from the same account
-			coalescedLogs = append(coalescedLogs, logs...)
-			env.tcount++
-			continue
+			case errors.Is(err, core.ErrTxTypeNotSupported):
+				// Pop the unsupported transaction without shifting in the next from the account
+				log.Trace("Skipping unsupported transaction type", "sender", from, "type", tx.Type())
+				return errCouldNotApplyTransaction
 
-		case errors.Is(err, core.ErrTxTypeNotSupported):
-			// Pop the unsupported transaction without shifting in the next from the account
-			log.Trace("Skipping unsupported transaction type", "sender", from, "type", tx.Type())
-			return errCouldNotApplyTransaction
+			default:
+				// Strange error, discard the transaction and get the next in line (note, the
+				// nonce-too-high clause will prevent us from executing in vain).
+				log.Debug("Transaction failed, account skipped", "hash", tx.Hash(), "err", err)
+				return errCouldNotApplyTransaction
+			}
+		}
 
-		default:
-			// Strange error, discard the transaction and get the next in line (note, the
-			// nonce-too-high clause will prevent us from executing in vain).
-			log.Debug("Transaction failed, account skipped", "hash", tx.Hash(), "err", err)
-			return errCouldNotApplyTransaction
+		// A bundle using more gas than allowed is rejected with the bundles
+		// merged with it, as in the simulation
+		if w.exceedsMaxBundleGas(bundleStart - env.gasPool.Gas()) {
+			revert()
+			return errBundleGasExceeded
 		}
 	}
 
@@ -1133,6 +1214,37 @@ func (w *worker) commitTransactions(env *environment, txs *transactionsByPriceAn
 		}(chDeps)
 	}
 
+	// Postpone the transactions conflicting with the ones already selected, to
+	// pack the independent ones first
+	var selection *conflictSelection
+
+	if EnableMVHashMap && w.IsRunning() && w.config.ConflictAwareSelection {
+		burntContract := common.HexToAddress(w.chainConfig.Bor.CalculateBurntContract(env.header.Number.Uint64()))
+		selection = newConflictSelection(env, burntContract, w.config.ConflictFeeTolerance)
+	}
+
+	// recordDeps tracks the dependencies of the committed transaction at the given index
+	recordDeps := func(index int, readList []blockstm.ReadDescriptor, fullWriteList []blockstm.WriteDescriptor, readMap map[blockstm.Key]blockstm.ReadDescriptor) {
+		env.depsMVFullWriteList = append(env.depsMVFullWriteList, fullWriteList)
+		env.mvReadMapList = append(env.mvReadMapList, readMap)
+
+		if index+1 > len(env.depsMVFullWriteList) {
+			log.Warn("blockstm - env.tcount > len(env.depsMVFullWriteList)", "env.tcount", index+1, "len(depsMVFullWriteList)", len(env.depsMVFullWriteList))
+		}
+
+		temp := blockstm.TxDep{
+			Index:         index,
+			ReadList:      readList,
+			FullWriteList: env.depsMVFullWriteList,
+		}
+
+		chDeps <- temp
+
+		if selection != nil {
+			selection.record(fullWriteList)
+		}
+	}
+
 	initialGasLimit := env.gasPool.Gas()
 
 	initialTxs := txs.GetTxs()
@@ -1181,6 +1293,10 @@ mainloop:
 			log.Trace("Not enough gas for further transactions", "have", env.gasPool, "want", params.TxGas)
 			break
 		}
+		// Put back the postponed transactions not worth postponing anymore
+		if selection != nil {
+			selection.resume(txs)
+		}
 		// Retrieve the next transaction and abort if all done.
 		ltx := txs.Peek()
 		if ltx == nil {
@@ -1241,6 +1357,10 @@ mainloop:
 			txs.Pop()
 			continue
 		}
+		// Postpone the transaction if it conflicts with the ones already selected
+		if selection != nil && selection.postpone(txs, tx, from) {
+			continue
+		}
 		// Start executing the transaction
 		env.state.SetTxContext(tx.Hash(), env.tcount)
 
@@ -1264,20 +1384,7 @@ mainloop:
 			env.tcount++
 
 			if EnableMVHashMap && w.IsRunning() {
-				env.depsMVFullWriteList = append(env.depsMVFullWriteList, env.state.MVFullWriteList())
-				env.mvReadMapList = append(env.mvReadMapList, env.state.MVReadMap())
-
-				if env.tcount > len(env.depsMVFullWriteList) {
-					log.Warn("blockstm - env.tcount > len(env.depsMVFullWriteList)", "env.tcount", env.tcount, "len(depsMVFullWriteList)", len(env.depsMVFullWriteList))
-				}
-
-				temp := blockstm.TxDep{
-					Index:         env.tcount - 1,
-					ReadList:      env.state.MVReadList(),
-					FullWriteList: env.depsMVFullWriteList,
-				}
-
-				chDeps <- temp
+				recordDeps(env.tcount-1, env.state.MVReadList(), env.state.MVFullWriteList(), env.state.MVReadMap())
 			}
 
 			txs.Shift()
@@ -1297,6 +1404,16 @@ mainloop:
 			env.state.ClearReadMap()
 			env.state.ClearWriteMap()
 		}
+
+		// Place the bundles backrunning the committed transaction right after it
+		if err == nil && len(env.backruns[tx.Hash()]) > 0 {
+			track := recordDeps
+			if !EnableMVHashMap || !w.IsRunning() {
+				track = nil
+			}
+
+			coalescedLogs = append(coalescedLogs, w.commitBackruns(env, tx.Hash(), interruptCtx, track)...)
+		}
 	}
 
 	// nolint:nestif
@@ -1341,6 +1458,8 @@ mainloop:
 
 			if delayFlag {
 				blockExtraData.TxDependency = tempDeps
+
+				txDependencyLongestPathHistogram.Update(int64(longestDependencyPath(tempDeps)))
 			} else {
 				blockExtraData.TxDependency = nil
 			}
@@ -1551,8 +1670,7 @@ func (w *worker) fillTransactions(ctx context.Context, interrupt *atomic.Int32,
 
 	// Split the pending transactions into locals and remotes
 	// Fill the block with all available pending transactions.
-	pending := w.eth.TxPool().Pending(true)
-	localTxs, remoteTxs := make(map[common.Address][]*txpool.LazyTransaction), pending
+	var localTxs, remoteTxs map[common.Address][]*txpool.LazyTransaction
 
 	var (
 		localTxsCount  int
@@ -1623,27 +1741,20 @@ func (w *worker) fillTransactions(ctx context.Context, interrupt *atomic.Int32,
 	tracing.Exec(ctx, "", "worker.SplittingTransactions", func(ctx context.Context, span trace.Span) {
 		prePendingTime := time.Now()
 
-		pending := w.eth.TxPool().Pending(true)
-		remoteTxs = pending
-
-		postPendingTime := time.Now()
-
-		for _, account := range w.eth.TxPool().Locals() {
-			if txs := remoteTxs[account]; len(txs) > 0 {
-				delete(remoteTxs, account)
-
-				localTxs[account] = txs
-			}
+		// Share the pending snapshot with the other workers if possible
+		if w.flashbots.pending != nil {
+			localTxs, remoteTxs = w.flashbots.pending.get(w.eth.TxPool(), env.header.ParentHash)
+		} else {
+			localTxs, remoteTxs = splitPending(w.eth.TxPool())
 		}
 
-		postLocalsTime := time.Now()
+		localTxsCount, remoteTxsCount = countPending(localTxs), countPending(remoteTxs)
 
 		tracing.SetAttributes(
 			span,
 			attribute.Int("len of local txs", localTxsCount),
 			attribute.Int("len of remote txs", remoteTxsCount),
-			attribute.String("time taken by Pending()", fmt.Sprintf("%v", postPendingTime.Sub(prePendingTime))),
-			attribute.String("time taken by Locals()", fmt.Sprintf("%v", postLocalsTime.Sub(postPendingTime))),
+			attribute.String("time taken by Pending()", fmt.Sprintf("%v", time.Since(prePendingTime))),
 		)
 	})
 
@@ -1654,19 +1765,39 @@ func (w *worker) fillTransactions(ctx context.Context, interrupt *atomic.Int32,
 			return err
 		}
 
+		bundles, env.backruns = splitBackruns(bundles)
+		defer w.dropBackruns(env)
+
 		bundleTxs, bundle, numBundles, err := w.generateFlashbotsBundle(env, bundles, w.eth.TxPool(), interruptCtx)
 		if err != nil {
 			log.Error("Failed to generate flashbots bundle", "err", err)
 			return err
 		}
-		log.Info("Flashbots bundle", "ethToCoinbase", bundle.totalEth, "gasUsed", bundle.totalGasUsed, "bundleScore", bundle.mevGasPrice, "bundleLength", len(bundleTxs), "numBundles", numBundles, "worker", w.flashbots.maxMergedBundles)
-		if len(bundleTxs) == 0 {
+
+		bundleLength := 0
+		for _, txs := range bundleTxs {
+			bundleLength += len(txs)
+		}
+
+		log.Info("Flashbots bundle", "ethToCoinbase", bundle.totalEth, "gasUsed", bundle.totalGasUsed, "bundleScore", bundle.mevGasPrice, "bundleLength", bundleLength, "numBundles", numBundles, "backruns", len(env.backruns), "worker", w.flashbots.maxMergedBundles)
+		if len(bundleTxs) == 0 && len(env.backruns) == 0 {
 			return nil
 		}
-		if err := w.commitBundle(env, bundleTxs, interrupt, interruptCtx); err != nil {
-			return err
+		if len(bundleTxs) > 0 {
+			err := w.commitBundle(env, bundleTxs, interrupt, interruptCtx)
+
+			switch {
+			case errors.Is(err, errBundleGasExceeded):
+				bundleGasRejectedMeter.Mark(1)
+				log.Warn("Rejected flashbots bundle at commit", "number", env.header.Number, "err", err)
+
+			case err != nil:
+				return err
+
+			default:
+				env.profit.Add(env.profit, bundle.ethSentToCoinbase)
+			}
 		}
-		env.profit.Add(env.profit, bundle.ethSentToCoinbase)
 	}
 
 	var (
@@ -1784,6 +1915,16 @@ func (w *worker) commitWork(ctx context.Context, interrupt *atomic.Int32, noempt
 	}
 	start := time.Now()
 
+	// Pin the round to its thread, for its CPU time not to include the one of
+	// the other workers
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+
+	cpuStart := threadCPUTime()
+	defer func() {
+		w.updateWorkMetrics(time.Since(start), threadCPUTime()-cpuStart)
+	}()
+
 	var (
 		work *environment
 		err  error
@@ -1798,6 +1939,11 @@ func (w *worker) commitWork(ctx context.Context, interrupt *atomic.Int32, noempt
 				log.Error("Refusing to mine without etherbase")
 				return
 			}
+
+			// In builder mode the fees are collected by the builder key
+			if w.config.BuilderKey != nil {
+				coinbase = w.builderAddress()
+			}
 		}
 
 		work, err = w.prepareWork(&generateParams{
@@ -1835,7 +1981,8 @@ func (w *worker) commitWork(ctx context.Context, interrupt *atomic.Int32, noempt
 
 	// Create an empty block based on temporary copied state for
 	// sealing in advance without waiting block execution finished.
-	if !noempty && !w.noempty.Load() {
+	// A builder block is never sealed empty, not paying the validator.
+	if !noempty && !w.noempty.Load() && w.config.BuilderKey == nil {
 		_ = w.commit(ctx, work.copy(), nil, false, start)
 	}
 	// Fill pending transactions from the txpool into the block.
@@ -1869,6 +2016,16 @@ func (w *worker) commitWork(ctx context.Context, interrupt *atomic.Int32, noempt
 		work.discard()
 		return
 	}
+
+	// A builder block is only sealed if it pays the validator
+	if work.builderBalance != nil {
+		if err := w.commitValidatorPayment(work); err != nil {
+			log.Warn("Discarding block without validator payment", "number", work.header.Number, "err", err)
+			work.discard()
+
+			return
+		}
+	}
 	// Submit the generated block for consensus sealing.
 	_ = w.commit(ctx, work.copy(), w.fullTaskHook, true, start)
 
@@ -1881,6 +2038,16 @@ func (w *worker) commitWork(ctx context.Context, interrupt *atomic.Int32, noempt
 	w.current = work
 }
 
+// updateWorkMetrics records the wall clock time spent by the worker in a single
+// commitWork round, and the CPU time spent by the thread running it. The CPU time
+// doesn't cover the goroutines spawned by the round, nor the other workers.
+func (w *worker) updateWorkMetrics(elapsed time.Duration, cpu time.Duration) {
+	name := w.flashbots.name()
+
+	metrics.GetOrRegisterTimer("worker/"+name+"/commit", nil).Update(elapsed)
+	metrics.GetOrRegisterTimer("worker/"+name+"/cpu", nil).Update(cpu)
+}
+
 func getInterruptTimer(ctx context.Context, work *environment, current *types.Block) (context.Context, func()) {
 	delay := time.Until(time.Unix(int64(work.header.Time), 0))
 
@@ -1982,6 +2149,45 @@ func (w *worker) isTTDReached(header *types.Header) bool {
 	return td != nil && ttd != nil && td.Cmp(ttd) >= 0
 }
 
+// bundleGasLimit returns the amount of block gas the bundles of the given
+// environment may still use, honouring the configured bundle share of the block
+// and the gas reserved for mempool transactions.
+func (w *worker) bundleGasLimit(env *environment) uint64 {
+	gasLimit := env.header.GasLimit
+
+	limit := gasLimit
+	if share := w.config.BundleGasShare; share > 0 && share < 100 {
+		limit = gasLimit / 100 * share
+	}
+
+	if reserve := w.config.MempoolGasReserve; reserve > 0 {
+		if reserve >= gasLimit {
+			return 0
+		}
+
+		if gasLimit-reserve < limit {
+			limit = gasLimit - reserve
+		}
+	}
+
+	if env.bundleGasUsed >= limit {
+		return 0
+	}
+
+	limit -= env.bundleGasUsed
+
+	if env.gasPool != nil && env.gasPool.Gas() < limit {
+		limit = env.gasPool.Gas()
+	}
+
+	return limit
+}
+
+// exceedsMaxBundleGas reports whether a single bundle uses more gas than allowed.
+func (w *worker) exceedsMaxBundleGas(gasUsed uint64) bool {
+	return w.config.MaxBundleGas > 0 && gasUsed > w.config.MaxBundleGas
+}
+
 type simulatedBundle struct {
 	mevGasPrice       *big.Int
 	totalEth          *big.Int
@@ -1990,7 +2196,9 @@ type simulatedBundle struct {
 	originalBundle    types.MevBundle
 }
 
-func (w *worker) generateFlashbotsBundle(env *environment, bundles []types.MevBundle, pendingTxs *txpool.TxPool, interruptCtx context.Context) (types.Transactions, simulatedBundle, int, error) {
+// generateFlashbotsBundle simulates the given bundles and merges the most
+// profitable ones, returning the transactions of every merged bundle.
+func (w *worker) generateFlashbotsBundle(env *environment, bundles []types.MevBundle, pendingTxs *txpool.TxPool, interruptCtx context.Context) ([]types.Transactions, simulatedBundle, int, error) {
 	simulatedBundles, err := w.simulateBundles(env, bundles, pendingTxs, interruptCtx)
 	if err != nil {
 		return nil, simulatedBundle{}, 0, err
@@ -2003,11 +2211,14 @@ func (w *worker) generateFlashbotsBundle(env *environment, bundles []types.MevBu
 	return w.mergeBundles(env, simulatedBundles, pendingTxs, interruptCtx)
 }
 
-func (w *worker) mergeBundles(env *environment, bundles []simulatedBundle, pendingTxs *txpool.TxPool, interruptCtx context.Context) (types.Transactions, simulatedBundle, int, error) {
-	finalBundle := types.Transactions{}
+func (w *worker) mergeBundles(env *environment, bundles []simulatedBundle, pendingTxs *txpool.TxPool, interruptCtx context.Context) ([]types.Transactions, simulatedBundle, int, error) {
+	var (
+		finalBundle types.Transactions
+		merged      []types.Transactions
+	)
 
 	currentState := env.state.Copy()
-	gasPool := new(core.GasPool).AddGas(env.header.GasLimit)
+	gasPool := new(core.GasPool).AddGas(w.bundleGasLimit(env))
 
 	var prevState *state.StateDB
 	var prevGasPool *core.GasPool
@@ -2027,7 +2238,7 @@ func (w *worker) mergeBundles(env *environment, bundles []simulatedBundle, pendi
 		floorGasPrice = floorGasPrice.Div(floorGasPrice, big.NewInt(100))
 
 		simmed, err := w.computeBundleGas(env, bundle.originalBundle, currentState, gasPool, pendingTxs, len(finalBundle), interruptCtx)
-		if err != nil || simmed.mevGasPrice.Cmp(floorGasPrice) <= 0 {
+		if err != nil || simmed.mevGasPrice.Cmp(floorGasPrice) <= 0 || w.exceedsMaxBundleGas(simmed.totalGasUsed) {
 			currentState = prevState
 			gasPool = prevGasPool
 			continue
@@ -2036,6 +2247,7 @@ func (w *worker) mergeBundles(env *environment, bundles []simulatedBundle, pendi
 		log.Info("Included bundle", "ethToCoinbase", simmed.totalEth, "gasUsed", simmed.totalGasUsed, "bundleScore", simmed.mevGasPrice, "bundleLength", len(simmed.originalBundle.Txs), "worker", w.flashbots.maxMergedBundles)
 
 		finalBundle = append(finalBundle, bundle.originalBundle.Txs...)
+		merged = append(merged, bundle.originalBundle.Txs)
 		mergedBundle.totalEth.Add(mergedBundle.totalEth, simmed.totalEth)
 		mergedBundle.ethSentToCoinbase.Add(mergedBundle.ethSentToCoinbase, simmed.ethSentToCoinbase)
 		mergedBundle.totalGasUsed += simmed.totalGasUsed
@@ -2050,7 +2262,7 @@ func (w *worker) mergeBundles(env *environment, bundles []simulatedBundle, pendi
 		return nil, simulatedBundle{}, count, nil
 	}
 
-	return finalBundle, simulatedBundle{
+	return merged, simulatedBundle{
 		mevGasPrice:       new(big.Int).Div(mergedBundle.totalEth, new(big.Int).SetUint64(mergedBundle.totalGasUsed)),
 		totalEth:          mergedBundle.totalEth,
 		ethSentToCoinbase: mergedBundle.ethSentToCoinbase,
@@ -2061,9 +2273,14 @@ func (w *worker) mergeBundles(env *environment, bundles []simulatedBundle, pendi
 func (w *worker) simulateBundles(env *environment, bundles []types.MevBundle, pendingTxs *txpool.TxPool, interruptCtx context.Context) ([]simulatedBundle, error) {
 	simulatedBundles := []simulatedBundle{}
 
+	gasLimit := w.bundleGasLimit(env)
+	if w.config.MaxBundleGas > 0 && w.config.MaxBundleGas < gasLimit {
+		gasLimit = w.config.MaxBundleGas
+	}
+
 	for _, bundle := range bundles {
 		state := env.state.Copy()
-		gasPool := new(core.GasPool).AddGas(env.header.GasLimit)
+		gasPool := new(core.GasPool).AddGas(gasLimit)
 		if len(bundle.Txs) == 0 {
 			continue
 		}
@@ -2071,6 +2288,7 @@ func (w *worker) simulateBundles(env *environment, bundles []types.MevBundle, pe
 
 		if err != nil {
 			log.Debug("Error computing gas for a bundle", "error", err)
+			bundleGasRejectedMeter.Mark(1)
 			continue
 		}
 		simulatedBundles = append(simulatedBundles, simmed)
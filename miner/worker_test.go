@@ -17,6 +17,8 @@
 package miner
 
 import (
+	"context"
+	"errors"
 	"math/big"
 	"os"
 	"sync/atomic"
@@ -1056,3 +1058,81 @@ func BenchmarkBorMiningBlockSTMMetadata(b *testing.B) {
 		}
 	}
 }
+
+func TestBundleGasLimits(t *testing.T) {
+	t.Parallel()
+
+	var (
+		db     = rawdb.NewMemoryDatabase()
+		engine = ethash.NewFaker()
+		config = *testConfig
+	)
+
+	defer engine.Close()
+
+	b := newTestWorkerBackend(t, ethashChainConfig, engine, db)
+
+	w := newWorker(&config, ethashChainConfig, engine, b, new(event.TypeMux), nil, false, &flashbotsData{isFlashbots: true, maxMergedBundles: 1})
+	defer w.close()
+
+	env, err := w.prepareWork(&generateParams{timestamp: uint64(time.Now().Unix()), coinbase: testUserAddress})
+	if err != nil {
+		t.Fatalf("failed to prepare work: %v", err)
+	}
+	defer env.discard()
+
+	gasLimit := env.header.GasLimit
+
+	assert.Equal(t, gasLimit, w.bundleGasLimit(env))
+
+	w.config.BundleGasShare = 50
+	assert.Equal(t, gasLimit/100*50, w.bundleGasLimit(env))
+
+	w.config.MempoolGasReserve = gasLimit - params.TxGas
+	assert.Equal(t, params.TxGas, w.bundleGasLimit(env))
+
+	w.config.MempoolGasReserve = gasLimit
+	assert.Equal(t, uint64(0), w.bundleGasLimit(env))
+
+	w.config.BundleGasShare, w.config.MempoolGasReserve = 0, 0
+
+	bundle := types.MevBundle{Txs: types.Transactions{b.newRandomTxWithNonce(false, 0), b.newRandomTxWithNonce(false, 1)}}
+
+	simulated, err := w.simulateBundles(env, []types.MevBundle{bundle}, b.txPool, context.Background())
+	if err != nil {
+		t.Fatalf("failed to simulate bundles: %v", err)
+	}
+
+	assert.Equal(t, 1, len(simulated))
+	assert.Equal(t, 2*params.TxGas, simulated[0].totalGasUsed)
+
+	// Bundles using more than the maximum gas per bundle are dropped
+	w.config.MaxBundleGas = params.TxGas
+
+	simulated, err = w.simulateBundles(env, []types.MevBundle{bundle}, b.txPool, context.Background())
+	if err != nil {
+		t.Fatalf("failed to simulate bundles: %v", err)
+	}
+
+	assert.Equal(t, 0, len(simulated))
+
+	// Bundles using more than the maximum gas per bundle are rejected at commit,
+	// leaving the environment untouched
+	if err := w.commitBundle(env, []types.Transactions{bundle.Txs}, nil, context.Background()); !errors.Is(err, errBundleGasExceeded) {
+		t.Fatalf("commit error mismatch: have %v, want %v", err, errBundleGasExceeded)
+	}
+
+	assert.Equal(t, gasLimit, env.gasPool.Gas())
+	assert.Equal(t, 0, len(env.txs))
+	assert.Equal(t, uint64(0), env.header.GasUsed)
+	assert.Equal(t, uint64(0), env.state.GetNonce(testBankAddress))
+
+	// Committed bundles are charged against the block gas pool
+	w.config.MaxBundleGas = 0
+
+	if err := w.commitBundle(env, []types.Transactions{bundle.Txs}, nil, context.Background()); err != nil {
+		t.Fatalf("failed to commit bundle: %v", err)
+	}
+
+	assert.Equal(t, gasLimit-2*params.TxGas, env.gasPool.Gas())
+}
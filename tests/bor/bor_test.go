@@ -7,6 +7,7 @@ import (
 	"context"
 	"crypto/ecdsa"
 	"encoding/hex"
+	"errors"
 	"fmt"
 	"io"
 	"math/big"
@@ -40,6 +41,7 @@ import (
 	"github.com/ethereum/go-ethereum/p2p/enode"
 	"github.com/ethereum/go-ethereum/params"
 	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/rpc"
 	"github.com/ethereum/go-ethereum/tests/bor/mocks"
 	"github.com/ethereum/go-ethereum/trie"
 )
@@ -496,6 +498,86 @@ func TestFetchStateSyncEvents(t *testing.T) {
 	insertNewBlock(t, chain, block)
 }
 
+func TestStateSyncEventsGasUsed(t *testing.T) {
+	genesis := InitGenesis(t, nil, "./testdata/genesis.json", sprintSize)
+	sample := getSampleEventRecord(t)
+
+	// The events are read from the StateCommitted logs of the live state receiver,
+	// only emitted for a receiver contract
+	stateReceiver := common.HexToAddress(genesis.Config.Bor.StateReceiverContract)
+	account := genesis.Alloc[stateReceiver]
+	account.Code = core.DefaultAmoyGenesisBlock().Alloc[stateReceiver].Code
+	genesis.Alloc[stateReceiver] = account
+	genesis.Alloc[sample.Contract] = core.GenesisAccount{Code: []byte{byte(vm.STOP)}, Balance: common.Big0}
+
+	init := buildEthereumInstanceWithGenesis(t, rawdb.NewMemoryDatabase(), genesis)
+	chain := init.ethereum.BlockChain()
+	engine := init.ethereum.Engine()
+	_bor := engine.(*bor.Bor)
+
+	defer _bor.Close()
+
+	block := init.genesis.ToBlock()
+
+	res, _ := loadSpanFromFile(t)
+
+	currentValidators := []*valset.Validator{valset.NewValidator(addr, 10)}
+
+	spanner := getMockedSpanner(t, currentValidators)
+	_bor.SetSpanner(spanner)
+
+	for i := uint64(1); i < sprintSize; i++ {
+		if IsSpanEnd(i) {
+			currentValidators = res.Result.ValidatorSet.Validators
+		}
+
+		block = buildNextBlock(t, _bor, chain, block, nil, init.genesis.Config.Bor, nil, currentValidators)
+		insertNewBlock(t, chain, block)
+	}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	h := mocks.NewMockIHeimdallClient(ctrl)
+	h.EXPECT().Close().AnyTimes()
+	h.EXPECT().Span(gomock.Any(), uint64(1)).Return(&res.Result, nil).AnyTimes()
+	// Nothing is whitelisted while the state syncs are replayed
+	h.EXPECT().FetchCheckpoint(gomock.Any(), int64(-1)).Return(nil, errors.New("no checkpoint")).AnyTimes()
+	h.EXPECT().FetchMilestone(gomock.Any()).Return(nil, errors.New("no milestone")).AnyTimes()
+	h.EXPECT().FetchLastNoAckMilestone(gomock.Any()).Return("", nil).AnyTimes()
+	h.EXPECT().FetchNoAckMilestone(gomock.Any(), string("test")).Return(nil).AnyTimes()
+
+	to := int64(chain.GetHeaderByNumber(0).Time)
+	eventCount := 5
+
+	sample.Time = time.Unix(to-int64(eventCount+1), 0)
+	eventRecords := generateFakeStateSyncEvents(sample, eventCount)
+
+	h.EXPECT().StateSyncEvents(gomock.Any(), uint64(1), to).Return(eventRecords, nil).AnyTimes()
+	_bor.SetHeimdallClient(h)
+
+	parent := block
+	block = buildNextBlock(t, _bor, chain, block, nil, init.genesis.Config.Bor, nil, res.Result.ValidatorSet.Validators)
+	insertNewBlock(t, chain, block)
+
+	applied, err := eth.NewBorAPI(init.ethereum).GetStateSyncEvents(context.Background(), rpc.BlockNumber(block.NumberU64()))
+	require.NoError(t, err)
+	require.Len(t, applied, eventCount)
+
+	// The events committed one after the other on the parent state use the same gas
+	statedb, err := chain.StateAt(parent.Root())
+	require.NoError(t, err)
+
+	for i, event := range applied {
+		simulated, err := _bor.SimulateStateSync(eventRecords[i], statedb, block.Header(), chain)
+		require.NoError(t, err)
+
+		require.Equal(t, eventRecords[i].ID, event.ID)
+		require.NotZero(t, event.GasUsed)
+		require.Equal(t, simulated.GasUsed, event.GasUsed, "gas used by state sync %d", event.ID)
+	}
+}
+
 func validateStateSyncEvents(t *testing.T, expected []*clerk.EventRecordWithTime, got []*types.StateSyncData) {
 	require.Equal(t, len(expected), len(got), "number of state sync events should be equal")
 
@@ -700,6 +782,73 @@ func TestOutOfTurnSigning(t *testing.T) {
 	require.Nil(t, err)
 }
 
+func TestProducerTracker(t *testing.T) {
+	init := buildEthereumInstance(t, rawdb.NewMemoryDatabase())
+	chain := init.ethereum.BlockChain()
+	engine := init.ethereum.Engine()
+	_bor := engine.(*bor.Bor)
+
+	defer _bor.Close()
+
+	_, heimdallSpan := loadSpanFromFile(t)
+
+	h, ctrl := getMockedHeimdallClient(t, heimdallSpan)
+	defer ctrl.Finish()
+
+	h.EXPECT().Close().AnyTimes()
+	h.EXPECT().FetchCheckpoint(gomock.Any(), int64(-1)).Return(&checkpoint.Checkpoint{
+		Proposer:   addr,
+		StartBlock: big.NewInt(0),
+		EndBlock:   big.NewInt(int64(spanSize)),
+	}, nil).AnyTimes()
+	h.EXPECT().FetchMilestone(gomock.Any()).Return(&milestone.Milestone{
+		Proposer:   addr,
+		StartBlock: big.NewInt(0),
+		EndBlock:   big.NewInt(int64(spanSize)),
+	}, nil).AnyTimes()
+	h.EXPECT().FetchLastNoAckMilestone(gomock.Any()).Return("", nil).AnyTimes()
+	h.EXPECT().FetchNoAckMilestone(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+
+	currentValidators := []*valset.Validator{valset.NewValidator(addr, 10)}
+
+	_bor.SetSpanner(getMockedSpanner(t, currentValidators))
+	_bor.SetHeimdallClient(h)
+
+	tracker := bor.NewProducerTracker(_bor, chain)
+
+	block := init.genesis.ToBlock()
+	headers := make([]*types.Header, 0)
+
+	for i := 1; i <= 5; i++ {
+		block = buildNextBlock(t, _bor, chain, block, nil, init.genesis.Config.Bor, nil, currentValidators)
+		insertNewBlock(t, chain, block)
+
+		headers = append(headers, block.Header())
+	}
+
+	// The first head is tracked alone, the following ones along with the
+	// blocks skipped since
+	tracker.ProcessHead(headers[1])
+	tracker.ProcessHead(headers[4])
+
+	stats := tracker.Stats()
+	require.Len(t, stats, 1)
+	require.Equal(t, addr, stats[0].Address)
+	require.Equal(t, uint64(4), stats[0].Expected)
+	require.Equal(t, uint64(4), stats[0].InTurn)
+	require.Zero(t, stats[0].Missed)
+	require.Zero(t, stats[0].Reorged)
+
+	// Rewinding the head counts the blocks above as reorged, reverting their
+	// production
+	tracker.ProcessHead(headers[2])
+
+	stats = tracker.Stats()
+	require.Equal(t, uint64(2), stats[0].Reorged)
+	require.Equal(t, uint64(2), stats[0].Expected)
+	require.Equal(t, uint64(2), stats[0].InTurn)
+}
+
 func TestSignerNotFound(t *testing.T) {
 	init := buildEthereumInstance(t, rawdb.NewMemoryDatabase())
 	chain := init.ethereum.BlockChain()
@@ -123,6 +123,12 @@ func buildEthereumInstance(t *testing.T, db ethdb.Database) *initializeData {
 		t.Fatalf("%s", err)
 	}
 
+	return buildEthereumInstanceWithGenesis(t, db, gen)
+}
+
+func buildEthereumInstanceWithGenesis(t *testing.T, db ethdb.Database, gen *core.Genesis) *initializeData {
+	t.Helper()
+
 	ethConf := &eth.Config{
 		Genesis: gen,
 		BorLogs: true,
@@ -131,9 +137,6 @@ func buildEthereumInstance(t *testing.T, db ethdb.Database) *initializeData {
 	ethConf.Genesis.MustCommit(db, trie.NewDatabase(db, trie.HashDefaults))
 
 	ethereum := utils.CreateBorEthereum(ethConf)
-	if err != nil {
-		t.Fatalf("failed to register Ethereum protocol: %v", err)
-	}
 
 	ethConf.Genesis.MustCommit(ethereum.ChainDb(), trie.NewDatabase(ethereum.ChainDb(), trie.HashDefaults))
 